@@ -0,0 +1,56 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+'use strict'
+
+const childProcess = require('child_process')
+
+const stepName = 'abapEnvironmentPullGitRepo'
+const types = {"cfApiEndpoint":"string","cfOrg":"string","cfServiceInstance":"string","cfServiceKey":"string","cfSpace":"string","host":"string","repositoryName":"string"}
+const secrets = ["username","password"]
+
+// inputs of a task are provided by the agent as environment variables INPUT_<NAME>
+function input(name) {
+  return process.env['INPUT_' + name.toUpperCase()] || ''
+}
+
+function list(value) {
+  return value.split(/[,\n]/).map(v => v.trim()).filter(v => v.length > 0)
+}
+
+function convert(value, type) {
+  switch (type) {
+    case 'bool':
+      return value === 'true'
+    case 'int':
+    case 'float64':
+      return Number(value)
+    case '[]string':
+      return list(value)
+    case '[]int':
+      return list(value).map(Number)
+    case 'map[string]interface{}':
+      return JSON.parse(value)
+    default:
+      return value
+  }
+}
+
+// empty inputs are omitted in order not to override the project configuration
+const parameters = {}
+Object.keys(types).forEach(name => {
+  const value = input(name)
+  if (value.length > 0) parameters[name] = convert(value, types[name])
+})
+
+// secrets are not passed on the command line but via environment variables
+const env = Object.assign({}, process.env)
+secrets.forEach(name => {
+  const value = input(name)
+  if (value.length > 0) env['PIPER_' + name] = value
+})
+
+const result = childProcess.spawnSync('piper', [stepName, '--parametersJSON', JSON.stringify(parameters)], { stdio: 'inherit', env: env })
+if (result.error || result.status !== 0) {
+  const reason = result.error ? result.error.message : 'exit code ' + result.status
+  console.log('##vso[task.complete result=Failed;]piper ' + stepName + ' failed: ' + reason)
+  process.exit(result.status || 1)
+}
@@ -1,17 +1,14 @@
 package cmd
 
 import (
-	"bytes"
 	"encoding/json"
-	"io/ioutil"
-	"net/http"
-	"net/http/cookiejar"
-	"strings"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/cloudfoundry"
 	"github.com/SAP/jenkins-library/pkg/command"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/odata"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/pkg/errors"
 )
@@ -24,21 +21,20 @@ func abapEnvironmentPullGitRepo(config abapEnvironmentPullGitRepoOptions, teleme
 	}
 
 	client := piperhttp.Client{}
-	cookieJar, _ := cookiejar.New(nil)
 	clientOptions := piperhttp.ClientOptions{
 		MaxRequestDuration: 30 * time.Second,
-		CookieJar:          cookieJar,
 		Username:           connectionDetails.User,
 		Password:           connectionDetails.Password,
 	}
 	client.SetOptions(clientOptions)
+	odataClient := odata.NewClient(&client)
 
-	var uriConnectionDetails, err = triggerPull(config, connectionDetails, &client)
+	var uriConnectionDetails, err = triggerPull(config, connectionDetails, odataClient)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("Pull failed on the ABAP System")
 	}
 
-	var status, er = pollEntity(config, uriConnectionDetails, &client, 10*time.Second)
+	var status, er = pollEntity(config, uriConnectionDetails, odataClient, 10*time.Second)
 	if er != nil {
 		log.Entry().WithError(er).Fatal("Pull failed on the ABAP System")
 	}
@@ -49,41 +45,31 @@ func abapEnvironmentPullGitRepo(config abapEnvironmentPullGitRepoOptions, teleme
 	return nil
 }
 
-func triggerPull(config abapEnvironmentPullGitRepoOptions, pullConnectionDetails connectionDetailsHTTP, client piperhttp.Sender) (connectionDetailsHTTP, error) {
+func triggerPull(config abapEnvironmentPullGitRepoOptions, pullConnectionDetails connectionDetailsHTTP, client *odata.Client) (connectionDetailsHTTP, error) {
 
 	uriConnectionDetails := pullConnectionDetails
 	uriConnectionDetails.URL = ""
-	pullConnectionDetails.XCsrfToken = "fetch"
 
 	// Loging into the ABAP System - getting the x-csrf-token and cookies
-	var resp, err = getHTTPResponse("HEAD", pullConnectionDetails, nil, client)
+	err := client.FetchCSRFToken(pullConnectionDetails.URL)
 	if err != nil {
-		handleHTTPError(resp, err, "Authentication on the ABAP system failed", pullConnectionDetails)
+		log.Entry().WithError(err).WithField("ABAP Endpoint", pullConnectionDetails.URL).Error("Authentication on the ABAP system failed")
 		return uriConnectionDetails, err
 	}
-	defer resp.Body.Close()
-	log.Entry().WithField("StatusCode", resp.Status).WithField("ABAP Endpoint", pullConnectionDetails.URL).Info("Authentication on the ABAP system successfull")
-	uriConnectionDetails.XCsrfToken = resp.Header.Get("X-Csrf-Token")
-	pullConnectionDetails.XCsrfToken = uriConnectionDetails.XCsrfToken
+	log.Entry().WithField("ABAP Endpoint", pullConnectionDetails.URL).Info("Authentication on the ABAP system successfull")
+	uriConnectionDetails.XCsrfToken = client.CSRFToken()
 
 	// Trigger the Pull of a Repository
-	var jsonBody = []byte(`{"sc_name":"` + config.RepositoryName + `"}`)
-	resp, err = getHTTPResponse("POST", pullConnectionDetails, jsonBody, client)
+	var body abapEntity
+	err = client.Post(pullConnectionDetails.URL, map[string]string{"sc_name": config.RepositoryName}, &body)
 	if err != nil {
-		handleHTTPError(resp, err, "Could not pull the Repository / Software Component "+config.RepositoryName, uriConnectionDetails)
+		log.Entry().WithError(err).WithField("repositoryName", config.RepositoryName).Error("Could not pull the Repository / Software Component")
 		return uriConnectionDetails, err
 	}
-	defer resp.Body.Close()
-	log.Entry().WithField("StatusCode", resp.Status).WithField("repositoryName", config.RepositoryName).Info("Triggered Pull of Repository / Software Component")
+	log.Entry().WithField("repositoryName", config.RepositoryName).Info("Triggered Pull of Repository / Software Component")
 
-	// Parse Response
-	var body abapEntity
-	var abapResp map[string]*json.RawMessage
-	bodyText, err := ioutil.ReadAll(resp.Body)
-	json.Unmarshal(bodyText, &abapResp)
-	json.Unmarshal(*abapResp["d"], &body)
 	if body == (abapEntity{}) {
-		log.Entry().WithField("StatusCode", resp.Status).WithField("repositoryName", config.RepositoryName).Error("Could not pull the Repository / Software Component")
+		log.Entry().WithField("repositoryName", config.RepositoryName).Error("Could not pull the Repository / Software Component")
 		var err = errors.New("Request to ABAP System not successful")
 		return uriConnectionDetails, err
 	}
@@ -91,32 +77,25 @@ func triggerPull(config abapEnvironmentPullGitRepoOptions, pullConnectionDetails
 	return uriConnectionDetails, nil
 }
 
-func pollEntity(config abapEnvironmentPullGitRepoOptions, connectionDetails connectionDetailsHTTP, client piperhttp.Sender, pollIntervall time.Duration) (string, error) {
+func pollEntity(config abapEnvironmentPullGitRepoOptions, connectionDetails connectionDetailsHTTP, client *odata.Client, pollIntervall time.Duration) (string, error) {
 
 	log.Entry().Info("Start polling the status...")
 	var status string = "R"
 
 	for {
-		var resp, err = getHTTPResponse("GET", connectionDetails, nil, client)
+		var body abapEntity
+		err := client.Get(connectionDetails.URL, &body)
 		if err != nil {
-			handleHTTPError(resp, err, "Could not pull the Repository / Software Component "+config.RepositoryName, connectionDetails)
+			log.Entry().WithError(err).WithField("ABAP Endpoint", connectionDetails.URL).Error("Could not pull the Repository / Software Component " + config.RepositoryName)
 			return "", err
 		}
-		defer resp.Body.Close()
-
-		// Parse response
-		var body abapEntity
-		bodyText, _ := ioutil.ReadAll(resp.Body)
-		var abapResp map[string]*json.RawMessage
-		json.Unmarshal(bodyText, &abapResp)
-		json.Unmarshal(*abapResp["d"], &body)
 		if body == (abapEntity{}) {
-			log.Entry().WithField("StatusCode", resp.Status).WithField("repositoryName", config.RepositoryName).Error("Could not pull the Repository / Software Component")
+			log.Entry().WithField("repositoryName", config.RepositoryName).Error("Could not pull the Repository / Software Component")
 			var err = errors.New("Request to ABAP System not successful")
 			return "", err
 		}
 		status = body.Status
-		log.Entry().WithField("StatusCode", resp.Status).Info("Pull Status: " + body.StatusDescr)
+		log.Entry().Info("Pull Status: " + body.StatusDescr)
 		if body.Status != "R" {
 			break
 		}
@@ -138,7 +117,7 @@ func getAbapCommunicationArrangementInfo(config abapEnvironmentPullGitRepoOption
 		connectionDetails.Password = config.Password
 	} else {
 		if config.CfAPIEndpoint == "" || config.CfOrg == "" || config.CfSpace == "" || config.CfServiceInstance == "" || config.CfServiceKey == "" {
-			var err = errors.New("Parameters missing. Please provide EITHER the Host of the ABAP server OR the Cloud Foundry ApiEndpoint, Organization, Space, Service Instance and a corresponding Service Key for the Communication Scenario SAP_COM_0510")
+			var err = log.NewError(log.ErrorConfiguration, "Parameters missing. Please provide EITHER the Host of the ABAP server OR the Cloud Foundry ApiEndpoint, Organization, Space, Service Instance and a corresponding Service Key for the Communication Scenario SAP_COM_0510")
 			return connectionDetails, err
 		}
 		// Url, User and Password should be read from a cf service key
@@ -153,60 +132,39 @@ func getAbapCommunicationArrangementInfo(config abapEnvironmentPullGitRepoOption
 	return connectionDetails, error
 }
 
-func readCfServiceKey(config abapEnvironmentPullGitRepoOptions, c execRunner) (serviceKey, error) {
-
-	var abapServiceKey serviceKey
+func readCfServiceKey(config abapEnvironmentPullGitRepoOptions, c execRunner) (abapServiceKey serviceKey, err error) {
 
 	c.Stderr(log.Entry().Writer())
 
 	// Logging into the Cloud Foundry via CF CLI
 	log.Entry().WithField("cfApiEndpoint", config.CfAPIEndpoint).WithField("cfSpace", config.CfSpace).WithField("cfOrg", config.CfOrg).WithField("User", config.Username).Info("Cloud Foundry parameters: ")
-	cfLoginSlice := []string{"login", "-a", config.CfAPIEndpoint, "-u", config.Username, "-p", config.Password, "-o", config.CfOrg, "-s", config.CfSpace}
-	error := c.RunExecutable("cf", cfLoginSlice...)
-	if error != nil {
+	session, err := cloudfoundry.Login(cloudfoundry.LoginOptions{
+		CfAPIEndpoint: config.CfAPIEndpoint,
+		CfOrg:         config.CfOrg,
+		CfSpace:       config.CfSpace,
+		Username:      config.Username,
+		Password:      config.Password,
+	}, c)
+	if err != nil {
 		log.Entry().Error("Login at cloud foundry failed.")
-		return abapServiceKey, error
+		return abapServiceKey, err
 	}
+	defer func() {
+		if logoutErr := session.Logout(); logoutErr != nil && err == nil {
+			err = logoutErr
+		}
+	}()
 
-	// Reading the Service Key via CF CLI
-	var serviceKeyBytes bytes.Buffer
-	c.Stdout(&serviceKeyBytes)
-	cfReadServiceKeySlice := []string{"service-key", config.CfServiceInstance, config.CfServiceKey}
-	error = c.RunExecutable("cf", cfReadServiceKeySlice...)
-	var serviceKeyJSON string
-	if len(serviceKeyBytes.String()) > 0 {
-		var lines []string = strings.Split(serviceKeyBytes.String(), "\n")
-		serviceKeyJSON = strings.Join(lines[2:], "")
-	}
-	if error != nil {
-		return abapServiceKey, error
+	// Reading the Service Key via the Cloud Controller API
+	credentials, err := session.ReadServiceKey(config.CfServiceInstance, config.CfServiceKey)
+	if err != nil {
+		return abapServiceKey, err
 	}
 	log.Entry().WithField("cfServiceInstance", config.CfServiceInstance).WithField("cfServiceKey", config.CfServiceKey).Info("Read service key for service instance")
-	json.Unmarshal([]byte(serviceKeyJSON), &abapServiceKey)
-	if abapServiceKey == (serviceKey{}) {
+	if err := json.Unmarshal(credentials, &abapServiceKey); err != nil || abapServiceKey == (serviceKey{}) {
 		return abapServiceKey, errors.New("Parsing the service key failed")
 	}
-	return abapServiceKey, error
-}
-
-func getHTTPResponse(requestType string, connectionDetails connectionDetailsHTTP, body []byte, client piperhttp.Sender) (*http.Response, error) {
-
-	header := make(map[string][]string)
-	header["Content-Type"] = []string{"application/json"}
-	header["Accept"] = []string{"application/json"}
-	header["x-csrf-token"] = []string{connectionDetails.XCsrfToken}
-
-	req, err := client.SendRequest(requestType, connectionDetails.URL, bytes.NewBuffer(body), header, nil)
-	return req, err
-}
-
-func handleHTTPError(resp *http.Response, err error, message string, connectionDetails connectionDetailsHTTP) {
-	if resp == nil {
-		log.Entry().WithError(err).WithField("ABAP Endpoint", connectionDetails.URL).Error("Request failed")
-	} else {
-		log.Entry().WithField("StatusCode", resp.Status).Error(message)
-		resp.Body.Close()
-	}
+	return abapServiceKey, nil
 }
 
 type abapEntity struct {
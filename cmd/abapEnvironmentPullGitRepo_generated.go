@@ -43,6 +43,7 @@ Please provide either of the following options:
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			startTime = time.Now()
 			log.SetStepName("abapEnvironmentPullGitRepo")
+			log.RegisterFatalHook("abapEnvironmentPullGitRepo", ".")
 			log.SetVerbose(GeneralConfig.Verbose)
 			return PrepareConfig(cmd, &metadata, "abapEnvironmentPullGitRepo", &stepConfig, config.OpenPiperFile)
 		},
@@ -51,12 +52,21 @@ Please provide either of the following options:
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				if telemetryData.ErrorCode != "0" {
+					telemetryData.ErrorCategory = log.GetErrorCategory().String()
+					telemetryData.ErrorCode = fmt.Sprintf("%v", log.GetErrorCategory().ExitCode())
+				}
 				telemetry.Send(&telemetryData)
 			}
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetry.Initialize(GeneralConfig.NoTelemetry, "abapEnvironmentPullGitRepo")
-			abapEnvironmentPullGitRepo(stepConfig, &telemetryData)
+			log.StartGroup("Execution")
+			err := abapEnvironmentPullGitRepo(stepConfig, &telemetryData)
+			log.EndGroup()
+			if err != nil {
+				log.Entry().WithError(err).Fatal("step execution failed")
+			}
 			telemetryData.ErrorCode = "0"
 		},
 	}
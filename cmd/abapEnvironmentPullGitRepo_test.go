@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/odata"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -38,7 +39,7 @@ func TestTriggerPull(t *testing.T) {
 			Password: "MY_PW",
 			URL:      "https://api.endpoint.com/Entity/",
 		}
-		entityConnection, _ := triggerPull(config, con, client)
+		entityConnection, _ := triggerPull(config, con, odata.NewClient(client))
 		assert.Equal(t, uriExpected, entityConnection.URL)
 		assert.Equal(t, tokenExpected, entityConnection.XCsrfToken)
 	})
@@ -72,7 +73,7 @@ func TestPollEntity(t *testing.T) {
 			URL:        "https://api.endpoint.com/Entity/",
 			XCsrfToken: "MY_TOKEN",
 		}
-		status, _ := pollEntity(config, con, client, 0)
+		status, _ := pollEntity(config, con, odata.NewClient(client), 0)
 		assert.Equal(t, "S", status)
 	})
 
@@ -101,7 +102,7 @@ func TestPollEntity(t *testing.T) {
 			URL:        "https://api.endpoint.com/Entity/",
 			XCsrfToken: "MY_TOKEN",
 		}
-		status, _ := pollEntity(config, con, client, 0)
+		status, _ := pollEntity(config, con, odata.NewClient(client), 0)
 		assert.Equal(t, "E", status)
 	})
 
@@ -121,11 +122,20 @@ func TestGetAbapCommunicationArrangementInfo(t *testing.T) {
 			Password:          "testPassword",
 		}
 
-		execRunner := mock.ExecMockRunner{}
+		execRunner := mock.ExecMockRunner{StdoutReturn: map[string]string{
+			"^cf space testSpace --guid$": "space-guid\n",
+			"^cf curl /v2/spaces/space-guid/service_instances\\?q=name%3AtestInstance$":            `{"next_url": null, "resources": [{"metadata": {"guid": "instance-guid"}, "entity": {"name": "testInstance"}}]}`,
+			"^cf curl /v2/service_instances/instance-guid/service_keys\\?q=name%3AtestServiceKey$": `{"next_url": null, "resources": [{"metadata": {"guid": "key-guid"}, "entity": {"name": "testServiceKey", "credentials": {"url": "https://my.abap.system", "abap": {"username": "abapUser", "password": "abapPassword"}}}}]}`,
+		}}
 
-		getAbapCommunicationArrangementInfo(config, &execRunner)
+		connectionDetails, err := getAbapCommunicationArrangementInfo(config, &execRunner)
+		assert.NoError(t, err)
 		assert.Equal(t, "cf", execRunner.Calls[0].Exec, "Wrong command")
-		assert.Equal(t, []string{"login", "-a", "https://api.endpoint.com", "-u", "testUser", "-p", "testPassword", "-o", "testOrg", "-s", "testSpace"}, execRunner.Calls[0].Params, "Wrong parameters")
+		assert.Equal(t, []string{"login", "-a", "https://api.endpoint.com", "-o", "testOrg", "-s", "testSpace", "-u", "testUser", "-p", "testPassword"}, execRunner.Calls[0].Params, "Wrong parameters")
+		assert.Equal(t, []string{"logout"}, execRunner.Calls[len(execRunner.Calls)-1].Params, "No logout")
+		assert.Equal(t, "https://my.abap.system/sap/opu/odata/sap/MANAGE_GIT_REPOSITORY/Pull", connectionDetails.URL)
+		assert.Equal(t, "abapUser", connectionDetails.User)
+		assert.Equal(t, "abapPassword", connectionDetails.Password)
 	})
 
 	t.Run("Test cf cli command: params missing", func(t *testing.T) {
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/versioning"
+	"github.com/pkg/errors"
+)
+
+// artifactPrepareVersionUtils defines an interface for utility functionality used from external packages,
+// so it can be easily mocked for testing.
+type artifactPrepareVersionUtils interface {
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	getExecRunner() execRunner
+}
+
+type artifactPrepareVersionUtilsBundle struct {
+	piperutils.Files
+	execRunner *command.Command
+}
+
+func (u *artifactPrepareVersionUtilsBundle) getExecRunner() execRunner {
+	if u.execRunner == nil {
+		u.execRunner = &command.Command{}
+		u.execRunner.Stdout(log.Entry().Writer())
+		u.execRunner.Stderr(log.Entry().Writer())
+	}
+	return u.execRunner
+}
+
+// versioningUtils provides the step utils to the versioning package
+type versioningUtils struct {
+	utils artifactPrepareVersionUtils
+}
+
+func (v versioningUtils) FileRead(path string) ([]byte, error) {
+	return v.utils.FileRead(path)
+}
+
+func (v versioningUtils) FileWrite(path string, content []byte, perm os.FileMode) error {
+	return v.utils.FileWrite(path, content, perm)
+}
+
+func (v versioningUtils) Stdout(out io.Writer) {
+	v.utils.getExecRunner().Stdout(out)
+}
+
+func (v versioningUtils) Stderr(err io.Writer) {
+	v.utils.getExecRunner().Stderr(err)
+}
+
+func (v versioningUtils) RunExecutable(e string, p ...string) error {
+	return v.utils.getExecRunner().RunExecutable(e, p...)
+}
+
+func artifactPrepareVersion(config artifactPrepareVersionOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *artifactPrepareVersionCommonPipelineEnvironment) {
+	utils := artifactPrepareVersionUtilsBundle{}
+
+	err := runArtifactPrepareVersion(&config, &utils, commonPipelineEnvironment, time.Now())
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, utils artifactPrepareVersionUtils, commonPipelineEnvironment *artifactPrepareVersionCommonPipelineEnvironment, now time.Time) error {
+	artifact, err := versioning.GetArtifact(config.BuildTool, config.FilePath, &versioning.Options{
+		DockerVersionSource: config.DockerVersionSource,
+		ProjectSettingsFile: config.ProjectSettingsFile,
+		GlobalSettingsFile:  config.GlobalSettingsFile,
+		M2Path:              config.M2Path,
+	}, versioningUtils{utils: utils})
+	if err != nil {
+		return log.WrapError(log.ErrorConfiguration, err)
+	}
+
+	currentVersion, err := artifact.GetVersion()
+	if err != nil {
+		return log.WrapError(log.ErrorConfiguration, err)
+	}
+
+	commitID, err := gitOutput(utils, "rev-parse", "HEAD")
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve the git commit id")
+	}
+
+	if config.CommitVersion {
+		// untracked files are ignored, since only changes to tracked files are committed via git add --update
+		status, err := gitOutput(utils, "status", "--porcelain", "--untracked-files=no")
+		if err != nil {
+			return errors.Wrap(err, "failed to retrieve the git status")
+		}
+		if len(status) > 0 {
+			return log.NewError(log.ErrorConfiguration, "the git working tree is not clean, the version can only be committed for a clean working tree")
+		}
+	}
+
+	newVersion, err := versioning.AutomaticVersion(config.VersioningTemplate, versioning.VersionData{
+		Version:   versioning.BaseVersion(currentVersion),
+		Timestamp: now.UTC().Format(config.TimestampTemplate),
+		CommitID:  commitID,
+	})
+	if err != nil {
+		return log.WrapError(log.ErrorConfiguration, err)
+	}
+
+	log.Entry().Infof("Updating version from %v to %v", currentVersion, newVersion)
+	if err := artifact.SetVersion(newVersion); err != nil {
+		return err
+	}
+
+	if config.CommitVersion {
+		if commitID, err = commitAndTagVersion(config, newVersion, utils); err != nil {
+			return err
+		}
+	}
+
+	commonPipelineEnvironment.artifactVersion = newVersion
+	commonPipelineEnvironment.git.commitID = commitID
+	return nil
+}
+
+// commitAndTagVersion commits the changed descriptors and pushes the tag of the version, it returns the id of the version commit.
+// All changed files are committed since the version may be updated in several descriptors, e.g. in the poms of maven modules,
+// the working tree has been verified to be clean before.
+func commitAndTagVersion(config *artifactPrepareVersionOptions, version string, utils artifactPrepareVersionUtils) (string, error) {
+	execRunner := utils.getExecRunner()
+
+	if err := execRunner.RunExecutable("git", "add", "--update"); err != nil {
+		return "", errors.Wrap(err, "failed to add the changed descriptors to git")
+	}
+	commitParameters := []string{
+		"-c", "user.name=" + config.GitUserName,
+		"-c", "user.email=" + config.GitUserEMail,
+		"commit", "--no-verify", "-m", "update version " + version,
+	}
+	if err := execRunner.RunExecutable("git", commitParameters...); err != nil {
+		return "", errors.Wrap(err, "failed to commit the version")
+	}
+
+	tag := config.TagPrefix + version
+	if err := execRunner.RunExecutable("git", "tag", tag); err != nil {
+		return "", errors.Wrapf(err, "failed to create tag %v", tag)
+	}
+
+	remoteURL, err := gitOutput(utils, "config", "--get", "remote.origin.url")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to retrieve the url of the remote origin")
+	}
+	pushURL, err := gitPushURL(remoteURL, config.Username, config.Password)
+	if err != nil {
+		return "", err
+	}
+	log.Entry().Infof("Pushing tag %v", tag)
+	if err := execRunner.RunExecutable("git", "push", pushURL, "refs/tags/"+tag); err != nil {
+		// the push url is not part of the error since it may contain credentials
+		return "", errors.Errorf("failed to push tag %v", tag)
+	}
+
+	return gitOutput(utils, "rev-parse", "HEAD")
+}
+
+// gitPushURL adds the credentials to an https remote url, other urls are returned unchanged
+func gitPushURL(remoteURL, username, password string) (string, error) {
+	if len(username) == 0 || !strings.HasPrefix(remoteURL, "https://") {
+		return remoteURL, nil
+	}
+	parsedURL, err := url.Parse(remoteURL)
+	if err != nil {
+		return "", log.WrapError(log.ErrorConfiguration, errors.Wrapf(err, "failed to parse the url of the remote origin"))
+	}
+	parsedURL.User = url.UserPassword(username, password)
+	return parsedURL.String(), nil
+}
+
+// gitOutput runs git and returns its trimmed output instead of logging it
+func gitOutput(utils artifactPrepareVersionUtils, parameters ...string) (string, error) {
+	execRunner := utils.getExecRunner()
+	var output bytes.Buffer
+	execRunner.Stdout(&output)
+	err := execRunner.RunExecutable("git", parameters...)
+	execRunner.Stdout(log.Entry().Writer())
+	return strings.TrimSpace(output.String()), err
+}
@@ -0,0 +1,273 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/spf13/cobra"
+)
+
+type artifactPrepareVersionOptions struct {
+	BuildTool           string `json:"buildTool,omitempty"`
+	FilePath            string `json:"filePath,omitempty"`
+	VersioningTemplate  string `json:"versioningTemplate,omitempty"`
+	TimestampTemplate   string `json:"timestampTemplate,omitempty"`
+	DockerVersionSource string `json:"dockerVersionSource,omitempty"`
+	CommitVersion       bool   `json:"commitVersion,omitempty"`
+	TagPrefix           string `json:"tagPrefix,omitempty"`
+	GitUserName         string `json:"gitUserName,omitempty"`
+	GitUserEMail        string `json:"gitUserEMail,omitempty"`
+	Username            string `json:"username,omitempty"`
+	Password            string `json:"password,omitempty"`
+	ProjectSettingsFile string `json:"projectSettingsFile,omitempty"`
+	GlobalSettingsFile  string `json:"globalSettingsFile,omitempty"`
+	M2Path              string `json:"m2Path,omitempty"`
+}
+
+type artifactPrepareVersionCommonPipelineEnvironment struct {
+	artifactVersion string
+	git             struct {
+		commitID string
+	}
+}
+
+func (p *artifactPrepareVersionCommonPipelineEnvironment) persist(path, resourceName string) {
+	content := []struct {
+		category string
+		name     string
+		value    string
+	}{
+		{category: "", name: "artifactVersion", value: p.artifactVersion},
+		{category: "git", name: "commitId", value: p.git.commitID},
+	}
+
+	errCount := 0
+	for _, param := range content {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(param.category, param.name), param.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting piper environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		os.Exit(1)
+	}
+}
+
+// ArtifactPrepareVersionCommand Prepares and potentially updates the artifact's version before building the artifact.
+func ArtifactPrepareVersionCommand() *cobra.Command {
+	metadata := artifactPrepareVersionMetadata()
+	var stepConfig artifactPrepareVersionOptions
+	var startTime time.Time
+	var commonPipelineEnvironment artifactPrepareVersionCommonPipelineEnvironment
+
+	var createArtifactPrepareVersionCmd = &cobra.Command{
+		Use:   "artifactPrepareVersion",
+		Short: "Prepares and potentially updates the artifact's version before building the artifact.",
+		Long: `Prepares and potentially updates the artifact's version before building the artifact.
+
+The version is calculated from the version of the build descriptor via the ` + "`" + `versioningTemplate` + "`" + `,
+by default it is ` + "`" + `<version>-<timestamp>+<commitId>` + "`" + `. A pre-release suffix like ` + "`" + `-SNAPSHOT` + "`" + ` is removed from the version of the descriptor.
+
+The new version is written back to the build descriptor:
+
+* ` + "`" + `docker` + "`" + `: ` + "`" + `LABEL version` + "`" + ` in the ` + "`" + `Dockerfile` + "`" + `, the version is read from the tag of the base image or from an ` + "`" + `ENV` + "`" + ` variable (` + "`" + `dockerVersionSource` + "`" + `)
+* ` + "`" + `golang` + "`" + `: ` + "`" + `VERSION` + "`" + ` file
+* ` + "`" + `maven` + "`" + `: ` + "`" + `pom.xml` + "`" + ` and the poms of its modules via the versions-maven-plugin
+* ` + "`" + `mta` + "`" + `: ` + "`" + `mta.yaml` + "`" + `
+* ` + "`" + `npm` + "`" + `: ` + "`" + `package.json` + "`" + `
+
+With ` + "`" + `commitVersion` + "`" + ` the changed descriptors are committed and tagged with ` + "`" + `<tagPrefix><version>` + "`" + `, the tag is pushed to the ` + "`" + `origin` + "`" + ` remote.
+Only the tag is pushed: the version commit is not pushed to any branch, it is only reachable via the tag.
+The version is written to the ` + "`" + `commonPipelineEnvironment` + "`" + ` as ` + "`" + `artifactVersion` + "`" + `,
+where it is picked up by subsequent steps like ` + "`" + `nexusUpload` + "`" + `, ` + "`" + `sonarExecuteScan` + "`" + ` and ` + "`" + `detectExecuteScan` + "`" + `.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			startTime = time.Now()
+			log.SetStepName("artifactPrepareVersion")
+			log.RegisterFatalHook("artifactPrepareVersion", ".")
+			log.SetVerbose(GeneralConfig.Verbose)
+			return PrepareConfig(cmd, &metadata, "artifactPrepareVersion", &stepConfig, config.OpenPiperFile)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			telemetryData := telemetry.CustomData{}
+			telemetryData.ErrorCode = "1"
+			handler := func() {
+				commonPipelineEnvironment.persist(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
+				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				if telemetryData.ErrorCode != "0" {
+					telemetryData.ErrorCategory = log.GetErrorCategory().String()
+					telemetryData.ErrorCode = fmt.Sprintf("%v", log.GetErrorCategory().ExitCode())
+				}
+				telemetry.Send(&telemetryData)
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetry.Initialize(GeneralConfig.NoTelemetry, "artifactPrepareVersion")
+			log.StartGroup("Execution")
+			artifactPrepareVersion(stepConfig, &telemetryData, &commonPipelineEnvironment)
+			log.EndGroup()
+			telemetryData.ErrorCode = "0"
+		},
+	}
+
+	addArtifactPrepareVersionFlags(createArtifactPrepareVersionCmd, &stepConfig)
+	return createArtifactPrepareVersionCmd
+}
+
+func addArtifactPrepareVersionFlags(cmd *cobra.Command, stepConfig *artifactPrepareVersionOptions) {
+	cmd.Flags().StringVar(&stepConfig.BuildTool, "buildTool", os.Getenv("PIPER_buildTool"), "Defines the tool which is used for building the artifact.")
+	cmd.Flags().StringVar(&stepConfig.FilePath, "filePath", os.Getenv("PIPER_filePath"), "Defines a custom path to the descriptor file. Build tool specific defaults are used (e.g. `pom.xml` for maven, `package.json` for npm).")
+	cmd.Flags().StringVar(&stepConfig.VersioningTemplate, "versioningTemplate", "{{.Version}}-{{.Timestamp}}{{with .CommitID}}+{{.}}{{end}}", "Go template for the new version. It can use `{{.Version}}` (version of the descriptor without pre-release suffix), `{{.Timestamp}}` and `{{.CommitID}}`.")
+	cmd.Flags().StringVar(&stepConfig.TimestampTemplate, "timestampTemplate", "20060102150405", "Go time layout for the timestamp which is part of the version.")
+	cmd.Flags().StringVar(&stepConfig.DockerVersionSource, "dockerVersionSource", "FROM", "For `docker` only: Specifies the source of the version. `FROM` uses the tag of the base image, any other value is the name of an `ENV` variable in the Dockerfile.")
+	cmd.Flags().BoolVar(&stepConfig.CommitVersion, "commitVersion", false, "Defines if the changed descriptors are committed and tagged in git. The tag is pushed to the `origin` remote, the commit is not pushed to any branch.")
+	cmd.Flags().StringVar(&stepConfig.TagPrefix, "tagPrefix", "build_", "Defines the prefix of the git tag.")
+	cmd.Flags().StringVar(&stepConfig.GitUserName, "gitUserName", "Project Piper", "User name used for the version commit.")
+	cmd.Flags().StringVar(&stepConfig.GitUserEMail, "gitUserEMail", "piper@example.com", "E-mail address used for the version commit.")
+	cmd.Flags().StringVar(&stepConfig.Username, "username", os.Getenv("PIPER_username"), "User name for pushing the tag via https.")
+	cmd.Flags().StringVar(&stepConfig.Password, "password", os.Getenv("PIPER_password"), "Password or token for pushing the tag via https.")
+	cmd.Flags().StringVar(&stepConfig.ProjectSettingsFile, "projectSettingsFile", os.Getenv("PIPER_projectSettingsFile"), "Path to the mvn settings file that should be used as project settings file.")
+	cmd.Flags().StringVar(&stepConfig.GlobalSettingsFile, "globalSettingsFile", os.Getenv("PIPER_globalSettingsFile"), "Path to the mvn settings file that should be used as global settings file.")
+	cmd.Flags().StringVar(&stepConfig.M2Path, "m2Path", os.Getenv("PIPER_m2Path"), "Path to the location of the local repository that should be used.")
+
+	cmd.MarkFlagRequired("buildTool")
+	cmd.RegisterFlagCompletionFunc("buildTool", CompleteValues("docker", "golang", "maven", "mta", "npm"))
+}
+
+// retrieve step metadata
+func artifactPrepareVersionMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:    "artifactPrepareVersion",
+			Aliases: []config.Alias{},
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:           "buildTool",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      true,
+						Aliases:        []config.Alias{},
+						PossibleValues: []interface{}{"docker", "golang", "maven", "mta", "npm"},
+					},
+					{
+						Name:        "filePath",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "versioningTemplate",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "timestampTemplate",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "dockerVersionSource",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "commitVersion",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "tagPrefix",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "gitUserName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "gitUserEMail",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "username",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "password",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "projectSettingsFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "maven/projectSettingsFile"}},
+					},
+					{
+						Name:        "globalSettingsFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "maven/globalSettingsFile"}},
+					},
+					{
+						Name:        "m2Path",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "maven/m2Path"}},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -18,22 +18,21 @@ import (
 	"github.com/SAP/jenkins-library/pkg/checkmarx"
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
-	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/bmatcuk/doublestar"
 )
 
-func checkmarxExecuteScan(config checkmarxExecuteScanOptions, telemetryData *telemetry.CustomData, influx *checkmarxExecuteScanInflux) error {
+func checkmarxExecuteScan(config checkmarxExecuteScanOptions, telemetryData *telemetry.CustomData, influx *checkmarxExecuteScanInflux, reports *checkmarxExecuteScanReports) error {
 	client := &piperHttp.Client{}
 	sys, err := checkmarx.NewSystemInstance(client, config.ServerURL, config.Username, config.Password)
 	if err != nil {
 		log.Entry().WithError(err).Fatalf("Failed to create Checkmarx client talking to URL %v", config.ServerURL)
 	}
-	runScan(config, sys, "./", influx)
+	runScan(config, sys, "./", influx, reports)
 	return nil
 }
 
-func runScan(config checkmarxExecuteScanOptions, sys checkmarx.System, workspace string, influx *checkmarxExecuteScanInflux) {
+func runScan(config checkmarxExecuteScanOptions, sys checkmarx.System, workspace string, influx *checkmarxExecuteScanInflux, reports *checkmarxExecuteScanReports) {
 
 	team := loadTeam(sys, config.TeamName, config.TeamID)
 	projectName := config.ProjectName
@@ -46,7 +45,7 @@ func runScan(config checkmarxExecuteScanOptions, sys checkmarx.System, workspace
 		project = createAndConfigureNewProject(sys, projectName, team.ID, config.Preset, config.SourceEncoding)
 	}
 
-	uploadAndScan(config, sys, project, workspace, influx)
+	uploadAndScan(config, sys, project, workspace, influx, reports)
 }
 
 func loadTeam(sys checkmarx.System, teamName, teamID string) checkmarx.Team {
@@ -104,7 +103,7 @@ func zipWorkspaceFiles(workspace, filterPattern string) *os.File {
 	return zipFile
 }
 
-func uploadAndScan(config checkmarxExecuteScanOptions, sys checkmarx.System, project checkmarx.Project, workspace string, influx *checkmarxExecuteScanInflux) {
+func uploadAndScan(config checkmarxExecuteScanOptions, sys checkmarx.System, project checkmarx.Project, workspace string, influx *checkmarxExecuteScanInflux, reports *checkmarxExecuteScanReports) {
 	zipFile := zipWorkspaceFiles(workspace, config.FilterPattern)
 	sourceCodeUploaded := sys.UploadProjectSourceCode(project.ID, zipFile.Name())
 	if sourceCodeUploaded {
@@ -124,13 +123,13 @@ func uploadAndScan(config checkmarxExecuteScanOptions, sys checkmarx.System, pro
 			incremental = false
 		}
 
-		triggerScan(config, sys, project, workspace, incremental, influx)
+		triggerScan(config, sys, project, workspace, incremental, influx, reports)
 	} else {
 		log.Entry().Fatalf("Cannot upload source code for project %v", project.Name)
 	}
 }
 
-func triggerScan(config checkmarxExecuteScanOptions, sys checkmarx.System, project checkmarx.Project, workspace string, incremental bool, influx *checkmarxExecuteScanInflux) {
+func triggerScan(config checkmarxExecuteScanOptions, sys checkmarx.System, project checkmarx.Project, workspace string, incremental bool, influx *checkmarxExecuteScanInflux, reports *checkmarxExecuteScanReports) {
 	projectIsScanning, scan := sys.ScanProject(project.ID, incremental, false, !config.AvoidDuplicateProjectScans)
 	if projectIsScanning {
 		log.Entry().Debugf("Scanning project %v ", project.Name)
@@ -138,12 +137,11 @@ func triggerScan(config checkmarxExecuteScanOptions, sys checkmarx.System, proje
 
 		log.Entry().Debugln("Scan finished")
 
-		var reports []piperutils.Path
 		if config.GeneratePdfReport {
 			pdfReportName := createReportName(workspace, "CxSASTReport_%v.pdf")
 			ok := downloadAndSaveReport(sys, pdfReportName, scan)
 			if ok {
-				reports = append(reports, piperutils.Path{Target: pdfReportName, Mandatory: true})
+				reports.pdfReport = pdfReportName
 			}
 		} else {
 			log.Entry().Debug("Report generation is disabled via configuration")
@@ -151,9 +149,8 @@ func triggerScan(config checkmarxExecuteScanOptions, sys checkmarx.System, proje
 
 		xmlReportName := createReportName(workspace, "CxSASTResults_%v.xml")
 		results := getDetailedResults(sys, xmlReportName, scan.ID)
-		reports = append(reports, piperutils.Path{Target: xmlReportName})
-		links := []piperutils.Path{piperutils.Path{Target: results["DeepLink"].(string), Name: "Checkmarx Web UI"}}
-		piperutils.PersistReportsAndLinks("checkmarxExecuteScan", workspace, reports, links)
+		reports.xmlReport = xmlReportName
+		reports.webUI = results["DeepLink"].(string)
 
 		reportToInflux(results, influx)
 
@@ -164,6 +161,7 @@ func triggerScan(config checkmarxExecuteScanOptions, sys checkmarx.System, proje
 
 		if insecure {
 			if config.VulnerabilityThresholdResult == "FAILURE" {
+				log.SetErrorCategory(log.ErrorCompliance)
 				log.Entry().Fatalln("Checkmarx scan failed, the project is not compliant. For details see the archived report.")
 			}
 			log.Entry().Errorf("Checkmarx scan result set to %v, some results are not meeting defined thresholds. For details see the archived report.", config.VulnerabilityThresholdResult)
@@ -11,6 +11,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/spf13/cobra"
 )
@@ -158,12 +159,34 @@ func (i *checkmarxExecuteScanInflux) persist(path, resourceName string) {
 	}
 }
 
+type checkmarxExecuteScanReports struct {
+	pdfReport string
+	xmlReport string
+	webUI     string
+}
+
+func (r *checkmarxExecuteScanReports) persist(workspace string) {
+	reports := []piperutils.Path{}
+	if len(r.pdfReport) > 0 {
+		reports = append(reports, piperutils.Path{Target: r.pdfReport, Mandatory: true})
+	}
+	if len(r.xmlReport) > 0 {
+		reports = append(reports, piperutils.Path{Target: r.xmlReport, Mandatory: false})
+	}
+	links := []piperutils.Path{}
+	if len(r.webUI) > 0 {
+		links = append(links, piperutils.Path{Name: "Checkmarx Web UI", Target: r.webUI, Mandatory: false, Scope: ""})
+	}
+	piperutils.PersistReportsAndLinks("checkmarxExecuteScan", workspace, reports, links)
+}
+
 // CheckmarxExecuteScanCommand Checkmarx is the recommended tool for security scans of JavaScript, iOS, Swift and Ruby code.
 func CheckmarxExecuteScanCommand() *cobra.Command {
 	metadata := checkmarxExecuteScanMetadata()
 	var stepConfig checkmarxExecuteScanOptions
 	var startTime time.Time
 	var influx checkmarxExecuteScanInflux
+	var reports checkmarxExecuteScanReports
 
 	var createCheckmarxExecuteScanCmd = &cobra.Command{
 		Use:   "checkmarxExecuteScan",
@@ -181,6 +204,7 @@ thresholds instead of ` + "`" + `percentage` + "`" + ` whereas we strongly recom
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			startTime = time.Now()
 			log.SetStepName("checkmarxExecuteScan")
+			log.RegisterFatalHook("checkmarxExecuteScan", ".")
 			log.SetVerbose(GeneralConfig.Verbose)
 			return PrepareConfig(cmd, &metadata, "checkmarxExecuteScan", &stepConfig, config.OpenPiperFile)
 		},
@@ -189,13 +213,23 @@ thresholds instead of ` + "`" + `percentage` + "`" + ` whereas we strongly recom
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				influx.persist(GeneralConfig.EnvRootPath, "influx")
+				reports.persist("./")
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				if telemetryData.ErrorCode != "0" {
+					telemetryData.ErrorCategory = log.GetErrorCategory().String()
+					telemetryData.ErrorCode = fmt.Sprintf("%v", log.GetErrorCategory().ExitCode())
+				}
 				telemetry.Send(&telemetryData)
 			}
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetry.Initialize(GeneralConfig.NoTelemetry, "checkmarxExecuteScan")
-			checkmarxExecuteScan(stepConfig, &telemetryData, &influx)
+			log.StartGroup("Execution")
+			err := checkmarxExecuteScan(stepConfig, &telemetryData, &influx, &reports)
+			log.EndGroup()
+			if err != nil {
+				log.Entry().WithError(err).Fatal("step execution failed")
+			}
 			telemetryData.ErrorCode = "0"
 		},
 	}
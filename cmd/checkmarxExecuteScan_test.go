@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -300,11 +301,14 @@ func TestRunScan(t *testing.T) {
 	defer os.RemoveAll(workspace)
 
 	influx := checkmarxExecuteScanInflux{}
+	reports := checkmarxExecuteScanReports{}
 
-	runScan(options, sys, workspace, &influx)
+	runScan(options, sys, workspace, &influx, &reports)
 	assert.Equal(t, false, sys.isIncremental, "isIncremental has wrong value")
 	assert.Equal(t, false, sys.isPublic, "isPublic has wrong value")
 	assert.Equal(t, true, sys.forceScan, "forceScan has wrong value")
+	assert.True(t, strings.HasPrefix(reports.pdfReport, filepath.Join(workspace, "CxSASTReport_")), "pdfReport has wrong value")
+	assert.True(t, strings.HasPrefix(reports.xmlReport, filepath.Join(workspace, "CxSASTResults_")), "xmlReport has wrong value")
 }
 
 func TestRunScanWOtherCycle(t *testing.T) {
@@ -318,8 +322,9 @@ func TestRunScanWOtherCycle(t *testing.T) {
 	defer os.RemoveAll(workspace)
 
 	influx := checkmarxExecuteScanInflux{}
+	reports := checkmarxExecuteScanReports{}
 
-	runScan(options, sys, workspace, &influx)
+	runScan(options, sys, workspace, &influx, &reports)
 	assert.Equal(t, true, sys.isIncremental, "isIncremental has wrong value")
 	assert.Equal(t, false, sys.isPublic, "isPublic has wrong value")
 	assert.Equal(t, true, sys.forceScan, "forceScan has wrong value")
@@ -336,8 +341,9 @@ func TestRunScanForPullRequest(t *testing.T) {
 	defer os.RemoveAll(workspace)
 
 	influx := checkmarxExecuteScanInflux{}
+	reports := checkmarxExecuteScanReports{}
 
-	runScan(options, sys, workspace, &influx)
+	runScan(options, sys, workspace, &influx, &reports)
 	assert.Equal(t, true, sys.isIncremental, "isIncremental has wrong value")
 	assert.Equal(t, false, sys.isPublic, "isPublic has wrong value")
 	assert.Equal(t, true, sys.forceScan, "forceScan has wrong value")
@@ -354,8 +360,9 @@ func TestRunScanForPullRequestProjectNew(t *testing.T) {
 	defer os.RemoveAll(workspace)
 
 	influx := checkmarxExecuteScanInflux{}
+	reports := checkmarxExecuteScanReports{}
 
-	runScan(options, sys, workspace, &influx)
+	runScan(options, sys, workspace, &influx, &reports)
 	assert.Equal(t, true, sys.isIncremental, "isIncremental has wrong value")
 	assert.Equal(t, false, sys.isPublic, "isPublic has wrong value")
 	assert.Equal(t, true, sys.forceScan, "forceScan has wrong value")
@@ -392,8 +399,9 @@ func TestRunScanHighViolationPercentage(t *testing.T) {
 		defer os.RemoveAll(workspace)
 
 		influx := checkmarxExecuteScanInflux{}
+		reports := checkmarxExecuteScanReports{}
 
-		runScan(options, sys, workspace, &influx)
+		runScan(options, sys, workspace, &influx, &reports)
 		return
 	}
 	cmd := exec.Command(os.Args[0], "-test.run=TestRunScanHighViolationPercentage")
@@ -436,8 +444,9 @@ func TestRunScanHighViolationAbsolute(t *testing.T) {
 		defer os.RemoveAll(workspace)
 
 		influx := checkmarxExecuteScanInflux{}
+		reports := checkmarxExecuteScanReports{}
 
-		runScan(options, sys, workspace, &influx)
+		runScan(options, sys, workspace, &influx, &reports)
 		return
 	}
 	cmd := exec.Command(os.Args[0], "-test.run=TestRunScanHighViolationAbsolute")
@@ -1,10 +1,9 @@
 package cmd
 
 import (
-	"bytes"
 	"fmt"
-	"strings"
 
+	"github.com/SAP/jenkins-library/pkg/cloudfoundry"
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
@@ -18,71 +17,67 @@ func cloudFoundryDeleteService(options cloudFoundryDeleteServiceOptions, telemet
 	c.Stdout(log.Entry().Writer())
 	c.Stderr(log.Entry().Writer())
 
-	var err error
-
-	err = cloudFoundryLogin(options, &c)
-
-	if err == nil && options.CfDeleteServiceKeys == true {
-		err = cloudFoundryDeleteServiceKeys(options, &c)
-	}
-
-	if err == nil {
-		err = cloudFoundryDeleteServiceFunction(options.CfServiceInstance, &c)
-	}
-
-	var logoutErr error
-
-	if err == nil {
-		logoutErr = cloudFoundryLogout(&c)
-		if logoutErr != nil {
-			log.Entry().
-				WithError(logoutErr).
-				Fatal("Error while logging out occured.")
-		}
-	} else if err != nil {
-		logoutErr = cloudFoundryLogout(&c)
-		if logoutErr != nil {
-			log.Entry().
-				WithError(logoutErr).
-				Fatal("Error while logging out occured.")
-		}
+	err := runCloudFoundryDeleteService(options, &c)
+	if err != nil {
 		log.Entry().
 			WithError(err).
 			Fatal("Error occured.")
 	}
 }
 
-func cloudFoundryDeleteServiceKeys(options cloudFoundryDeleteServiceOptions, c execRunner) error {
+func runCloudFoundryDeleteService(options cloudFoundryDeleteServiceOptions, c execRunner) (err error) {
+	session, err := cloudfoundry.Login(cloudfoundry.LoginOptions{
+		CfAPIEndpoint: options.CfAPIEndpoint,
+		CfOrg:         options.CfOrg,
+		CfSpace:       options.CfSpace,
+		Username:      options.Username,
+		Password:      options.Password,
+	}, c)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if logoutErr := session.Logout(); logoutErr != nil && err == nil {
+			err = logoutErr
+		}
+	}()
 
-	log.Entry().Info("Deleting inherent Service Keys")
+	if options.CfDeleteServiceKeys {
+		if err := cloudFoundryDeleteServiceKeys(options, session, c); err != nil {
+			return err
+		}
+	}
 
-	var cfFindServiceKeysScript = []string{"service-keys", options.CfServiceInstance}
+	return cloudFoundryDeleteServiceFunction(options.CfServiceInstance, c)
+}
 
-	var serviceKeyBytes bytes.Buffer
-	c.Stdout(&serviceKeyBytes)
+func cloudFoundryDeleteServiceKeys(options cloudFoundryDeleteServiceOptions, session *cloudfoundry.Session, c execRunner) error {
 
-	err := c.RunExecutable("cf", cfFindServiceKeysScript...)
+	log.Entry().Info("Deleting inherent Service Keys")
 
+	instance, err := session.ServiceInstance(options.CfServiceInstance)
 	if err != nil {
-		return fmt.Errorf("Failed to Delete Service Key, most likely your service doesn't exist: %w", err)
+		return fmt.Errorf("Failed to Delete Service Key: %w", err)
 	}
-
-	if len(serviceKeyBytes.String()) == 0 {
-		log.Entry().Info("No service key could be retrieved for your requested Service")
+	if instance == nil {
+		log.Entry().Info("No service key could be retrieved, the requested Service doesn't exist")
 		return nil
 	}
 
-	var lines []string = strings.Split(serviceKeyBytes.String(), "\n")
-	if len(lines) <= 4 {
+	serviceKeys, err := session.ServiceKeys(instance)
+	if err != nil {
+		return fmt.Errorf("Failed to Delete Service Key: %w", err)
+	}
+	if len(serviceKeys) == 0 {
 		log.Entry().Info("No Service Keys active to be deleted")
 		return nil
 	}
-	var numberOfLines = len(lines)
-	log.Entry().WithField("Number of service keys :", numberOfLines-4).Info("ServiceKey")
-	//Deleting all matched Service Keys for Service
-	for i := 3; i <= numberOfLines-2; i++ {
-		log.Entry().WithField("Deleting Service Key", lines[i]).Info("ServiceKeyDeletion")
-		var cfDeleteServiceKeyScript = []string{"delete-service-key", options.CfServiceInstance, lines[i], "-f"}
+
+	log.Entry().WithField("Number of service keys :", len(serviceKeys)).Info("ServiceKey")
+	//Deleting all Service Keys of the Service
+	for _, serviceKey := range serviceKeys {
+		log.Entry().WithField("Deleting Service Key", serviceKey.Name).Info("ServiceKeyDeletion")
+		var cfDeleteServiceKeyScript = []string{"delete-service-key", options.CfServiceInstance, serviceKey.Name, "-f"}
 		err := c.RunExecutable("cf", cfDeleteServiceKeyScript...)
 		if err != nil {
 			return fmt.Errorf("Failed to Delete Service Key: %w", err)
@@ -92,20 +87,6 @@ func cloudFoundryDeleteServiceKeys(options cloudFoundryDeleteServiceOptions, c e
 	return nil
 }
 
-func cloudFoundryLogin(options cloudFoundryDeleteServiceOptions, c execRunner) error {
-	var cfLoginScript = []string{"login", "-a", options.CfAPIEndpoint, "-o", options.CfOrg, "-s", options.CfSpace, "-u", options.Username, "-p", options.Password}
-
-	log.Entry().WithField("cfAPI:", options.CfAPIEndpoint).WithField("cfOrg", options.CfOrg).WithField("space", options.CfSpace).Info("Logging into Cloud Foundry..")
-
-	err := c.RunExecutable("cf", cfLoginScript...)
-
-	if err != nil {
-		return fmt.Errorf("Failed to login to Cloud Foundry: %w", err)
-	}
-	log.Entry().Info("Logged in successfully to Cloud Foundry..")
-	return nil
-}
-
 func cloudFoundryDeleteServiceFunction(service string, c execRunner) error {
 	var cfdeleteServiceScript = []string{"delete-service", service, "-f"}
 
@@ -119,16 +100,3 @@ func cloudFoundryDeleteServiceFunction(service string, c execRunner) error {
 	log.Entry().Info("Deletion of Service is finished or the Service has never existed")
 	return nil
 }
-
-func cloudFoundryLogout(c execRunner) error {
-	var cfLogoutScript = "logout"
-
-	log.Entry().Info("Logging out of Cloud Foundry")
-
-	err := c.RunExecutable("cf", cfLogoutScript)
-	if err != nil {
-		return fmt.Errorf("Failed to Logout of Cloud Foundry: %w", err)
-	}
-	log.Entry().Info("Logged out successfully")
-	return nil
-}
@@ -36,6 +36,7 @@ func CloudFoundryDeleteServiceCommand() *cobra.Command {
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			startTime = time.Now()
 			log.SetStepName("cloudFoundryDeleteService")
+			log.RegisterFatalHook("cloudFoundryDeleteService", ".")
 			log.SetVerbose(GeneralConfig.Verbose)
 			return PrepareConfig(cmd, &metadata, "cloudFoundryDeleteService", &stepConfig, config.OpenPiperFile)
 		},
@@ -44,12 +45,18 @@ func CloudFoundryDeleteServiceCommand() *cobra.Command {
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				if telemetryData.ErrorCode != "0" {
+					telemetryData.ErrorCategory = log.GetErrorCategory().String()
+					telemetryData.ErrorCode = fmt.Sprintf("%v", log.GetErrorCategory().ExitCode())
+				}
 				telemetry.Send(&telemetryData)
 			}
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetry.Initialize(GeneralConfig.NoTelemetry, "cloudFoundryDeleteService")
+			log.StartGroup("Execution")
 			cloudFoundryDeleteService(stepConfig, &telemetryData)
+			log.EndGroup()
 			telemetryData.ErrorCode = "0"
 		},
 	}
@@ -1,68 +1,79 @@
 package cmd
 
 import (
+	"fmt"
+	"regexp"
+	"testing"
+
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/stretchr/testify/assert"
-	"testing"
 )
 
 func TestCloudFoundryDeleteService(t *testing.T) {
-	execRunner := mock.ExecMockRunner{}
+	options := cloudFoundryDeleteServiceOptions{
+		CfAPIEndpoint:     "https://api.endpoint.com",
+		CfOrg:             "testOrg",
+		CfSpace:           "testSpace",
+		Username:          "testUser",
+		Password:          "testPassword",
+		CfServiceInstance: "testInstance",
+	}
+	serviceInstancePath := "/v2/spaces/space-guid/service_instances?q=name%3AtestInstance"
+	serviceKeysPath := "/v2/service_instances/instance-guid/service_keys"
 
-	t.Run("CF Login: success case", func(t *testing.T) {
-		config := cloudFoundryDeleteServiceOptions{
-			CfAPIEndpoint: "https://api.endpoint.com",
-			CfOrg:         "testOrg",
-			CfSpace:       "testSpace",
-			Username:      "testUser",
-			Password:      "testPassword",
-		}
-		error := cloudFoundryLogin(config, &execRunner)
-		if error == nil {
-			assert.Equal(t, "cf", execRunner.Calls[0].Exec)
-			assert.Equal(t, "login", execRunner.Calls[0].Params[0])
-			assert.Equal(t, "-a", execRunner.Calls[0].Params[1])
-			assert.Equal(t, "https://api.endpoint.com", execRunner.Calls[0].Params[2])
-			assert.Equal(t, "-o", execRunner.Calls[0].Params[3])
-			assert.Equal(t, "testOrg", execRunner.Calls[0].Params[4])
-			assert.Equal(t, "-s", execRunner.Calls[0].Params[5])
-			assert.Equal(t, "testSpace", execRunner.Calls[0].Params[6])
-			assert.Equal(t, "-u", execRunner.Calls[0].Params[7])
-			assert.Equal(t, "testUser", execRunner.Calls[0].Params[8])
-			assert.Equal(t, "-p", execRunner.Calls[0].Params[9])
-			assert.Equal(t, "testPassword", execRunner.Calls[0].Params[10])
-		}
-	})
 	t.Run("CF Delete Service: Success case", func(t *testing.T) {
-		ServiceName := "testInstance"
-		error := cloudFoundryDeleteServiceFunction(ServiceName, &execRunner)
-		if error == nil {
-			assert.Equal(t, "cf", execRunner.Calls[1].Exec)
-			assert.Equal(t, "delete-service", execRunner.Calls[1].Params[0])
-			assert.Equal(t, "testInstance", execRunner.Calls[1].Params[1])
-			assert.Equal(t, "-f", execRunner.Calls[1].Params[2])
-		}
-	})
-	t.Run("CF Logout: Success case", func(t *testing.T) {
-		error := cloudFoundryLogout(&execRunner)
-		if error == nil {
-			assert.Equal(t, "cf", execRunner.Calls[2].Exec)
-			assert.Equal(t, "logout", execRunner.Calls[2].Params[0])
-		}
+		execRunner := mock.ExecMockRunner{}
+
+		err := runCloudFoundryDeleteService(options, &execRunner)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "cf", Params: []string{"login", "-a", "https://api.endpoint.com", "-o", "testOrg", "-s", "testSpace", "-u", "testUser", "-p", "testPassword"}},
+			{Exec: "cf", Params: []string{"delete-service", "testInstance", "-f"}},
+			{Exec: "cf", Params: []string{"logout"}},
+		}, execRunner.Calls)
 	})
+
 	t.Run("CF Delete Service Keys: success case", func(t *testing.T) {
-		config := cloudFoundryDeleteServiceOptions{
-			CfAPIEndpoint:     "https://api.endpoint.com",
-			CfOrg:             "testOrg",
-			CfSpace:           "testSpace",
-			Username:          "testUser",
-			Password:          "testPassword",
-			CfServiceInstance: "testInstance",
-		}
-		error := cloudFoundryDeleteServiceKeys(config, &execRunner)
-		if error == nil {
-			assert.Equal(t, "cf", execRunner.Calls[3].Exec)
-			assert.Equal(t, []string{"service-keys", "testInstance"}, execRunner.Calls[3].Params)
-		}
+		execRunner := mock.ExecMockRunner{StdoutReturn: map[string]string{
+			"^cf space testSpace --guid$":                             "space-guid\n",
+			"^cf curl " + regexp.QuoteMeta(serviceInstancePath) + "$": `{"next_url": null, "resources": [{"metadata": {"guid": "instance-guid"}, "entity": {"name": "testInstance"}}]}`,
+			"^cf curl " + regexp.QuoteMeta(serviceKeysPath) + "$":     `{"next_url": null, "resources": [{"metadata": {"guid": "key1-guid"}, "entity": {"name": "key1"}}, {"metadata": {"guid": "key2-guid"}, "entity": {"name": "my key"}}]}`,
+		}}
+		deleteKeysOptions := options
+		deleteKeysOptions.CfDeleteServiceKeys = true
+
+		err := runCloudFoundryDeleteService(deleteKeysOptions, &execRunner)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "cf", Params: []string{"delete-service-key", "testInstance", "key1", "-f"}},
+			{Exec: "cf", Params: []string{"delete-service-key", "testInstance", "my key", "-f"}},
+			{Exec: "cf", Params: []string{"delete-service", "testInstance", "-f"}},
+			{Exec: "cf", Params: []string{"logout"}},
+		}, execRunner.Calls[4:])
+	})
+
+	t.Run("CF Delete Service Keys: service does not exist", func(t *testing.T) {
+		execRunner := mock.ExecMockRunner{StdoutReturn: map[string]string{
+			"^cf space testSpace --guid$":                             "space-guid\n",
+			"^cf curl " + regexp.QuoteMeta(serviceInstancePath) + "$": `{"next_url": null, "resources": []}`,
+		}}
+		deleteKeysOptions := options
+		deleteKeysOptions.CfDeleteServiceKeys = true
+
+		err := runCloudFoundryDeleteService(deleteKeysOptions, &execRunner)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mock.ExecCall{Exec: "cf", Params: []string{"delete-service", "testInstance", "-f"}}, execRunner.Calls[3])
+	})
+
+	t.Run("CF Delete Service: error case", func(t *testing.T) {
+		execRunner := mock.ExecMockRunner{ShouldFailOnCommand: map[string]error{"^cf delete-service ": fmt.Errorf("not authorized")}}
+
+		err := runCloudFoundryDeleteService(options, &execRunner)
+
+		assert.EqualError(t, err, "Failed to delete Service: not authorized")
+		assert.Equal(t, mock.ExecCall{Exec: "cf", Params: []string{"logout"}}, execRunner.Calls[2])
 	})
 }
@@ -0,0 +1,565 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/cloudfoundry"
+	"github.com/SAP/jenkins-library/pkg/command"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/bmatcuk/doublestar"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+const (
+	cfNativeDeployTool           = "cf_native"
+	mtaDeployPluginTool          = "mtaDeployPlugin"
+	blueGreenDeployType          = "blue-green"
+	blueGreenPluginStrategy      = "plugin"
+	blueGreenRouteSwitchStrategy = "routeSwitch"
+	defaultSmokeTestScript       = "blueGreenCheckScript.sh"
+	// substitutedManifestPrefix is prepended to the file name of manifests with resolved variables
+	substitutedManifestPrefix = ".piper-"
+)
+
+// blueGreenCheckScript is written if the default smoke test script is configured but not available in the workspace,
+// it checks whether the application root returns the expected status code.
+const blueGreenCheckScript = `#!/usr/bin/env bash
+# this is simply testing if the application root returns HTTP STATUS_CODE
+curl -so /dev/null -w '%{response_code}' https://$1 | grep $STATUS_CODE
+`
+
+// cfManifestVariablePattern matches variable references like ((name)) in a manifest
+var cfManifestVariablePattern = regexp.MustCompile(`\(\(([^()]+)\)\)`)
+
+// cfManifest contains the parts of a Cloud Foundry manifest which are relevant for the deployment
+type cfManifest struct {
+	Applications []cfManifestApplication `json:"applications"`
+}
+
+type cfManifestApplication struct {
+	Name    string `json:"name"`
+	NoRoute bool   `json:"no-route"`
+	Routes  []struct {
+		Route string `json:"route"`
+	} `json:"routes"`
+}
+
+// cfRoute is a route split into the parts expected by cf map-route
+type cfRoute struct {
+	host   string
+	domain string
+	path   string
+}
+
+// cloudFoundryDeployApplicationUtils defines an interface for utility functionality used from external packages,
+// so it can be easily mocked for testing.
+type cloudFoundryDeployApplicationUtils interface {
+	FileExists(path string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	Glob(pattern string) ([]string, error)
+	getStatusCode(url string) (int, error)
+	getExecRunner() execRunner
+}
+
+type cloudFoundryDeployApplicationUtilsBundle struct {
+	piperutils.Files
+	httpClient piperhttp.Client
+	execRunner *command.Command
+}
+
+func (u *cloudFoundryDeployApplicationUtilsBundle) Glob(pattern string) ([]string, error) {
+	return doublestar.Glob(pattern)
+}
+
+func (u *cloudFoundryDeployApplicationUtilsBundle) getStatusCode(url string) (int, error) {
+	response, err := u.httpClient.SendRequest("GET", url, nil, nil, nil)
+	if response != nil && response.StatusCode != 0 {
+		if response.Body != nil {
+			response.Body.Close()
+		}
+		// status codes outside of 2xx are reported as error by the client, but are a valid result here
+		return response.StatusCode, nil
+	}
+	return 0, err
+}
+
+func (u *cloudFoundryDeployApplicationUtilsBundle) getExecRunner() execRunner {
+	if u.execRunner == nil {
+		u.execRunner = &command.Command{}
+		u.execRunner.Stdout(log.Entry().Writer())
+		u.execRunner.Stderr(log.Entry().Writer())
+	}
+	return u.execRunner
+}
+
+func cloudFoundryDeployApplication(config cloudFoundryDeployApplicationOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *cloudFoundryDeployApplicationCommonPipelineEnvironment) {
+	utils := cloudFoundryDeployApplicationUtilsBundle{}
+
+	err := runCloudFoundryDeployApplication(&config, &utils, commonPipelineEnvironment)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runCloudFoundryDeployApplication(config *cloudFoundryDeployApplicationOptions, utils cloudFoundryDeployApplicationUtils, commonPipelineEnvironment *cloudFoundryDeployApplicationCommonPipelineEnvironment) (err error) {
+	if config.DeployTool != cfNativeDeployTool && config.DeployTool != mtaDeployPluginTool {
+		return log.NewError(log.ErrorConfiguration, "deploy tool '%v' not supported, possible values are %v and %v", config.DeployTool, cfNativeDeployTool, mtaDeployPluginTool)
+	}
+
+	log.Entry().Infof("Deploying with %v (%v) to org %v, space %v", config.DeployTool, config.DeployType, config.CfOrg, config.CfSpace)
+
+	loginOptions := cloudfoundry.LoginOptions{
+		CfAPIEndpoint: config.CfAPIEndpoint,
+		CfOrg:         config.CfOrg,
+		CfSpace:       config.CfSpace,
+		Username:      config.Username,
+		Password:      config.Password,
+		CfAPIOpts:     strings.Fields(config.APIParameters),
+		CfLoginOpts:   strings.Fields(config.LoginParameters),
+	}
+	session, err := cloudfoundry.Login(loginOptions, utils.getExecRunner())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if logoutErr := session.Logout(); logoutErr != nil && err == nil {
+			err = logoutErr
+		}
+	}()
+
+	if config.DeployTool == mtaDeployPluginTool {
+		return deployMta(config, utils, commonPipelineEnvironment)
+	}
+	return deployCfNative(config, utils, session)
+}
+
+func deployCfNative(config *cloudFoundryDeployApplicationOptions, utils cloudFoundryDeployApplicationUtils, session *cloudfoundry.Session) error {
+	variables, variableOptions, err := cfManifestVariables(config.ManifestVariablesFiles, config.ManifestVariables, utils)
+	if err != nil {
+		return err
+	}
+
+	manifest, manifestContent, err := readCfManifest(config.Manifest, variables, utils)
+	if err != nil {
+		return err
+	}
+
+	deployType := config.DeployType
+	if deployType == blueGreenDeployType && manifest != nil {
+		if len(manifest.Applications) > 1 {
+			return log.NewError(log.ErrorConfiguration, "your manifest contains more than one application, for blue-green deployments the manifest may contain only one application")
+		}
+		if len(manifest.Applications) == 1 && manifest.Applications[0].NoRoute {
+			log.Entry().Warn("Blue-green deployment is not possible for applications without route, using deployment type 'standard' instead")
+			deployType = "standard"
+		}
+	}
+
+	if err := checkCfAppName(config, deployType, manifest); err != nil {
+		return err
+	}
+
+	execRunner := utils.getExecRunner()
+	dockerOptions := []string{}
+	env := []string{}
+	if len(config.DeployDockerImage) > 0 {
+		dockerOptions = append(dockerOptions, "--docker-image", config.DeployDockerImage)
+	}
+	if len(config.DockerUsername) > 0 {
+		dockerOptions = append(dockerOptions, "--docker-username", config.DockerUsername)
+		// the cf CLI reads the password of the Docker registry from the environment
+		env = append(env, "CF_DOCKER_PASSWORD="+config.DockerPassword)
+	}
+
+	if deployType != blueGreenDeployType {
+		deployOptions := []string{"push"}
+		if len(config.AppName) > 0 {
+			deployOptions = append(deployOptions, config.AppName)
+		}
+		deployOptions = append(deployOptions, variableOptions...)
+		if manifest != nil {
+			deployOptions = append(deployOptions, "-f", config.Manifest)
+		}
+		deployOptions = append(deployOptions, dockerOptions...)
+		deployOptions = append(deployOptions, strings.Fields(config.CfNativeDeployParameters)...)
+
+		session.SetEnv(env)
+		if err := execRunner.RunExecutable("cf", deployOptions...); err != nil {
+			return errors.Wrap(err, "failed to push the application")
+		}
+		return nil
+	}
+
+	if config.BlueGreenStrategy == blueGreenRouteSwitchStrategy {
+		session.SetEnv(env)
+		return deployCfBlueGreenRouteSwitch(config, manifest, variableOptions, dockerOptions, utils)
+	}
+
+	env = append(env, fmt.Sprintf("STATUS_CODE=%v", config.SmokeTestStatusCode))
+	session.SetEnv(env)
+	return deployCfBlueGreenPlugin(config, manifest, manifestContent, len(variables) > 0, dockerOptions, utils)
+}
+
+// deployCfBlueGreenPlugin deploys the application via the blue-green deployment plugin, which does not support manifest variables.
+// Therefore the variables are resolved into a copy of the manifest.
+func deployCfBlueGreenPlugin(config *cloudFoundryDeployApplicationOptions, manifest *cfManifest, manifestContent []byte, substitute bool, dockerOptions []string, utils cloudFoundryDeployApplicationUtils) error {
+	smokeTestScript, err := prepareSmokeTestScript(config.SmokeTestScript, utils)
+	if err != nil {
+		return err
+	}
+
+	deployOptions := []string{"blue-green-deploy", config.AppName}
+	if !config.KeepOldInstance {
+		deployOptions = append(deployOptions, "--delete-old-apps")
+	}
+	if manifest != nil {
+		manifestFile := config.Manifest
+		if substitute {
+			manifestFile = filepath.Join(filepath.Dir(config.Manifest), substitutedManifestPrefix+filepath.Base(config.Manifest))
+			if err := utils.FileWrite(manifestFile, manifestContent, 0644); err != nil {
+				return errors.Wrapf(err, "failed to write manifest with resolved variables %v", manifestFile)
+			}
+		}
+		deployOptions = append(deployOptions, "-f", manifestFile)
+	}
+	deployOptions = append(deployOptions, dockerOptions...)
+	deployOptions = append(deployOptions, "--smoke-test", smokeTestScript)
+	deployOptions = append(deployOptions, strings.Fields(config.CfNativeDeployParameters)...)
+
+	execRunner := utils.getExecRunner()
+	if err := execRunner.RunExecutable("cf", deployOptions...); err != nil {
+		return errors.Wrap(err, "blue-green deployment failed")
+	}
+
+	if config.KeepOldInstance {
+		oldApp := config.AppName + "-old"
+		if cfAppExists(oldApp, execRunner) {
+			if err := execRunner.RunExecutable("cf", "stop", oldApp); err != nil {
+				return errors.Wrapf(err, "failed to stop application %v", oldApp)
+			}
+		}
+	}
+	return nil
+}
+
+// deployCfBlueGreenRouteSwitch pushes the new version next to the running application on a temporary route.
+// After a successful smoke test the routes of the manifest are switched to the new version.
+func deployCfBlueGreenRouteSwitch(config *cloudFoundryDeployApplicationOptions, manifest *cfManifest, variableOptions, dockerOptions []string, utils cloudFoundryDeployApplicationUtils) error {
+	if manifest == nil || len(manifest.Applications) == 0 || len(manifest.Applications[0].Routes) == 0 {
+		return log.NewError(log.ErrorConfiguration, "blue-green deployment with route switching requires the routes of the application in the manifest")
+	}
+	routes := []cfRoute{}
+	for _, route := range manifest.Applications[0].Routes {
+		routes = append(routes, parseCfRoute(route.Route))
+	}
+
+	execRunner := utils.getExecRunner()
+	app := config.AppName
+	newApp := app + "-new"
+	oldApp := app + "-old"
+	tempRoute := cfRoute{host: newApp, domain: routes[0].domain}
+	appExists := cfAppExists(app, execRunner)
+
+	deployOptions := []string{"push", newApp}
+	deployOptions = append(deployOptions, variableOptions...)
+	deployOptions = append(deployOptions, "-f", config.Manifest, "--no-route")
+	deployOptions = append(deployOptions, dockerOptions...)
+	deployOptions = append(deployOptions, strings.Fields(config.CfNativeDeployParameters)...)
+	if err := execRunner.RunExecutable("cf", deployOptions...); err != nil {
+		return errors.Wrapf(err, "failed to push application %v", newApp)
+	}
+
+	if err := execRunner.RunExecutable("cf", cfRouteOptions("map-route", newApp, tempRoute)...); err != nil {
+		return errors.Wrapf(err, "failed to map temporary route to application %v", newApp)
+	}
+
+	smokeTestURL := fmt.Sprintf("https://%v.%v", tempRoute.host, tempRoute.domain)
+	statusCode, err := utils.getStatusCode(smokeTestURL)
+	if err != nil || statusCode != config.SmokeTestStatusCode {
+		log.Entry().Warnf("Smoke test of %v failed, removing application %v", smokeTestURL, newApp)
+		if deleteErr := execRunner.RunExecutable("cf", "delete", newApp, "-f"); deleteErr != nil {
+			log.Entry().WithError(deleteErr).Warnf("Failed to delete application %v", newApp)
+		}
+		if deleteErr := execRunner.RunExecutable("cf", "delete-route", tempRoute.domain, "--hostname", tempRoute.host, "-f"); deleteErr != nil {
+			log.Entry().WithError(deleteErr).Warn("Failed to delete temporary route")
+		}
+		if err != nil {
+			return log.WrapError(log.ErrorTest, errors.Wrapf(err, "smoke test of %v failed", smokeTestURL))
+		}
+		return log.NewError(log.ErrorTest, "smoke test of %v failed: expected status code %v, got %v", smokeTestURL, config.SmokeTestStatusCode, statusCode)
+	}
+
+	for _, route := range routes {
+		if err := execRunner.RunExecutable("cf", cfRouteOptions("map-route", newApp, route)...); err != nil {
+			return errors.Wrapf(err, "failed to map route %v.%v to application %v", route.host, route.domain, newApp)
+		}
+	}
+
+	if appExists {
+		for _, route := range routes {
+			if err := execRunner.RunExecutable("cf", cfRouteOptions("unmap-route", app, route)...); err != nil {
+				return errors.Wrapf(err, "failed to unmap route %v.%v from application %v", route.host, route.domain, app)
+			}
+		}
+		if cfAppExists(oldApp, execRunner) {
+			if err := execRunner.RunExecutable("cf", "delete", oldApp, "-f"); err != nil {
+				return errors.Wrapf(err, "failed to delete application %v", oldApp)
+			}
+		}
+		if err := execRunner.RunExecutable("cf", "rename", app, oldApp); err != nil {
+			return errors.Wrapf(err, "failed to rename application %v", app)
+		}
+	}
+
+	if err := execRunner.RunExecutable("cf", cfRouteOptions("unmap-route", newApp, tempRoute)...); err != nil {
+		return errors.Wrap(err, "failed to unmap temporary route")
+	}
+	if err := execRunner.RunExecutable("cf", "delete-route", tempRoute.domain, "--hostname", tempRoute.host, "-f"); err != nil {
+		return errors.Wrap(err, "failed to delete temporary route")
+	}
+	if err := execRunner.RunExecutable("cf", "rename", newApp, app); err != nil {
+		return errors.Wrapf(err, "failed to rename application %v", newApp)
+	}
+
+	if !appExists {
+		return nil
+	}
+	if config.KeepOldInstance {
+		if err := execRunner.RunExecutable("cf", "stop", oldApp); err != nil {
+			return errors.Wrapf(err, "failed to stop application %v", oldApp)
+		}
+		return nil
+	}
+	if err := execRunner.RunExecutable("cf", "delete", oldApp, "-f"); err != nil {
+		return errors.Wrapf(err, "failed to delete application %v", oldApp)
+	}
+	return nil
+}
+
+func deployMta(config *cloudFoundryDeployApplicationOptions, utils cloudFoundryDeployApplicationUtils, commonPipelineEnvironment *cloudFoundryDeployApplicationCommonPipelineEnvironment) error {
+	mtaPath, err := findMtar(config.MtaPath, utils)
+	if err != nil {
+		return err
+	}
+
+	deployCommand := "deploy"
+	deployParameters := strings.Fields(config.MtaDeployParameters)
+	if config.DeployType == blueGreenDeployType {
+		deployCommand = "bg-deploy"
+		if !piperutils.ContainsString(deployParameters, "--no-confirm") {
+			deployParameters = append(deployParameters, "--no-confirm")
+		}
+	}
+
+	deployOptions := append([]string{deployCommand, mtaPath}, deployParameters...)
+	if len(config.MtaExtensionDescriptor) > 0 {
+		deployOptions = append(deployOptions, "-e", strings.TrimPrefix(config.MtaExtensionDescriptor, "-e "))
+	}
+
+	log.Entry().Infof("Deploying MTA %v", mtaPath)
+
+	// the output is needed to retrieve the ID of the operation
+	var deployLog bytes.Buffer
+	execRunner := utils.getExecRunner()
+	execRunner.Stdout(io.MultiWriter(log.Entry().Writer(), &deployLog))
+	deployErr := execRunner.RunExecutable("cf", deployOptions...)
+	execRunner.Stdout(log.Entry().Writer())
+
+	operationID := retrieveOperationID(deployLog.String(), config.OperationIDLogPattern)
+	commonPipelineEnvironment.cloudFoundry.operationID = operationID
+
+	if deployErr != nil {
+		if len(operationID) > 0 {
+			log.Entry().Infof("Aborting operation %v", operationID)
+			if err := execRunner.RunExecutable("cf", "deploy", "-i", operationID, "-a", "abort"); err != nil {
+				log.Entry().WithError(err).Warnf("Failed to abort operation %v", operationID)
+			}
+		}
+		return errors.Wrapf(deployErr, "failed to deploy MTA %v", mtaPath)
+	}
+	return nil
+}
+
+// cfManifestFileUtils provides the file access needed for resolving manifest variables
+type cfManifestFileUtils interface {
+	FileExists(path string) (bool, error)
+	FileRead(path string) ([]byte, error)
+}
+
+// cfManifestVariables collects the variables of the existing variables files and the configured key=value variables,
+// as well as the corresponding options for cf push
+func cfManifestVariables(variablesFiles, manifestVariables []string, utils cfManifestFileUtils) (map[string]string, []string, error) {
+	variables := map[string]string{}
+	options := []string{}
+
+	for _, variablesFile := range variablesFiles {
+		exists, err := utils.FileExists(variablesFile)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to check for variables file %v", variablesFile)
+		}
+		if !exists {
+			log.Entry().Warnf("Skipping not existing variables file %v", variablesFile)
+			continue
+		}
+		content, err := utils.FileRead(variablesFile)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read variables file %v", variablesFile)
+		}
+		fileVariables := map[string]interface{}{}
+		if err := yaml.Unmarshal(content, &fileVariables); err != nil {
+			return nil, nil, log.WrapError(log.ErrorConfiguration, errors.Wrapf(err, "failed to parse variables file %v", variablesFile))
+		}
+		for name, value := range fileVariables {
+			variables[name] = fmt.Sprint(value)
+		}
+		options = append(options, "--vars-file", variablesFile)
+	}
+
+	for _, variable := range manifestVariables {
+		parts := strings.SplitN(variable, "=", 2)
+		if len(parts) != 2 {
+			return nil, nil, log.NewError(log.ErrorConfiguration, "manifest variable '%v' is invalid, expected format is key=value", variable)
+		}
+		variables[parts[0]] = parts[1]
+		options = append(options, "--var", variable)
+	}
+	return variables, options, nil
+}
+
+// readCfManifest returns the manifest with resolved variables, or nil if the manifest does not exist
+func readCfManifest(manifestFile string, variables map[string]string, utils cloudFoundryDeployApplicationUtils) (*cfManifest, []byte, error) {
+	if len(manifestFile) == 0 {
+		return nil, nil, nil
+	}
+	exists, err := utils.FileExists(manifestFile)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to check for manifest %v", manifestFile)
+	}
+	if !exists {
+		return nil, nil, nil
+	}
+	content, err := utils.FileRead(manifestFile)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to read manifest %v", manifestFile)
+	}
+	content = substituteCfManifestVariables(content, variables)
+
+	var manifest cfManifest
+	if err := yaml.Unmarshal(content, &manifest); err != nil {
+		return nil, nil, log.WrapError(log.ErrorConfiguration, errors.Wrapf(err, "failed to parse manifest %v", manifestFile))
+	}
+	return &manifest, content, nil
+}
+
+// substituteCfManifestVariables replaces the references of known variables, unknown references are kept
+func substituteCfManifestVariables(content []byte, variables map[string]string) []byte {
+	return cfManifestVariablePattern.ReplaceAllFunc(content, func(reference []byte) []byte {
+		name := strings.TrimSpace(string(cfManifestVariablePattern.FindSubmatch(reference)[1]))
+		if value, ok := variables[name]; ok {
+			return []byte(value)
+		}
+		return reference
+	})
+}
+
+func checkCfAppName(config *cloudFoundryDeployApplicationOptions, deployType string, manifest *cfManifest) error {
+	if len(config.AppName) > 0 {
+		return nil
+	}
+	if deployType == blueGreenDeployType {
+		return log.NewError(log.ErrorConfiguration, "blue-green deployments require the app name to be configured via appName")
+	}
+	if len(config.DeployDockerImage) > 0 {
+		return log.NewError(log.ErrorConfiguration, "deployments of Docker images require the app name to be configured via appName")
+	}
+	if manifest == nil {
+		return log.NewError(log.ErrorConfiguration, "no manifest file %v found", config.Manifest)
+	}
+	if len(manifest.Applications) == 0 || len(manifest.Applications[0].Name) == 0 {
+		return log.NewError(log.ErrorConfiguration, "no appName available in manifest %v", config.Manifest)
+	}
+	return nil
+}
+
+// prepareSmokeTestScript writes the default smoke test script if it is not part of the workspace and returns the absolute path of the script
+func prepareSmokeTestScript(smokeTestScript string, utils cloudFoundryDeployApplicationUtils) (string, error) {
+	script, err := filepath.Abs(smokeTestScript)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve path of smoke test script %v", smokeTestScript)
+	}
+	exists, err := utils.FileExists(script)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to check for smoke test script %v", smokeTestScript)
+	}
+	if exists {
+		return script, nil
+	}
+	if smokeTestScript != defaultSmokeTestScript {
+		return "", log.NewError(log.ErrorConfiguration, "smoke test script %v not found", smokeTestScript)
+	}
+	if err := utils.FileWrite(script, []byte(blueGreenCheckScript), 0755); err != nil {
+		return "", errors.Wrapf(err, "failed to write smoke test script %v", script)
+	}
+	return script, nil
+}
+
+func findMtar(mtaPath string, utils cloudFoundryDeployApplicationUtils) (string, error) {
+	if len(mtaPath) > 0 {
+		return mtaPath, nil
+	}
+	mtarFiles, err := utils.Glob("**/*.mtar")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to search for *.mtar files")
+	}
+	if len(mtarFiles) > 1 {
+		sort.Strings(mtarFiles)
+		return "", log.NewError(log.ErrorConfiguration, "found multiple *.mtar files, please specify the file via mtaPath: %v", mtarFiles)
+	}
+	if len(mtarFiles) == 0 {
+		return "", log.NewError(log.ErrorConfiguration, "no *.mtar file found")
+	}
+	return mtarFiles[0], nil
+}
+
+func cfAppExists(app string, execRunner execRunner) bool {
+	return execRunner.RunExecutable("cf", "app", app) == nil
+}
+
+// parseCfRoute splits a route like host.domain.com/path
+func parseCfRoute(route string) cfRoute {
+	result := cfRoute{}
+	if i := strings.Index(route, "/"); i >= 0 {
+		result.path = route[i:]
+		route = route[:i]
+	}
+	parts := strings.SplitN(route, ".", 2)
+	if len(parts) == 2 {
+		result.host, result.domain = parts[0], parts[1]
+	} else {
+		result.domain = route
+	}
+	return result
+}
+
+func cfRouteOptions(command, app string, route cfRoute) []string {
+	options := []string{command, app, route.domain}
+	if len(route.host) > 0 {
+		options = append(options, "--hostname", route.host)
+	}
+	if len(route.path) > 0 {
+		options = append(options, "--path", route.path)
+	}
+	return options
+}
@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudFoundryDeployApplicationCommand(t *testing.T) {
+
+	testCmd := CloudFoundryDeployApplicationCommand()
+
+	// only high level testing performed - details are tested in step generation procudure
+	assert.Equal(t, "cloudFoundryDeployApplication", testCmd.Use, "command name incorrect")
+
+}
@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type cloudFoundryDeployApplicationMockUtils struct {
+	mock.FileSystemMock
+	statusCodes map[string]int
+	execRunner  mock.ExecMockRunner
+}
+
+func newCloudFoundryDeployApplicationMockUtils() cloudFoundryDeployApplicationMockUtils {
+	return cloudFoundryDeployApplicationMockUtils{FileSystemMock: mock.NewFileSystemMock(), statusCodes: map[string]int{}}
+}
+
+func (m *cloudFoundryDeployApplicationMockUtils) getStatusCode(url string) (int, error) {
+	statusCode, ok := m.statusCodes[url]
+	if !ok {
+		return 0, fmt.Errorf("connection refused")
+	}
+	return statusCode, nil
+}
+
+func (m *cloudFoundryDeployApplicationMockUtils) getExecRunner() execRunner {
+	return &m.execRunner
+}
+
+func defaultCloudFoundryDeployApplicationOptions() cloudFoundryDeployApplicationOptions {
+	return cloudFoundryDeployApplicationOptions{
+		CfAPIEndpoint:          "https://api.endpoint.com",
+		CfOrg:                  "testOrg",
+		CfSpace:                "testSpace",
+		Username:               "testUser",
+		Password:               "testPassword",
+		Manifest:               "manifest.yml",
+		ManifestVariablesFiles: []string{"manifest-variables.yml"},
+		DeployTool:             "cf_native",
+		DeployType:             "standard",
+		BlueGreenStrategy:      "plugin",
+		SmokeTestScript:        "blueGreenCheckScript.sh",
+		SmokeTestStatusCode:    200,
+		MtaDeployParameters:    "-f",
+		OperationIDLogPattern:  `^.*cf (?:bg-)?deploy -i (\S+) -a.*$`,
+	}
+}
+
+var cfDeployLoginCall = mock.ExecCall{Exec: "cf", Params: []string{"login", "-a", "https://api.endpoint.com", "-o", "testOrg", "-s", "testSpace", "-u", "testUser", "-p", "testPassword"}}
+var cfDeployLogoutCall = mock.ExecCall{Exec: "cf", Params: []string{"logout"}}
+
+const cfDeployTestManifest = `applications:
+- name: myApp
+  routes:
+  - route: ((host)).cfapps.eu10.hana.ondemand.com
+  - route: myApp.custom.domain.com/api
+`
+
+func TestRunCloudFoundryDeployApplication(t *testing.T) {
+	t.Run("standard deployment", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		utils.Files["manifest.yml"] = []byte("applications:\n- name: myApp\n")
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.APIParameters = "--skip-ssl-validation"
+		config.LoginParameters = "--origin ldap"
+		config.CfNativeDeployParameters = "--no-start"
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "cf", Params: []string{"api", "https://api.endpoint.com", "--skip-ssl-validation"}},
+			{Exec: "cf", Params: []string{"login", "-a", "https://api.endpoint.com", "-o", "testOrg", "-s", "testSpace", "-u", "testUser", "-p", "testPassword", "--origin", "ldap"}},
+			{Exec: "cf", Params: []string{"push", "-f", "manifest.yml", "--no-start"}},
+			cfDeployLogoutCall,
+		}, utils.execRunner.Calls)
+	})
+
+	t.Run("manifest variables", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		utils.Files["manifest.yml"] = []byte(cfDeployTestManifest)
+		utils.Files["manifest-variables.yml"] = []byte("host: myHost\n")
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.AppName = "myApp"
+		config.ManifestVariablesFiles = []string{"manifest-variables.yml", "missing-variables.yml"}
+		config.ManifestVariables = []string{"instances=2"}
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, mock.ExecCall{Exec: "cf", Params: []string{"push", "myApp", "--vars-file", "manifest-variables.yml", "--var", "instances=2", "-f", "manifest.yml"}}, utils.execRunner.Calls[1])
+	})
+
+	t.Run("invalid manifest variable", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.ManifestVariables = []string{"instances"}
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "manifest variable 'instances' is invalid, expected format is key=value")
+		assert.Equal(t, cfDeployLogoutCall, utils.execRunner.Calls[len(utils.execRunner.Calls)-1])
+	})
+
+	t.Run("docker image", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.AppName = "myApp"
+		config.DeployDockerImage = "my.registry.io/image:1.0"
+		config.DockerUsername = "dockerUser"
+		config.DockerPassword = "dockerPassword"
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, mock.ExecCall{Exec: "cf", Params: []string{"push", "myApp", "--docker-image", "my.registry.io/image:1.0", "--docker-username", "dockerUser"}}, utils.execRunner.Calls[1])
+		assert.Contains(t, utils.execRunner.Env, "CF_DOCKER_PASSWORD=dockerPassword")
+	})
+
+	t.Run("unsupported deploy tool", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.DeployTool = "unknown"
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "deploy tool 'unknown' not supported, possible values are cf_native and mtaDeployPlugin")
+		assert.Empty(t, utils.execRunner.Calls)
+	})
+
+	t.Run("logout failure", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		utils.Files["manifest.yml"] = []byte("applications:\n- name: myApp\n")
+		utils.execRunner.ShouldFailOnCommand = map[string]error{"^cf logout": fmt.Errorf("logout failed")}
+		config := defaultCloudFoundryDeployApplicationOptions()
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to logout of Cloud Foundry: logout failed")
+	})
+}
+
+func TestCloudFoundryDeployApplicationBlueGreen(t *testing.T) {
+	smokeTestScript, _ := filepath.Abs("blueGreenCheckScript.sh")
+
+	t.Run("plugin with resolved manifest variables", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		utils.Files["manifest.yml"] = []byte(cfDeployTestManifest)
+		utils.Files["manifest-variables.yml"] = []byte("host: myHost\n")
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.AppName = "myApp"
+		config.DeployType = "blue-green"
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			cfDeployLoginCall,
+			{Exec: "cf", Params: []string{"blue-green-deploy", "myApp", "--delete-old-apps", "-f", ".piper-manifest.yml", "--smoke-test", smokeTestScript}},
+			cfDeployLogoutCall,
+		}, utils.execRunner.Calls)
+		assert.Contains(t, string(utils.Files[".piper-manifest.yml"]), "route: myHost.cfapps.eu10.hana.ondemand.com")
+		assert.Equal(t, blueGreenCheckScript, string(utils.Files[smokeTestScript]))
+		assert.Contains(t, utils.execRunner.Env, "STATUS_CODE=200")
+	})
+
+	t.Run("plugin keeping the old instance", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		utils.Files["manifest.yml"] = []byte("applications:\n- name: myApp\n")
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.AppName = "myApp"
+		config.DeployType = "blue-green"
+		config.KeepOldInstance = true
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			cfDeployLoginCall,
+			{Exec: "cf", Params: []string{"blue-green-deploy", "myApp", "-f", "manifest.yml", "--smoke-test", smokeTestScript}},
+			{Exec: "cf", Params: []string{"app", "myApp-old"}},
+			{Exec: "cf", Params: []string{"stop", "myApp-old"}},
+			cfDeployLogoutCall,
+		}, utils.execRunner.Calls)
+	})
+
+	t.Run("fallback to standard for applications without route", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		utils.Files["manifest.yml"] = []byte("applications:\n- name: myApp\n  no-route: true\n")
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.AppName = "myApp"
+		config.DeployType = "blue-green"
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, mock.ExecCall{Exec: "cf", Params: []string{"push", "myApp", "-f", "manifest.yml"}}, utils.execRunner.Calls[1])
+	})
+
+	t.Run("multiple applications", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		utils.Files["manifest.yml"] = []byte("applications:\n- name: myApp\n- name: otherApp\n")
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.AppName = "myApp"
+		config.DeployType = "blue-green"
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "your manifest contains more than one application, for blue-green deployments the manifest may contain only one application")
+	})
+
+	t.Run("missing app name", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		utils.Files["manifest.yml"] = []byte("applications:\n- name: myApp\n")
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.DeployType = "blue-green"
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "blue-green deployments require the app name to be configured via appName")
+	})
+
+	t.Run("route switch", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		utils.Files["manifest.yml"] = []byte(cfDeployTestManifest)
+		utils.Files["manifest-variables.yml"] = []byte("host: myHost\n")
+		utils.statusCodes["https://myApp-new.cfapps.eu10.hana.ondemand.com"] = 200
+		utils.execRunner.ShouldFailOnCommand = map[string]error{"^cf app myApp-old$": fmt.Errorf("not found")}
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.AppName = "myApp"
+		config.DeployType = "blue-green"
+		config.BlueGreenStrategy = "routeSwitch"
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			cfDeployLoginCall,
+			{Exec: "cf", Params: []string{"app", "myApp"}},
+			{Exec: "cf", Params: []string{"push", "myApp-new", "--vars-file", "manifest-variables.yml", "-f", "manifest.yml", "--no-route"}},
+			{Exec: "cf", Params: []string{"map-route", "myApp-new", "cfapps.eu10.hana.ondemand.com", "--hostname", "myApp-new"}},
+			{Exec: "cf", Params: []string{"map-route", "myApp-new", "cfapps.eu10.hana.ondemand.com", "--hostname", "myHost"}},
+			{Exec: "cf", Params: []string{"map-route", "myApp-new", "custom.domain.com", "--hostname", "myApp", "--path", "/api"}},
+			{Exec: "cf", Params: []string{"unmap-route", "myApp", "cfapps.eu10.hana.ondemand.com", "--hostname", "myHost"}},
+			{Exec: "cf", Params: []string{"unmap-route", "myApp", "custom.domain.com", "--hostname", "myApp", "--path", "/api"}},
+			{Exec: "cf", Params: []string{"app", "myApp-old"}},
+			{Exec: "cf", Params: []string{"rename", "myApp", "myApp-old"}},
+			{Exec: "cf", Params: []string{"unmap-route", "myApp-new", "cfapps.eu10.hana.ondemand.com", "--hostname", "myApp-new"}},
+			{Exec: "cf", Params: []string{"delete-route", "cfapps.eu10.hana.ondemand.com", "--hostname", "myApp-new", "-f"}},
+			{Exec: "cf", Params: []string{"rename", "myApp-new", "myApp"}},
+			{Exec: "cf", Params: []string{"delete", "myApp-old", "-f"}},
+			cfDeployLogoutCall,
+		}, utils.execRunner.Calls)
+	})
+
+	t.Run("route switch with failing smoke test", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		utils.Files["manifest.yml"] = []byte(cfDeployTestManifest)
+		utils.Files["manifest-variables.yml"] = []byte("host: myHost\n")
+		utils.statusCodes["https://myApp-new.cfapps.eu10.hana.ondemand.com"] = 503
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.AppName = "myApp"
+		config.DeployType = "blue-green"
+		config.BlueGreenStrategy = "routeSwitch"
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "smoke test of https://myApp-new.cfapps.eu10.hana.ondemand.com failed: expected status code 200, got 503")
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "cf", Params: []string{"delete", "myApp-new", "-f"}},
+			{Exec: "cf", Params: []string{"delete-route", "cfapps.eu10.hana.ondemand.com", "--hostname", "myApp-new", "-f"}},
+			cfDeployLogoutCall,
+		}, utils.execRunner.Calls[4:])
+	})
+}
+
+func TestCloudFoundryDeployApplicationMta(t *testing.T) {
+	t.Run("deploy found mtar", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		utils.Files["target/my.mtar"] = []byte("mtar")
+		utils.execRunner.StdoutReturn = map[string]string{"^cf deploy": "Use \"cf deploy -i 1234 -a abort\" to abort the process\n"}
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.DeployTool = "mtaDeployPlugin"
+		config.MtaExtensionDescriptor = "prod.mtaext"
+		cpe := cloudFoundryDeployApplicationCommonPipelineEnvironment{}
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cpe)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mock.ExecCall{Exec: "cf", Params: []string{"deploy", "target/my.mtar", "-f", "-e", "prod.mtaext"}}, utils.execRunner.Calls[1])
+		assert.Equal(t, "1234", cpe.cloudFoundry.operationID)
+	})
+
+	t.Run("blue-green deployment", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.DeployTool = "mtaDeployPlugin"
+		config.DeployType = "blue-green"
+		config.MtaPath = "my.mtar"
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, mock.ExecCall{Exec: "cf", Params: []string{"bg-deploy", "my.mtar", "-f", "--no-confirm"}}, utils.execRunner.Calls[1])
+	})
+
+	t.Run("abort failed deployment", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		utils.execRunner.StdoutReturn = map[string]string{"^cf deploy my.mtar": "Use \"cf deploy -i 1234 -a abort\" to abort the process\n"}
+		utils.execRunner.ShouldFailOnCommand = map[string]error{"^cf deploy my.mtar": fmt.Errorf("deployment failed")}
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.DeployTool = "mtaDeployPlugin"
+		config.MtaPath = "my.mtar"
+		cpe := cloudFoundryDeployApplicationCommonPipelineEnvironment{}
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cpe)
+
+		assert.EqualError(t, err, "failed to deploy MTA my.mtar: deployment failed")
+		assert.Equal(t, mock.ExecCall{Exec: "cf", Params: []string{"deploy", "-i", "1234", "-a", "abort"}}, utils.execRunner.Calls[2])
+		assert.Equal(t, "1234", cpe.cloudFoundry.operationID)
+	})
+
+	t.Run("no mtar", func(t *testing.T) {
+		utils := newCloudFoundryDeployApplicationMockUtils()
+		config := defaultCloudFoundryDeployApplicationOptions()
+		config.DeployTool = "mtaDeployPlugin"
+
+		err := runCloudFoundryDeployApplication(&config, &utils, &cloudFoundryDeployApplicationCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "no *.mtar file found")
+	})
+}
+
+func TestParseCfRoute(t *testing.T) {
+	assert.Equal(t, cfRoute{host: "myApp", domain: "cfapps.sap.hana.ondemand.com"}, parseCfRoute("myApp.cfapps.sap.hana.ondemand.com"))
+	assert.Equal(t, cfRoute{host: "myApp", domain: "custom.com", path: "/api/v1"}, parseCfRoute("myApp.custom.com/api/v1"))
+	assert.Equal(t, cfRoute{domain: "localhost"}, parseCfRoute("localhost"))
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/cloudfoundry"
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// cfServiceManifest is a service manifest in the format of the Create-Service-Push plugin
+type cfServiceManifest struct {
+	Services []cfServiceDefinition `json:"create-services"`
+}
+
+type cfServiceDefinition struct {
+	Name string `json:"name"`
+	// Service is the service offering, Broker is supported for compatibility with the Create-Service-Push plugin
+	Service string `json:"service"`
+	Broker  string `json:"broker"`
+	Plan    string `json:"plan"`
+	// Parameters are either a JSON string or a map
+	Parameters  interface{} `json:"parameters"`
+	Tags        string      `json:"tags"`
+	ServiceKeys []string    `json:"service-keys"`
+}
+
+// cloudFoundryProvisionServicesUtils defines an interface for utility functionality used from external packages,
+// so it can be easily mocked for testing.
+type cloudFoundryProvisionServicesUtils interface {
+	FileExists(path string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	getExecRunner() execRunner
+}
+
+type cloudFoundryProvisionServicesUtilsBundle struct {
+	piperutils.Files
+	execRunner *command.Command
+}
+
+func (u *cloudFoundryProvisionServicesUtilsBundle) getExecRunner() execRunner {
+	if u.execRunner == nil {
+		u.execRunner = &command.Command{}
+		u.execRunner.Stdout(log.Entry().Writer())
+		u.execRunner.Stderr(log.Entry().Writer())
+	}
+	return u.execRunner
+}
+
+func cloudFoundryProvisionServices(config cloudFoundryProvisionServicesOptions, telemetryData *telemetry.CustomData) {
+	utils := cloudFoundryProvisionServicesUtilsBundle{}
+
+	err := runCloudFoundryProvisionServices(&config, &utils, 10*time.Second)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runCloudFoundryProvisionServices(config *cloudFoundryProvisionServicesOptions, utils cloudFoundryProvisionServicesUtils, pollInterval time.Duration) (err error) {
+	exists, err := utils.FileExists(config.ServiceManifest)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check for service manifest %v", config.ServiceManifest)
+	}
+	if !exists {
+		log.Entry().Warnf("Service manifest %v not found, no services are created", config.ServiceManifest)
+		return nil
+	}
+
+	services, err := readCfServiceManifest(config, utils)
+	if err != nil {
+		return err
+	}
+
+	loginOptions := cloudfoundry.LoginOptions{
+		CfAPIEndpoint: config.CfAPIEndpoint,
+		CfOrg:         config.CfOrg,
+		CfSpace:       config.CfSpace,
+		Username:      config.Username,
+		Password:      config.Password,
+	}
+	session, err := cloudfoundry.Login(loginOptions, utils.getExecRunner())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if logoutErr := session.Logout(); logoutErr != nil && err == nil {
+			err = logoutErr
+		}
+	}()
+
+	timeout := time.Duration(config.Timeout) * time.Second
+	for _, service := range services {
+		if err := createOrUpdateCfService(service, session, utils.getExecRunner()); err != nil {
+			return err
+		}
+		instance, err := waitForCfService(service.Name, session, timeout, pollInterval)
+		if err != nil {
+			return err
+		}
+		for _, serviceKey := range service.ServiceKeys {
+			if err := createCfServiceKey(instance, serviceKey, session, utils.getExecRunner()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readCfServiceManifest reads the service manifest and resolves the variable references
+func readCfServiceManifest(config *cloudFoundryProvisionServicesOptions, utils cloudFoundryProvisionServicesUtils) ([]cfServiceDefinition, error) {
+	variables, _, err := cfManifestVariables(config.ManifestVariablesFiles, config.ManifestVariables, utils)
+	if err != nil {
+		return nil, err
+	}
+	content, err := utils.FileRead(config.ServiceManifest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read service manifest %v", config.ServiceManifest)
+	}
+	content = substituteCfManifestVariables(content, variables)
+	if cfManifestVariablePattern.Match(content) {
+		return nil, log.NewError(log.ErrorConfiguration, "service manifest %v contains unresolved variables", config.ServiceManifest)
+	}
+
+	var manifest cfServiceManifest
+	if err := yaml.Unmarshal(content, &manifest); err != nil {
+		return nil, log.WrapError(log.ErrorConfiguration, errors.Wrapf(err, "failed to parse service manifest %v", config.ServiceManifest))
+	}
+	for i, service := range manifest.Services {
+		if len(service.Service) == 0 {
+			manifest.Services[i].Service = service.Broker
+		}
+		if len(service.Name) == 0 || len(manifest.Services[i].Service) == 0 || len(service.Plan) == 0 {
+			return nil, log.NewError(log.ErrorConfiguration, "service %v of service manifest %v requires name, service and plan", i+1, config.ServiceManifest)
+		}
+	}
+	return manifest.Services, nil
+}
+
+// createOrUpdateCfService creates the service if it does not exist, otherwise the plan, parameters and tags of the service are updated
+func createOrUpdateCfService(service cfServiceDefinition, session *cloudfoundry.Session, execRunner execRunner) error {
+	parameters, err := cfServiceParameters(service)
+	if err != nil {
+		return err
+	}
+	options := []string{}
+	if len(parameters) > 0 {
+		options = append(options, "-c", parameters)
+	}
+	if len(service.Tags) > 0 {
+		options = append(options, "-t", service.Tags)
+	}
+
+	instance, err := session.ServiceInstance(service.Name)
+	if err != nil {
+		return err
+	}
+	if instance == nil {
+		log.Entry().Infof("Creating service %v (%v, plan %v)", service.Name, service.Service, service.Plan)
+		if err := execRunner.RunExecutable("cf", append([]string{"create-service", service.Service, service.Plan, service.Name}, options...)...); err != nil {
+			return errors.Wrapf(err, "failed to create service %v", service.Name)
+		}
+		return nil
+	}
+
+	log.Entry().Infof("Updating existing service %v (plan %v)", service.Name, service.Plan)
+	if err := execRunner.RunExecutable("cf", append([]string{"update-service", service.Name, "-p", service.Plan}, options...)...); err != nil {
+		return errors.Wrapf(err, "failed to update service %v", service.Name)
+	}
+	return nil
+}
+
+func cfServiceParameters(service cfServiceDefinition) (string, error) {
+	switch parameters := service.Parameters.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return parameters, nil
+	default:
+		parametersJSON, err := json.Marshal(parameters)
+		if err != nil {
+			return "", log.WrapError(log.ErrorConfiguration, errors.Wrapf(err, "invalid parameters of service %v", service.Name))
+		}
+		return string(parametersJSON), nil
+	}
+}
+
+// waitForCfService polls the service until its last operation is no longer in progress
+func waitForCfService(serviceName string, session *cloudfoundry.Session, timeout, pollInterval time.Duration) (*cloudfoundry.ServiceInstance, error) {
+	var waited time.Duration
+	for {
+		instance, err := session.ServiceInstance(serviceName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to retrieve the status of service %v", serviceName)
+		}
+		if instance == nil {
+			return nil, log.NewError(log.ErrorInfrastructure, "service %v not found", serviceName)
+		}
+		operation := instance.LastOperation
+		if operation.Failed() {
+			return nil, log.NewError(log.ErrorInfrastructure, "%v of service %v failed: %v", operation.Type, serviceName, operation.Description)
+		}
+		if !operation.InProgress() {
+			log.Entry().Infof("Service %v is ready", serviceName)
+			return instance, nil
+		}
+		if waited >= timeout {
+			return nil, log.NewError(log.ErrorInfrastructure, "timeout while waiting for %v of service %v", operation.Type, serviceName)
+		}
+		log.Entry().Infof("Waiting for %v of service %v", operation.Type, serviceName)
+		time.Sleep(pollInterval)
+		waited += pollInterval
+	}
+}
+
+func createCfServiceKey(instance *cloudfoundry.ServiceInstance, serviceKey string, session *cloudfoundry.Session, execRunner execRunner) error {
+	existingKey, err := session.ServiceKey(instance, serviceKey)
+	if err != nil {
+		return err
+	}
+	if existingKey != nil {
+		log.Entry().Infof("Service key %v of service %v already exists", serviceKey, instance.Name)
+		return nil
+	}
+	log.Entry().Infof("Creating service key %v of service %v", serviceKey, instance.Name)
+	if err := execRunner.RunExecutable("cf", "create-service-key", instance.Name, serviceKey); err != nil {
+		return errors.Wrapf(err, "failed to create service key %v of service %v", serviceKey, instance.Name)
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudFoundryProvisionServicesCommand(t *testing.T) {
+
+	testCmd := CloudFoundryProvisionServicesCommand()
+
+	// only high level testing performed - details are tested in step generation procudure
+	assert.Equal(t, "cloudFoundryProvisionServices", testCmd.Use, "command name incorrect")
+
+}
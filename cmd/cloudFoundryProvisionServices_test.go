@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+// cfServiceMockRunner returns the next of the serviceInstances for each request of the service instance
+type cfServiceMockRunner struct {
+	mock.ExecMockRunner
+	stdout           io.Writer
+	serviceInstances []string
+}
+
+func (m *cfServiceMockRunner) Stdout(out io.Writer) {
+	m.stdout = out
+	m.ExecMockRunner.Stdout(out)
+}
+
+func (m *cfServiceMockRunner) RunExecutable(e string, p ...string) error {
+	if err := m.ExecMockRunner.RunExecutable(e, p...); err != nil {
+		return err
+	}
+	if len(p) < 2 || p[0] != "curl" || !strings.Contains(p[1], "/service_instances?") {
+		return nil
+	}
+	if len(m.serviceInstances) == 0 {
+		return fmt.Errorf("unexpected request of service instance")
+	}
+	m.stdout.Write([]byte(m.serviceInstances[0]))
+	m.serviceInstances = m.serviceInstances[1:]
+	return nil
+}
+
+type cloudFoundryProvisionServicesMockUtils struct {
+	mock.FileSystemMock
+	execRunner cfServiceMockRunner
+}
+
+func newCloudFoundryProvisionServicesMockUtils() cloudFoundryProvisionServicesMockUtils {
+	return cloudFoundryProvisionServicesMockUtils{FileSystemMock: mock.NewFileSystemMock()}
+}
+
+func (m *cloudFoundryProvisionServicesMockUtils) getExecRunner() execRunner {
+	return &m.execRunner
+}
+
+// cfServiceInstanceResponse returns the response for the service instance, an empty state simulates a missing service
+func cfServiceInstanceResponse(operationType, state string) string {
+	if len(state) == 0 {
+		return `{"next_url": null, "resources": []}`
+	}
+	return fmt.Sprintf(`{"next_url": null, "resources": [{"metadata": {"guid": "db-guid"}, "entity": {"name": "my-db", "last_operation": {"type": "%v", "state": "%v", "description": "broker error"}}}]}`, operationType, state)
+}
+
+const (
+	cfTestServiceInstancePath = "/v2/spaces/space-guid/service_instances?q=name%3Amy-db"
+	cfTestServiceKeyPath      = "/v2/service_instances/db-guid/service_keys?q=name%3Amy-db-key"
+)
+
+func newCloudFoundryProvisionServicesTestUtils(serviceKeys string, serviceInstances ...string) cloudFoundryProvisionServicesMockUtils {
+	utils := newCloudFoundryProvisionServicesMockUtils()
+	utils.execRunner.serviceInstances = serviceInstances
+	utils.execRunner.StdoutReturn = map[string]string{
+		"^cf space testSpace --guid$":                              "space-guid\n",
+		"^cf curl " + regexp.QuoteMeta(cfTestServiceKeyPath) + "$": serviceKeys,
+	}
+	return utils
+}
+
+func defaultCloudFoundryProvisionServicesOptions() cloudFoundryProvisionServicesOptions {
+	return cloudFoundryProvisionServicesOptions{
+		CfAPIEndpoint:          "https://api.endpoint.com",
+		CfOrg:                  "testOrg",
+		CfSpace:                "testSpace",
+		Username:               "testUser",
+		Password:               "testPassword",
+		ServiceManifest:        "service-manifest.yml",
+		ManifestVariablesFiles: []string{"manifest-variables.yml"},
+		Timeout:                900,
+	}
+}
+
+const cfTestServiceManifest = `create-services:
+- name: my-db
+  broker: postgresql
+  plan: ((plan))
+  parameters: '{"version": "11"}'
+  tags: database, production
+  service-keys:
+  - my-db-key
+`
+
+func TestRunCloudFoundryProvisionServices(t *testing.T) {
+	noServiceKeys := `{"next_url": null, "resources": []}`
+	serviceKeyResponse := `{"next_url": null, "resources": [{"metadata": {"guid": "key-guid"}, "entity": {"name": "my-db-key", "credentials": {}}}]}`
+
+	t.Run("create service with service key", func(t *testing.T) {
+		utils := newCloudFoundryProvisionServicesTestUtils(noServiceKeys, cfServiceInstanceResponse("", ""), cfServiceInstanceResponse("create", "in progress"), cfServiceInstanceResponse("create", "succeeded"))
+		utils.Files["service-manifest.yml"] = []byte(cfTestServiceManifest)
+		utils.Files["manifest-variables.yml"] = []byte("plan: small\n")
+		config := defaultCloudFoundryProvisionServicesOptions()
+
+		err := runCloudFoundryProvisionServices(&config, &utils, time.Microsecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "cf", Params: []string{"login", "-a", "https://api.endpoint.com", "-o", "testOrg", "-s", "testSpace", "-u", "testUser", "-p", "testPassword"}},
+			{Exec: "cf", Params: []string{"space", "testSpace", "--guid"}},
+			{Exec: "cf", Params: []string{"curl", cfTestServiceInstancePath}},
+			{Exec: "cf", Params: []string{"create-service", "postgresql", "small", "my-db", "-c", `{"version": "11"}`, "-t", "database, production"}},
+			{Exec: "cf", Params: []string{"curl", cfTestServiceInstancePath}},
+			{Exec: "cf", Params: []string{"curl", cfTestServiceInstancePath}},
+			{Exec: "cf", Params: []string{"curl", cfTestServiceKeyPath}},
+			{Exec: "cf", Params: []string{"create-service-key", "my-db", "my-db-key"}},
+			{Exec: "cf", Params: []string{"logout"}},
+		}, utils.execRunner.Calls)
+	})
+
+	t.Run("update existing service", func(t *testing.T) {
+		utils := newCloudFoundryProvisionServicesTestUtils(noServiceKeys, cfServiceInstanceResponse("create", "succeeded"), cfServiceInstanceResponse("update", "succeeded"))
+		utils.Files["service-manifest.yml"] = []byte("create-services:\n- name: my-db\n  service: postgresql\n  plan: large\n  parameters:\n    version: 12\n")
+		config := defaultCloudFoundryProvisionServicesOptions()
+
+		err := runCloudFoundryProvisionServices(&config, &utils, time.Microsecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mock.ExecCall{Exec: "cf", Params: []string{"update-service", "my-db", "-p", "large", "-c", `{"version":12}`}}, utils.execRunner.Calls[3])
+		assert.Len(t, utils.execRunner.Calls, 6)
+	})
+
+	t.Run("existing service key", func(t *testing.T) {
+		utils := newCloudFoundryProvisionServicesTestUtils(serviceKeyResponse, cfServiceInstanceResponse("create", "succeeded"), cfServiceInstanceResponse("update", "succeeded"))
+		utils.Files["service-manifest.yml"] = []byte(cfTestServiceManifest)
+		config := defaultCloudFoundryProvisionServicesOptions()
+		config.ManifestVariables = []string{"plan=small"}
+
+		err := runCloudFoundryProvisionServices(&config, &utils, time.Microsecond)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mock.ExecCall{Exec: "cf", Params: []string{"curl", cfTestServiceKeyPath}}, utils.execRunner.Calls[5])
+		assert.Equal(t, mock.ExecCall{Exec: "cf", Params: []string{"logout"}}, utils.execRunner.Calls[6])
+	})
+
+	t.Run("failed provisioning", func(t *testing.T) {
+		utils := newCloudFoundryProvisionServicesTestUtils(noServiceKeys, cfServiceInstanceResponse("", ""), cfServiceInstanceResponse("create", "failed"))
+		utils.Files["service-manifest.yml"] = []byte(cfTestServiceManifest)
+		config := defaultCloudFoundryProvisionServicesOptions()
+		config.ManifestVariables = []string{"plan=small"}
+
+		err := runCloudFoundryProvisionServices(&config, &utils, time.Microsecond)
+
+		assert.EqualError(t, err, "create of service my-db failed: broker error")
+		assert.Equal(t, mock.ExecCall{Exec: "cf", Params: []string{"logout"}}, utils.execRunner.Calls[len(utils.execRunner.Calls)-1])
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		utils := newCloudFoundryProvisionServicesTestUtils(noServiceKeys, cfServiceInstanceResponse("", ""), cfServiceInstanceResponse("create", "in progress"))
+		utils.Files["service-manifest.yml"] = []byte(cfTestServiceManifest)
+		config := defaultCloudFoundryProvisionServicesOptions()
+		config.ManifestVariables = []string{"plan=small"}
+		config.Timeout = 0
+
+		err := runCloudFoundryProvisionServices(&config, &utils, time.Second)
+
+		assert.EqualError(t, err, "timeout while waiting for create of service my-db")
+	})
+
+	t.Run("unresolved variables", func(t *testing.T) {
+		utils := newCloudFoundryProvisionServicesMockUtils()
+		utils.Files["service-manifest.yml"] = []byte(cfTestServiceManifest)
+		config := defaultCloudFoundryProvisionServicesOptions()
+
+		err := runCloudFoundryProvisionServices(&config, &utils, time.Microsecond)
+
+		assert.EqualError(t, err, "service manifest service-manifest.yml contains unresolved variables")
+		assert.Empty(t, utils.execRunner.Calls)
+	})
+
+	t.Run("incomplete service definition", func(t *testing.T) {
+		utils := newCloudFoundryProvisionServicesMockUtils()
+		utils.Files["service-manifest.yml"] = []byte("create-services:\n- name: my-db\n  plan: small\n")
+		config := defaultCloudFoundryProvisionServicesOptions()
+
+		err := runCloudFoundryProvisionServices(&config, &utils, time.Microsecond)
+
+		assert.EqualError(t, err, "service 1 of service manifest service-manifest.yml requires name, service and plan")
+	})
+
+	t.Run("missing service manifest", func(t *testing.T) {
+		utils := newCloudFoundryProvisionServicesMockUtils()
+		config := defaultCloudFoundryProvisionServicesOptions()
+
+		err := runCloudFoundryProvisionServices(&config, &utils, time.Microsecond)
+
+		assert.NoError(t, err)
+		assert.Empty(t, utils.execRunner.Calls)
+	})
+}
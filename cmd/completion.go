@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// CompletionCommand is the command for creating the shell completion script of the piper binary
+func CompletionCommand() *cobra.Command {
+	var createCompletionCmd = &cobra.Command{
+		Use:   "completion [bash|zsh|fish]",
+		Short: "Generates the shell completion script for the piper binary",
+		Long: `Generates the shell completion script for the piper binary.
+
+Besides commands and flags the completion also contains the possible values of step parameters
+as well as the step names for getConfig --stepName.
+
+To load the completion in the current shell:
+
+  Bash: source <(piper completion bash)
+  Zsh:  source <(piper completion zsh)
+  Fish: piper completion fish | source
+
+To load the completion for each session write the script to the completion directory of your shell, e.g.
+
+  Bash: piper completion bash > /etc/bash_completion.d/piper
+  Zsh:  piper completion zsh > "${fpath[1]}/_piper"
+  Fish: piper completion fish > ~/.config/fish/completions/piper.fish
+`,
+		DisableFlagsInUseLine: true,
+		ValidArgs:             []string{"bash", "zsh", "fish"},
+		Args:                  cobra.ExactValidArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return generateCompletion(cmd.Root(), args[0], cmd.OutOrStdout())
+		},
+	}
+	return createCompletionCmd
+}
+
+func generateCompletion(root *cobra.Command, shell string, out io.Writer) error {
+	switch shell {
+	case "bash":
+		return root.GenBashCompletion(out)
+	case "zsh":
+		return root.GenZshCompletion(out)
+	case "fish":
+		return root.GenFishCompletion(out, true)
+	default:
+		return fmt.Errorf("shell '%v' not supported, possible shells are bash, zsh and fish", shell)
+	}
+}
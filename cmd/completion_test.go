@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateCompletion(t *testing.T) {
+	testRootCmd := &cobra.Command{Use: "piper"}
+	testRootCmd.AddCommand(&cobra.Command{Use: "testStep", Run: func(_ *cobra.Command, _ []string) {}})
+
+	t.Run("bash", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, generateCompletion(testRootCmd, "bash", &out))
+		assert.Contains(t, out.String(), "# bash completion for piper")
+	})
+
+	t.Run("zsh", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, generateCompletion(testRootCmd, "zsh", &out))
+		assert.Contains(t, out.String(), "#compdef _piper piper")
+	})
+
+	t.Run("fish", func(t *testing.T) {
+		var out bytes.Buffer
+		assert.NoError(t, generateCompletion(testRootCmd, "fish", &out))
+		assert.Contains(t, out.String(), "complete -c piper")
+	})
+
+	t.Run("unsupported shell", func(t *testing.T) {
+		var out bytes.Buffer
+		err := generateCompletion(testRootCmd, "powershell", &out)
+		assert.EqualError(t, err, "shell 'powershell' not supported, possible shells are bash, zsh and fish")
+	})
+}
+
+func TestCompletionCommand(t *testing.T) {
+	cmd := CompletionCommand()
+	assert.Equal(t, []string{"bash", "zsh", "fish"}, cmd.ValidArgs)
+	assert.Error(t, cmd.Args(cmd, []string{}), "shell is mandatory")
+	assert.Error(t, cmd.Args(cmd, []string{"tcsh"}), "only valid shells are accepted")
+	assert.NoError(t, cmd.Args(cmd, []string{"fish"}))
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type cpeCommandOptions struct {
+	file      string   //file used for export and import, stdout/stdin if not set
+	resources []string //resources considered for export
+	resource  string   //resource used for get and set
+	json      bool     //value of set is JSON
+}
+
+var cpeOptions cpeCommandOptions
+
+// CpeCommand is the entry command for handling the Common Pipeline Environment
+func CpeCommand() *cobra.Command {
+	var cpeCmd = &cobra.Command{
+		Use:   "cpe",
+		Short: "Reads and writes the Common Pipeline Environment.",
+		Long: `Reads and writes the Common Pipeline Environment (CPE) which is stored below the envRootPath.
+The CPE can be exported to and imported from one JSON document, e.g. to transfer it between agents or stages.`,
+	}
+
+	cpeCmd.AddCommand(cpeExportCommand())
+	cpeCmd.AddCommand(cpeImportCommand())
+	cpeCmd.AddCommand(cpeGetCommand())
+	cpeCmd.AddCommand(cpeSetCommand())
+	return cpeCmd
+}
+
+func cpeExportCommand() *cobra.Command {
+	var exportCmd = &cobra.Command{
+		Use:   "export",
+		Short: "Exports the Common Pipeline Environment into one JSON document.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			out := cmd.OutOrStdout()
+			if len(cpeOptions.file) > 0 {
+				file, err := os.Create(cpeOptions.file)
+				if err != nil {
+					return errors.Wrapf(err, "failed to create file %v", cpeOptions.file)
+				}
+				defer file.Close()
+				out = file
+			}
+			return exportCPE(GeneralConfig.EnvRootPath, cpeOptions.resources, out)
+		},
+	}
+
+	exportCmd.Flags().StringVar(&cpeOptions.file, "file", "", "File the environment is written to, defaults to stdout")
+	exportCmd.Flags().StringSliceVar(&cpeOptions.resources, "resources", []string{"commonPipelineEnvironment", "influx"}, "Resources which are exported")
+	return exportCmd
+}
+
+func cpeImportCommand() *cobra.Command {
+	var importCmd = &cobra.Command{
+		Use:   "import",
+		Short: "Imports the Common Pipeline Environment from a JSON document created by 'piper cpe export'.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			in := cmd.InOrStdin()
+			if len(cpeOptions.file) > 0 {
+				file, err := os.Open(cpeOptions.file)
+				if err != nil {
+					return errors.Wrapf(err, "failed to open file %v", cpeOptions.file)
+				}
+				defer file.Close()
+				in = file
+			}
+			return importCPE(GeneralConfig.EnvRootPath, in)
+		},
+	}
+
+	importCmd.Flags().StringVar(&cpeOptions.file, "file", "", "File the environment is read from, defaults to stdin")
+	return importCmd
+}
+
+func cpeGetCommand() *cobra.Command {
+	var getCmd = &cobra.Command{
+		Use:   "get <key>",
+		Short: "Prints a single value of the Common Pipeline Environment, e.g. 'git/commitId'.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !piperenv.IsRelativePath(cpeOptions.resource) {
+				return fmt.Errorf("invalid resource name '%v'", cpeOptions.resource)
+			}
+			if !piperenv.IsRelativePath(args[0]) {
+				return fmt.Errorf("invalid key '%v'", args[0])
+			}
+			value := piperenv.GetResourceParameter(GeneralConfig.EnvRootPath, cpeOptions.resource, filepath.FromSlash(args[0]))
+			if len(value) == 0 {
+				return fmt.Errorf("no value available for '%v'", args[0])
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), value)
+			return nil
+		},
+	}
+
+	getCmd.Flags().StringVar(&cpeOptions.resource, "resource", "commonPipelineEnvironment", "Resource the value is read from")
+	return getCmd
+}
+
+func cpeSetCommand() *cobra.Command {
+	var setCmd = &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Sets a single value of the Common Pipeline Environment, e.g. 'custom/myValue'.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return setCPEValue(GeneralConfig.EnvRootPath, cpeOptions.resource, args[0], args[1], cpeOptions.json)
+		},
+	}
+
+	setCmd.Flags().StringVar(&cpeOptions.resource, "resource", "commonPipelineEnvironment", "Resource the value is written to")
+	setCmd.Flags().BoolVar(&cpeOptions.json, "json", false, "Defines if the value is a JSON object or array")
+	return setCmd
+}
+
+func exportCPE(path string, resources []string, out io.Writer) error {
+	document := map[string]map[string]interface{}{}
+	for _, resource := range resources {
+		values, err := piperenv.ExportResource(path, resource)
+		if err != nil {
+			return err
+		}
+		document[resource] = values
+	}
+
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal environment")
+	}
+	_, err = fmt.Fprintln(out, string(content))
+	return err
+}
+
+func importCPE(path string, in io.Reader) error {
+	content, err := ioutil.ReadAll(in)
+	if err != nil {
+		return errors.Wrap(err, "failed to read environment")
+	}
+
+	document := map[string]map[string]interface{}{}
+	if err := json.Unmarshal(content, &document); err != nil {
+		return errors.Wrap(err, "failed to parse environment")
+	}
+
+	for resource, values := range document {
+		if err := piperenv.ImportResource(path, resource, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setCPEValue(path, resource, key, value string, isJSON bool) error {
+	if !piperenv.IsRelativePath(resource) {
+		return fmt.Errorf("invalid resource name '%v'", resource)
+	}
+	if !piperenv.IsRelativePath(key) {
+		return fmt.Errorf("invalid key '%v'", key)
+	}
+	paramName := filepath.FromSlash(key)
+	if !isJSON {
+		return piperenv.SetResourceParameter(path, resource, paramName, value)
+	}
+
+	var jsonValue interface{}
+	if err := json.Unmarshal([]byte(value), &jsonValue); err != nil {
+		return errors.Wrapf(err, "value of '%v' is no valid JSON", key)
+	}
+	return piperenv.SetResourceParameterJSON(path, resource, paramName, jsonValue)
+}
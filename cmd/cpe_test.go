@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCpeCommand(t *testing.T) {
+	cmd := CpeCommand()
+
+	assert.Equal(t, "cpe", cmd.Use)
+	subCommands := []string{}
+	for _, c := range cmd.Commands() {
+		subCommands = append(subCommands, c.Name())
+	}
+	assert.ElementsMatch(t, []string{"export", "import", "get", "set"}, subCommands)
+}
+
+func TestExportImportCPE(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal("Failed to create temporary directory")
+	}
+	// clean up tmp dir
+	defer os.RemoveAll(dir)
+
+	piperenv.SetResourceParameter(dir, "commonPipelineEnvironment", "git/commitId", "abc")
+	piperenv.SetResourceParameterJSON(dir, "commonPipelineEnvironment", "custom/list", []string{"a"})
+	piperenv.SetResourceParameter(dir, "influx", "step_data/fields/build", "true")
+
+	var export bytes.Buffer
+	err = exportCPE(dir, []string{"commonPipelineEnvironment", "influx"}, &export)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"commonPipelineEnvironment": {"git/commitId": "abc", "custom/list": ["a"]},
+		"influx": {"step_data/fields/build": "true"}
+	}`, export.String())
+
+	target, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal("Failed to create temporary directory")
+	}
+	// clean up tmp dir
+	defer os.RemoveAll(target)
+
+	err = importCPE(target, &export)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", piperenv.GetResourceParameter(target, "commonPipelineEnvironment", "git/commitId"))
+	assert.Equal(t, `["a"]`, piperenv.GetResourceParameter(target, "commonPipelineEnvironment", "custom/list"))
+	assert.Equal(t, piperenv.TypeJSON, piperenv.GetResourceParameterType(target, "commonPipelineEnvironment", "custom/list"))
+	assert.Equal(t, "true", piperenv.GetResourceParameter(target, "influx", "step_data/fields/build"))
+
+	t.Run("invalid document", func(t *testing.T) {
+		err := importCPE(target, strings.NewReader("[]"))
+		assert.Contains(t, err.Error(), "failed to parse environment")
+	})
+
+	t.Run("export of resource outside of the environment", func(t *testing.T) {
+		var out bytes.Buffer
+		err := exportCPE(filepath.Join(dir, "env"), []string{"commonPipelineEnvironment", ".."}, &out)
+		assert.EqualError(t, err, "invalid resource name '..'")
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("resource outside of the environment", func(t *testing.T) {
+		err := importCPE(filepath.Join(target, "env"), strings.NewReader(`{"../../outside": {"key": "value"}}`))
+		assert.EqualError(t, err, "invalid resource name '../../outside'")
+		_, err = os.Stat(filepath.Join(target, "outside"))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestSetCPEValue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal("Failed to create temporary directory")
+	}
+	// clean up tmp dir
+	defer os.RemoveAll(dir)
+
+	t.Run("string", func(t *testing.T) {
+		assert.NoError(t, setCPEValue(dir, "commonPipelineEnvironment", "custom/value", "test", false))
+		assert.Equal(t, "test", piperenv.GetResourceParameter(dir, "commonPipelineEnvironment", "custom/value"))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		assert.NoError(t, setCPEValue(dir, "commonPipelineEnvironment", "custom/object", `{"key": "value"}`, true))
+		assert.Equal(t, `{"key":"value"}`, piperenv.GetResourceParameter(dir, "commonPipelineEnvironment", "custom/object"))
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		err := setCPEValue(dir, "commonPipelineEnvironment", "custom/object", `{`, true)
+		assert.Contains(t, err.Error(), "value of 'custom/object' is no valid JSON")
+	})
+
+	t.Run("resource outside of the environment", func(t *testing.T) {
+		assert.EqualError(t, setCPEValue(dir, "../../x", "outside", "test", false), "invalid resource name '../../x'")
+		_, err := os.Stat(filepath.Join(dir, "..", "..", "x"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("key outside of the environment", func(t *testing.T) {
+		assert.EqualError(t, setCPEValue(dir, "commonPipelineEnvironment", "../../outside", "test", false), "invalid key '../../outside'")
+		_, err := os.Stat(filepath.Join(dir, "..", "outside"))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestCpeGetCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal("Failed to create temporary directory")
+	}
+	// clean up tmp dir
+	defer os.RemoveAll(dir)
+
+	envRootPath := GeneralConfig.EnvRootPath
+	defer func() { GeneralConfig.EnvRootPath = envRootPath }()
+	GeneralConfig.EnvRootPath = dir
+
+	piperenv.SetResourceParameter(dir, "commonPipelineEnvironment", "artifactVersion", "1.2.3")
+
+	t.Run("existing value", func(t *testing.T) {
+		var out bytes.Buffer
+		cmd := cpeGetCommand()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"artifactVersion"})
+
+		assert.NoError(t, cmd.Execute())
+		assert.Equal(t, "1.2.3\n", out.String())
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+		var out bytes.Buffer
+		cmd := cpeGetCommand()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"git/commitId"})
+
+		assert.EqualError(t, cmd.Execute(), "no value available for 'git/commitId'")
+	})
+
+	t.Run("key outside of the environment", func(t *testing.T) {
+		var out bytes.Buffer
+		cmd := cpeGetCommand()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"/etc/passwd"})
+
+		assert.EqualError(t, cmd.Execute(), "invalid key '/etc/passwd'")
+	})
+
+	t.Run("resource outside of the environment", func(t *testing.T) {
+		var out bytes.Buffer
+		cmd := cpeGetCommand()
+		cmd.SetOut(&out)
+		cmd.SetArgs([]string{"--resource", "../..", "etc/passwd"})
+
+		assert.EqualError(t, cmd.Execute(), "invalid resource name '../..'")
+	})
+}
@@ -37,6 +37,7 @@ func DetectExecuteScanCommand() *cobra.Command {
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			startTime = time.Now()
 			log.SetStepName("detectExecuteScan")
+			log.RegisterFatalHook("detectExecuteScan", ".")
 			log.SetVerbose(GeneralConfig.Verbose)
 			return PrepareConfig(cmd, &metadata, "detectExecuteScan", &stepConfig, config.OpenPiperFile)
 		},
@@ -45,12 +46,18 @@ func DetectExecuteScanCommand() *cobra.Command {
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				if telemetryData.ErrorCode != "0" {
+					telemetryData.ErrorCategory = log.GetErrorCategory().String()
+					telemetryData.ErrorCode = fmt.Sprintf("%v", log.GetErrorCategory().ExitCode())
+				}
 				telemetry.Send(&telemetryData)
 			}
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetry.Initialize(GeneralConfig.NoTelemetry, "detectExecuteScan")
+			log.StartGroup("Execution")
 			detectExecuteScan(stepConfig, &telemetryData)
+			log.EndGroup()
 			telemetryData.ErrorCode = "0"
 		},
 	}
@@ -72,6 +79,7 @@ func addDetectExecuteScanFlags(cmd *cobra.Command, stepConfig *detectExecuteScan
 	cmd.MarkFlagRequired("apiToken")
 	cmd.MarkFlagRequired("projectName")
 	cmd.MarkFlagRequired("projectVersion")
+	cmd.RegisterFlagCompletionFunc("scanners", CompleteValues("signature", "source"))
 }
 
 // retrieve step metadata
@@ -110,19 +118,20 @@ func detectExecuteScanMetadata() config.StepData {
 					},
 					{
 						Name:        "projectVersion",
-						ResourceRef: []config.ResourceReference{},
+						ResourceRef: []config.ResourceReference{{Name: "commonPipelineEnvironment", Param: "artifactVersion"}},
 						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
 						Type:        "string",
 						Mandatory:   true,
 						Aliases:     []config.Alias{{Name: "detect/projectVersion"}},
 					},
 					{
-						Name:        "scanners",
-						ResourceRef: []config.ResourceReference{},
-						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "[]string",
-						Mandatory:   false,
-						Aliases:     []config.Alias{{Name: "detect/scanners"}},
+						Name:           "scanners",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "[]string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{{Name: "detect/scanners"}},
+						PossibleValues: []interface{}{"signature", "source"},
 					},
 					{
 						Name:        "scanPaths",
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
@@ -43,15 +44,9 @@ func generateConfig() error {
 	var myConfig config.Config
 	var stepConfig config.StepConfig
 
-	var metadata config.StepData
-	metadataFile, err := configOptions.openFile(configOptions.stepMetadata)
-	if err != nil {
-		return errors.Wrap(err, "metadata: open failed")
-	}
-
-	err = metadata.ReadPipelineStepData(metadataFile)
+	metadata, err := resolveMetadata()
 	if err != nil {
-		return errors.Wrap(err, "metadata: read failed")
+		return err
 	}
 
 	resourceParams := metadata.GetResourceParameters(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
@@ -117,10 +112,49 @@ func addConfigFlags(cmd *cobra.Command) {
 
 	cmd.Flags().StringVar(&configOptions.parametersJSON, "parametersJSON", os.Getenv("PIPER_parametersJSON"), "Parameters to be considered in JSON format")
 	cmd.Flags().StringVar(&configOptions.stepMetadata, "stepMetadata", "", "Step metadata, passed as path to yaml")
+	cmd.Flags().StringVar(&configOptions.stepName, "stepName", "", "Step name, used to get the step metadata contained in the piper binary if stepMetadata is not set")
 	cmd.Flags().BoolVar(&configOptions.contextConfig, "contextConfig", false, "Defines if step context configuration should be loaded instead of step config")
 
-	cmd.MarkFlagRequired("stepMetadata")
+	cmd.MarkFlagFilename("stepMetadata", "yaml", "yml")
+	cmd.RegisterFlagCompletionFunc("stepName", CompleteValues(stepNames()...))
+
+}
+
+// resolveMetadata reads the step metadata either from the file passed via stepMetadata or from the metadata contained in the binary
+func resolveMetadata() (config.StepData, error) {
+	var metadata config.StepData
+
+	if len(configOptions.stepMetadata) == 0 {
+		if len(configOptions.stepName) == 0 {
+			return metadata, errors.New("metadata: either stepMetadata or stepName must be provided")
+		}
+		metadata, ok := GetAllStepMetadata()[configOptions.stepName]
+		if !ok {
+			return metadata, errors.Errorf("metadata: step '%v' not known", configOptions.stepName)
+		}
+		return metadata, nil
+	}
+
+	metadataFile, err := configOptions.openFile(configOptions.stepMetadata)
+	if err != nil {
+		return metadata, errors.Wrap(err, "metadata: open failed")
+	}
+
+	err = metadata.ReadPipelineStepData(metadataFile)
+	if err != nil {
+		return metadata, errors.Wrap(err, "metadata: read failed")
+	}
+	return metadata, nil
+}
 
+// stepNames returns the sorted names of all steps contained in the binary
+func stepNames() []string {
+	names := []string{}
+	for name := range GetAllStepMetadata() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 func defaultsAndFilters(metadata *config.StepData, stepName string) ([]io.ReadCloser, config.StepFilters, error) {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"sort"
 	"strings"
 	"testing"
 
@@ -42,24 +43,69 @@ func TestConfigCommand(t *testing.T) {
 	})
 
 	t.Run("Required flags", func(t *testing.T) {
-		exp := []string{"stepMetadata"}
+		exp := []string{}
 		assert.Equal(t, exp, gotReq, "required flags incorrect")
 	})
 
 	t.Run("Optional flags", func(t *testing.T) {
-		exp := []string{"contextConfig", "output", "parametersJSON"}
+		exp := []string{"contextConfig", "output", "parametersJSON", "stepMetadata", "stepName"}
 		assert.Equal(t, exp, gotOpt, "optional flags incorrect")
 	})
 
 	t.Run("Run", func(t *testing.T) {
 		t.Run("Success case", func(t *testing.T) {
 			configOptions.openFile = configOpenFileMock
+			configOptions.stepMetadata = "testStep.yaml"
+			defer func() { configOptions.stepMetadata = "" }()
 			err := cmd.RunE(cmd, []string{})
 			assert.NoError(t, err, "error occured but none expected")
 		})
 	})
 }
 
+func TestResolveMetadata(t *testing.T) {
+	configOptions.openFile = configOpenFileMock
+
+	t.Run("from metadata file", func(t *testing.T) {
+		configOptions.stepMetadata = "testStep.yaml"
+		configOptions.stepName = "xsDeploy"
+		defer func() { configOptions.stepMetadata, configOptions.stepName = "", "" }()
+
+		metadata, err := resolveMetadata()
+		assert.NoError(t, err)
+		assert.Equal(t, "", metadata.Metadata.Name, "metadata file must take precedence over the step name")
+	})
+
+	t.Run("from step name", func(t *testing.T) {
+		configOptions.stepName = "xsDeploy"
+		defer func() { configOptions.stepName = "" }()
+
+		metadata, err := resolveMetadata()
+		assert.NoError(t, err)
+		assert.Equal(t, "xsDeploy", metadata.Metadata.Name)
+	})
+
+	t.Run("unknown step name", func(t *testing.T) {
+		configOptions.stepName = "notExisting"
+		defer func() { configOptions.stepName = "" }()
+
+		_, err := resolveMetadata()
+		assert.EqualError(t, err, "metadata: step 'notExisting' not known")
+	})
+
+	t.Run("neither metadata file nor step name", func(t *testing.T) {
+		_, err := resolveMetadata()
+		assert.EqualError(t, err, "metadata: either stepMetadata or stepName must be provided")
+	})
+}
+
+func TestStepNameCompletion(t *testing.T) {
+	names := stepNames()
+	assert.Contains(t, names, "xsDeploy")
+	assert.Contains(t, names, "kubernetesDeploy")
+	assert.True(t, sort.StringsAreSorted(names), "step names must be sorted")
+}
+
 func TestDefaultsAndFilters(t *testing.T) {
 	metadata := config.StepData{
 		Spec: config.StepSpec{
@@ -42,6 +42,7 @@ It can for example be used for GitOps scenarios or for scenarios where you want
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			startTime = time.Now()
 			log.SetStepName("githubCreatePullRequest")
+			log.RegisterFatalHook("githubCreatePullRequest", ".")
 			log.SetVerbose(GeneralConfig.Verbose)
 			return PrepareConfig(cmd, &metadata, "githubCreatePullRequest", &stepConfig, config.OpenPiperFile)
 		},
@@ -50,12 +51,18 @@ It can for example be used for GitOps scenarios or for scenarios where you want
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				if telemetryData.ErrorCode != "0" {
+					telemetryData.ErrorCategory = log.GetErrorCategory().String()
+					telemetryData.ErrorCode = fmt.Sprintf("%v", log.GetErrorCategory().ExitCode())
+				}
 				telemetry.Send(&telemetryData)
 			}
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetry.Initialize(GeneralConfig.NoTelemetry, "githubCreatePullRequest")
+			log.StartGroup("Execution")
 			githubCreatePullRequest(stepConfig, &telemetryData)
+			log.EndGroup()
 			telemetryData.ErrorCode = "0"
 		},
 	}
@@ -79,11 +86,9 @@ func addGithubCreatePullRequestFlags(cmd *cobra.Command, stepConfig *githubCreat
 
 	cmd.MarkFlagRequired("base")
 	cmd.MarkFlagRequired("body")
-	cmd.MarkFlagRequired("apiUrl")
 	cmd.MarkFlagRequired("head")
 	cmd.MarkFlagRequired("owner")
 	cmd.MarkFlagRequired("repository")
-	cmd.MarkFlagRequired("serverUrl")
 	cmd.MarkFlagRequired("title")
 	cmd.MarkFlagRequired("token")
 }
@@ -127,7 +132,7 @@ func githubCreatePullRequestMetadata() config.StepData {
 						ResourceRef: []config.ResourceReference{},
 						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
 						Type:        "string",
-						Mandatory:   true,
+						Mandatory:   false,
 						Aliases:     []config.Alias{{Name: "githubApiUrl"}},
 					},
 					{
@@ -159,7 +164,7 @@ func githubCreatePullRequestMetadata() config.StepData {
 						ResourceRef: []config.ResourceReference{},
 						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
 						Type:        "string",
-						Mandatory:   true,
+						Mandatory:   false,
 						Aliases:     []config.Alias{{Name: "githubServerUrl"}},
 					},
 					{
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+const (
+	golangTestReport     = "TEST-go.xml"
+	golangCoverProfile   = "cover.out"
+	golangCoverageReport = "cobertura-coverage.xml"
+
+	gotestsumPackage        = "gotest.tools/gotestsum@latest"
+	gocoverCoberturaPackage = "github.com/boumenot/gocover-cobertura@latest"
+)
+
+// golangBuildUtils defines an interface for utility functionality used from external packages,
+// so it can be easily mocked for testing.
+type golangBuildUtils interface {
+	FileRead(path string) ([]byte, error)
+	getExecRunner() execRunner
+}
+
+type golangBuildUtilsBundle struct {
+	piperutils.Files
+	execRunner *command.Command
+}
+
+func (u *golangBuildUtilsBundle) getExecRunner() execRunner {
+	if u.execRunner == nil {
+		u.execRunner = &command.Command{}
+		u.execRunner.Stdout(log.Entry().Writer())
+		u.execRunner.Stderr(log.Entry().Writer())
+	}
+	return u.execRunner
+}
+
+// ldflagsData is available within the ldflagsTemplate
+type ldflagsData struct {
+	Version string
+}
+
+func golangBuild(config golangBuildOptions, telemetryData *telemetry.CustomData, reports *golangBuildReports) {
+	utils := golangBuildUtilsBundle{}
+
+	// the reports are persisted by the generated code also for failing tests, so that the test results are available
+	err := runGolangBuild(&config, &utils, reports)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runGolangBuild(config *golangBuildOptions, utils golangBuildUtils, reports *golangBuildReports) error {
+	if config.RunTests {
+		if err := runGolangTests(config, utils, reports); err != nil {
+			return err
+		}
+	}
+
+	if len(config.TargetArchitectures) == 0 {
+		return nil
+	}
+
+	ldflags, err := renderLdflags(config.LdflagsTemplate, ldflagsData{Version: config.ArtifactVersion})
+	if err != nil {
+		return err
+	}
+
+	output := config.Output
+	if len(output) == 0 {
+		if output, err = golangModuleName(utils); err != nil {
+			return err
+		}
+	}
+
+	binaries := []string{}
+	for _, architecture := range config.TargetArchitectures {
+		binary, err := runGolangBuildForArchitecture(config, architecture, output, ldflags, utils)
+		if err != nil {
+			return err
+		}
+		binaries = append(binaries, binary)
+	}
+	// the binaries are archived as comma separated list of patterns
+	reports.binaries = strings.Join(binaries, ",")
+	return nil
+}
+
+func runGolangTests(config *golangBuildOptions, utils golangBuildUtils, reports *golangBuildReports) error {
+	execRunner := utils.getExecRunner()
+
+	tools := []string{gotestsumPackage}
+	if config.ReportCoverage {
+		tools = append(tools, gocoverCoberturaPackage)
+	}
+	for _, tool := range tools {
+		if err := execRunner.RunExecutable("go", "install", tool); err != nil {
+			return log.WrapError(log.ErrorInfrastructure, errors.Wrapf(err, "failed to install %v", tool))
+		}
+	}
+
+	testOptions := []string{}
+	if config.ReportCoverage {
+		testOptions = append(testOptions, "-coverprofile="+golangCoverProfile)
+	}
+	testOptions = append(testOptions, config.TestOptions...)
+	testOptions = append(testOptions, "./...")
+
+	err := execRunner.RunExecutable("gotestsum", append([]string{"--junitfile", golangTestReport, "--"}, testOptions...)...)
+	// the JUnit report is also written for failing tests
+	reports.testReport = golangTestReport
+	if err != nil {
+		return log.WrapError(log.ErrorTest, errors.Wrap(err, "running tests failed"))
+	}
+
+	if config.ReportCoverage {
+		// gocover-cobertura only supports stdin and stdout
+		script := fmt.Sprintf("gocover-cobertura < %v > %v", golangCoverProfile, golangCoverageReport)
+		if err := execRunner.RunExecutable("/bin/sh", "-c", script); err != nil {
+			return errors.Wrap(err, "failed to convert coverage to Cobertura format")
+		}
+		reports.coverageReport = golangCoverageReport
+	}
+	return nil
+}
+
+func runGolangBuildForArchitecture(config *golangBuildOptions, architecture, output, ldflags string, utils golangBuildUtils) (string, error) {
+	parts := strings.Split(architecture, "/")
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", log.NewError(log.ErrorConfiguration, "target architecture '%v' is invalid, expected format is GOOS/GOARCH", architecture)
+	}
+	goos, goarch := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+	binary := fmt.Sprintf("%v-%v.%v", output, goos, goarch)
+	if goos == "windows" {
+		binary += ".exe"
+	}
+
+	cgoEnabled := "0"
+	if config.CgoEnabled {
+		cgoEnabled = "1"
+	}
+
+	execRunner := utils.getExecRunner()
+	execRunner.SetEnv([]string{"GOOS=" + goos, "GOARCH=" + goarch, "CGO_ENABLED=" + cgoEnabled})
+
+	buildOptions := []string{"build", "-o", binary}
+	buildOptions = append(buildOptions, config.BuildFlags...)
+	if len(ldflags) > 0 {
+		buildOptions = append(buildOptions, "-ldflags", ldflags)
+	}
+	buildOptions = append(buildOptions, config.Packages...)
+
+	log.Entry().Infof("Building %v", binary)
+	if err := execRunner.RunExecutable("go", buildOptions...); err != nil {
+		return "", errors.Wrapf(err, "failed to build %v", binary)
+	}
+	return binary, nil
+}
+
+func renderLdflags(ldflagsTemplate string, data ldflagsData) (string, error) {
+	if len(ldflagsTemplate) == 0 {
+		return "", nil
+	}
+	tmpl, err := template.New("ldflags").Parse(ldflagsTemplate)
+	if err != nil {
+		return "", log.WrapError(log.ErrorConfiguration, errors.Wrap(err, "failed to parse ldflagsTemplate"))
+	}
+	var ldflags bytes.Buffer
+	if err := tmpl.Execute(&ldflags, data); err != nil {
+		return "", log.WrapError(log.ErrorConfiguration, errors.Wrap(err, "failed to render ldflagsTemplate"))
+	}
+	return ldflags.String(), nil
+}
+
+// golangModuleName returns the last element of the module path declared in go.mod
+func golangModuleName(utils golangBuildUtils) (string, error) {
+	goMod, err := utils.FileRead("go.mod")
+	if err != nil {
+		return "", log.WrapError(log.ErrorConfiguration, errors.Wrap(err, "failed to read go.mod, please configure the name of the binaries via output"))
+	}
+	for _, line := range strings.Split(string(goMod), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "module" {
+			return path.Base(strings.Trim(fields[1], `"`)), nil
+		}
+	}
+	return "", log.NewError(log.ErrorConfiguration, "no module declared in go.mod, please configure the name of the binaries via output")
+}
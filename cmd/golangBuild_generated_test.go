@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGolangBuildCommand(t *testing.T) {
+
+	testCmd := GolangBuildCommand()
+
+	// only high level testing performed - details are tested in step generation procudure
+	assert.Equal(t, "golangBuild", testCmd.Use, "command name incorrect")
+
+}
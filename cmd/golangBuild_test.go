@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+type golangBuildMockUtils struct {
+	mock.FileSystemMock
+	execRunner mock.ExecMockRunner
+}
+
+func newGolangBuildMockUtils() golangBuildMockUtils {
+	return golangBuildMockUtils{FileSystemMock: mock.NewFileSystemMock()}
+}
+
+func (m *golangBuildMockUtils) getExecRunner() execRunner {
+	return &m.execRunner
+}
+
+func TestRunGolangBuild(t *testing.T) {
+	t.Run("test and build", func(t *testing.T) {
+		utils := newGolangBuildMockUtils()
+		utils.Files["go.mod"] = []byte("module github.com/SAP/jenkins-library\n\ngo 1.13\n")
+		config := golangBuildOptions{
+			RunTests:            true,
+			ReportCoverage:      true,
+			TestOptions:         []string{"-race"},
+			TargetArchitectures: []string{"linux/amd64", "windows/amd64"},
+			Packages:            []string{"."},
+			LdflagsTemplate:     "-X main.Version={{.Version}}",
+			ArtifactVersion:     "1.2.3",
+		}
+
+		reports := golangBuildReports{}
+		err := runGolangBuild(&config, &utils, &reports)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "go", Params: []string{"install", "gotest.tools/gotestsum@latest"}},
+			{Exec: "go", Params: []string{"install", "github.com/boumenot/gocover-cobertura@latest"}},
+			{Exec: "gotestsum", Params: []string{"--junitfile", "TEST-go.xml", "--", "-coverprofile=cover.out", "-race", "./..."}},
+			{Exec: "/bin/sh", Params: []string{"-c", "gocover-cobertura < cover.out > cobertura-coverage.xml"}},
+			{Exec: "go", Params: []string{"build", "-o", "jenkins-library-linux.amd64", "-ldflags", "-X main.Version=1.2.3", "."}},
+			{Exec: "go", Params: []string{"build", "-o", "jenkins-library-windows.amd64.exe", "-ldflags", "-X main.Version=1.2.3", "."}},
+		}, utils.execRunner.Calls)
+		assert.Equal(t, []string{
+			"GOOS=linux", "GOARCH=amd64", "CGO_ENABLED=0",
+			"GOOS=windows", "GOARCH=amd64", "CGO_ENABLED=0",
+		}, utils.execRunner.Env)
+		assert.Equal(t, golangBuildReports{
+			testReport:     "TEST-go.xml",
+			coverageReport: "cobertura-coverage.xml",
+			binaries:       "jenkins-library-linux.amd64,jenkins-library-windows.amd64.exe",
+		}, reports)
+	})
+
+	t.Run("build only", func(t *testing.T) {
+		utils := newGolangBuildMockUtils()
+		config := golangBuildOptions{
+			TargetArchitectures: []string{"darwin/arm64"},
+			Output:              "piper",
+			Packages:            []string{"./cmd/piper"},
+			BuildFlags:          []string{"-trimpath"},
+			CgoEnabled:          true,
+		}
+
+		reports := golangBuildReports{}
+		err := runGolangBuild(&config, &utils, &reports)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "go", Params: []string{"build", "-o", "piper-darwin.arm64", "-trimpath", "./cmd/piper"}},
+		}, utils.execRunner.Calls)
+		assert.Equal(t, []string{"GOOS=darwin", "GOARCH=arm64", "CGO_ENABLED=1"}, utils.execRunner.Env)
+		assert.Equal(t, golangBuildReports{binaries: "piper-darwin.arm64"}, reports)
+	})
+
+	t.Run("failing tests", func(t *testing.T) {
+		utils := newGolangBuildMockUtils()
+		utils.execRunner.ShouldFailOnCommand = map[string]error{"^gotestsum": fmt.Errorf("exit status 1")}
+		config := golangBuildOptions{RunTests: true, TargetArchitectures: []string{"linux/amd64"}}
+
+		reports := golangBuildReports{}
+		err := runGolangBuild(&config, &utils, &reports)
+
+		assert.EqualError(t, err, "running tests failed: exit status 1")
+		assert.Equal(t, log.ErrorTest, log.ErrorCategoryOf(err))
+		assert.Equal(t, golangBuildReports{testReport: "TEST-go.xml"}, reports, "test report is expected to be persisted")
+		assert.Len(t, utils.execRunner.Calls, 2, "no build is expected")
+	})
+
+	t.Run("target architectures via flag", func(t *testing.T) {
+		// list flags are split on commas, which must not break the format of the architectures
+		command := &cobra.Command{}
+		config := golangBuildOptions{}
+		addGolangBuildFlags(command, &config)
+		assert.NoError(t, command.ParseFlags([]string{"--targetArchitectures", "linux/amd64,windows/amd64", "--output", "piper"}))
+		utils := newGolangBuildMockUtils()
+
+		err := runGolangBuild(&config, &utils, &golangBuildReports{})
+
+		assert.NoError(t, err)
+		assert.Contains(t, utils.execRunner.Calls, mock.ExecCall{Exec: "go", Params: []string{"build", "-o", "piper-linux.amd64", "."}})
+		assert.Contains(t, utils.execRunner.Calls, mock.ExecCall{Exec: "go", Params: []string{"build", "-o", "piper-windows.amd64.exe", "."}})
+	})
+
+	t.Run("invalid target architecture", func(t *testing.T) {
+		utils := newGolangBuildMockUtils()
+		config := golangBuildOptions{TargetArchitectures: []string{"linux"}, Output: "piper"}
+
+		err := runGolangBuild(&config, &utils, &golangBuildReports{})
+
+		assert.EqualError(t, err, "target architecture 'linux' is invalid, expected format is GOOS/GOARCH")
+		assert.Equal(t, log.ErrorConfiguration, log.ErrorCategoryOf(err))
+	})
+
+	t.Run("invalid ldflags template", func(t *testing.T) {
+		utils := newGolangBuildMockUtils()
+		config := golangBuildOptions{TargetArchitectures: []string{"linux/amd64"}, LdflagsTemplate: "-X main.Version={{.Version"}
+
+		err := runGolangBuild(&config, &utils, &golangBuildReports{})
+
+		assert.Contains(t, fmt.Sprint(err), "failed to parse ldflagsTemplate")
+	})
+
+	t.Run("missing go.mod", func(t *testing.T) {
+		utils := newGolangBuildMockUtils()
+		config := golangBuildOptions{TargetArchitectures: []string{"linux/amd64"}}
+
+		err := runGolangBuild(&config, &utils, &golangBuildReports{})
+
+		assert.EqualError(t, err, "failed to read go.mod, please configure the name of the binaries via output: could not read 'go.mod'")
+	})
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/gradle"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/bmatcuk/doublestar"
+	"github.com/pkg/errors"
+)
+
+// gradleRepositoryInitScript is the init script generated for the repository configured via repositoryUrl
+const gradleRepositoryInitScript = ".piperRepository.gradle"
+
+// gradleArtifactsPattern matches the artifacts of all projects of a Gradle build
+const gradleArtifactsPattern = "**/build/libs/*.{jar,war,ear}"
+
+// gradleBuildUtils defines an interface for utility functionality used from external packages,
+// so it can be easily mocked for testing.
+type gradleBuildUtils interface {
+	piperutils.FileUtils
+	FileRemove(path string) error
+	Glob(pattern string) ([]string, error)
+	getExecRunner() execRunner
+}
+
+type gradleBuildUtilsBundle struct {
+	piperutils.Files
+	execRunner *command.Command
+}
+
+func (u *gradleBuildUtilsBundle) FileRemove(path string) error {
+	return os.Remove(path)
+}
+
+func (u *gradleBuildUtilsBundle) Glob(pattern string) ([]string, error) {
+	return doublestar.Glob(pattern)
+}
+
+func (u *gradleBuildUtilsBundle) getExecRunner() execRunner {
+	if u.execRunner == nil {
+		u.execRunner = &command.Command{}
+		u.execRunner.Stdout(log.Entry().Writer())
+		u.execRunner.Stderr(log.Entry().Writer())
+	}
+	return u.execRunner
+}
+
+func gradleBuild(config gradleBuildOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *gradleBuildCommonPipelineEnvironment) {
+	utils := gradleBuildUtilsBundle{}
+
+	err := runGradleBuild(&config, &utils, commonPipelineEnvironment)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runGradleBuild(config *gradleBuildOptions, utils gradleBuildUtils, commonPipelineEnvironment *gradleBuildCommonPipelineEnvironment) error {
+	execRunner := utils.getExecRunner()
+	initScriptFiles := config.InitScriptFiles
+
+	if len(config.RepositoryURL) > 0 {
+		initScript, err := filepath.Abs(gradleRepositoryInitScript)
+		if err != nil {
+			return errors.Wrapf(err, "failed to resolve path of %v", gradleRepositoryInitScript)
+		}
+		if err := gradle.WriteRepositoryInitScript(initScript, utils); err != nil {
+			return err
+		}
+		defer func() {
+			if err := utils.FileRemove(initScript); err != nil {
+				log.Entry().WithError(err).Warnf("failed to remove %v", initScript)
+			}
+		}()
+		log.Entry().Infof("Adding repository %v to the Gradle build", config.RepositoryURL)
+		execRunner.SetEnv(gradle.RepositoryEnvironment(config.RepositoryURL, config.RepositoryUsername, config.RepositoryPassword))
+		initScriptFiles = append(initScriptFiles, initScript)
+	}
+
+	gradleOptions := gradle.ExecuteOptions{
+		ProjectDir:      config.ProjectDir,
+		InitScriptFiles: initScriptFiles,
+		Tasks:           config.Tasks,
+	}
+	if err := gradle.Execute(&gradleOptions, execRunner, utils); err != nil {
+		return err
+	}
+
+	artifacts, err := findGradleArtifacts(config.ProjectDir, utils)
+	if err != nil {
+		return err
+	}
+	if len(artifacts) == 0 {
+		log.Entry().Info("No artifacts found below build/libs")
+	} else {
+		log.Entry().Infof("Artifacts built: %v", artifacts)
+	}
+
+	artifactsJSON, err := json.Marshal(artifacts)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize the list of artifacts")
+	}
+	commonPipelineEnvironment.gradle.artifacts = string(artifactsJSON)
+	return nil
+}
+
+// findGradleArtifacts returns the artifacts below build/libs of all projects of the build
+func findGradleArtifacts(projectDir string, utils gradleBuildUtils) ([]string, error) {
+	artifacts, err := utils.Glob(filepath.Join(projectDir, gradleArtifactsPattern))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search for artifacts")
+	}
+	if artifacts == nil {
+		artifacts = []string{}
+	}
+	sort.Strings(artifacts)
+	return artifacts, nil
+}
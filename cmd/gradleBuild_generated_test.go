@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGradleBuildCommand(t *testing.T) {
+
+	testCmd := GradleBuildCommand()
+
+	// only high level testing performed - details are tested in step generation procudure
+	assert.Equal(t, "gradleBuild", testCmd.Use, "command name incorrect")
+
+}
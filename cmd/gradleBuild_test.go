@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type gradleBuildMockUtils struct {
+	mock.FileSystemMock
+	execRunner mock.ExecMockRunner
+}
+
+func newGradleBuildMockUtils() gradleBuildMockUtils {
+	return gradleBuildMockUtils{FileSystemMock: mock.NewFileSystemMock()}
+}
+
+func (m *gradleBuildMockUtils) getExecRunner() execRunner {
+	return &m.execRunner
+}
+
+func TestRunGradleBuild(t *testing.T) {
+	t.Run("build with wrapper", func(t *testing.T) {
+		utils := newGradleBuildMockUtils()
+		utils.Files["gradlew"] = []byte("#!/bin/sh")
+		utils.Files["build/libs/app.jar"] = []byte{}
+		utils.Files["lib/build/libs/lib.jar"] = []byte{}
+		utils.Files["lib/build/libs/lib.txt"] = []byte{}
+		config := gradleBuildOptions{ProjectDir: ".", Tasks: []string{"clean", "build"}}
+		cpe := gradleBuildCommonPipelineEnvironment{}
+
+		err := runGradleBuild(&config, &utils, &cpe)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{{Exec: "./gradlew", Params: []string{"--console=plain", "clean", "build"}}}, utils.execRunner.Calls)
+		assert.Empty(t, utils.execRunner.Env)
+		assert.Equal(t, `["build/libs/app.jar","lib/build/libs/lib.jar"]`, cpe.gradle.artifacts)
+	})
+
+	t.Run("build with repository", func(t *testing.T) {
+		utils := newGradleBuildMockUtils()
+		config := gradleBuildOptions{
+			ProjectDir:         "service",
+			Tasks:              []string{"build"},
+			InitScriptFiles:    []string{"init.gradle"},
+			RepositoryURL:      "https://repo.example.org/maven",
+			RepositoryUsername: "user",
+			RepositoryPassword: "secret",
+		}
+		cpe := gradleBuildCommonPipelineEnvironment{}
+
+		err := runGradleBuild(&config, &utils, &cpe)
+
+		assert.NoError(t, err)
+		initScript, _ := filepath.Abs(".piperRepository.gradle")
+		exists, _ := utils.FileExists(initScript)
+		assert.False(t, exists, "init script must be removed after the build")
+		assert.Equal(t, []mock.ExecCall{{Exec: "gradle", Params: []string{
+			"--init-script", "init.gradle",
+			"--init-script", initScript,
+			"--project-dir", "service",
+			"--console=plain", "build",
+		}}}, utils.execRunner.Calls)
+		assert.Equal(t, []string{
+			"PIPER_GRADLE_REPOSITORY_URL=https://repo.example.org/maven",
+			"PIPER_GRADLE_REPOSITORY_USERNAME=user",
+			"PIPER_GRADLE_REPOSITORY_PASSWORD=secret",
+		}, utils.execRunner.Env)
+		assert.Equal(t, "[]", cpe.gradle.artifacts)
+	})
+
+	t.Run("failing build", func(t *testing.T) {
+		utils := newGradleBuildMockUtils()
+		utils.execRunner.ShouldFailOnCommand = map[string]error{"gradle --console=plain build": fmt.Errorf("exit status 1")}
+		config := gradleBuildOptions{Tasks: []string{"build"}}
+		cpe := gradleBuildCommonPipelineEnvironment{}
+
+		err := runGradleBuild(&config, &utils, &cpe)
+
+		assert.EqualError(t, err, "failed to run executable, command: '[gradle --console=plain build]', error: exit status 1")
+		assert.Empty(t, cpe.gradle.artifacts)
+	})
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/influx"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+func influxWriteLineProtocol(config influxWriteLineProtocolOptions, telemetryData *telemetry.CustomData) {
+	client := &piperhttp.Client{}
+	clientOptions := piperhttp.ClientOptions{Username: config.Username, Password: config.Password}
+	if len(config.Token) > 0 {
+		clientOptions.Token = fmt.Sprintf("Token %v", config.Token)
+	}
+	client.SetOptions(clientOptions)
+
+	err := runInfluxWriteLineProtocol(&config, GeneralConfig.EnvRootPath, client, &piperutils.Files{}, time.Now())
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runInfluxWriteLineProtocol(config *influxWriteLineProtocolOptions, envRootPath string, sender piperhttp.Sender, fileUtils piperutils.FileUtils, timestamp time.Time) error {
+	measurements, err := influx.ReadMeasurements(envRootPath, "influx")
+	if err != nil {
+		return err
+	}
+
+	lines := influx.LineProtocol(measurements, timestamp)
+	if len(lines) == 0 {
+		log.Entry().Info("No influx data available")
+		return nil
+	}
+
+	if len(config.LineProtocolFile) > 0 {
+		if err := fileUtils.FileWrite(config.LineProtocolFile, []byte(lines), 0644); err != nil {
+			return errors.Wrapf(err, "failed to write influx data to %v", config.LineProtocolFile)
+		}
+		log.Entry().Infof("Influx data written to %v", config.LineProtocolFile)
+	}
+
+	if len(config.ServerURL) > 0 {
+		client := influx.NewClient(sender, influx.ClientOptions{
+			ServerURL:       config.ServerURL,
+			APIVersion:      config.APIVersion,
+			Database:        config.Database,
+			RetentionPolicy: config.RetentionPolicy,
+			Organization:    config.Organization,
+			Bucket:          config.Bucket,
+		})
+		if _, err := client.WriteURL(); err != nil {
+			return log.WrapError(log.ErrorConfiguration, err)
+		}
+		if err := client.Write(lines); err != nil {
+			return log.WrapError(log.ErrorInfrastructure, err)
+		}
+		log.Entry().Infof("Influx data of %v measurements sent to %v", len(measurements), config.ServerURL)
+	}
+	return nil
+}
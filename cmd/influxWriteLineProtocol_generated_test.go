@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInfluxWriteLineProtocolCommand(t *testing.T) {
+
+	testCmd := InfluxWriteLineProtocolCommand()
+
+	// only high level testing performed - details are tested in step generation procudure
+	assert.Equal(t, "influxWriteLineProtocol", testCmd.Use, "command name incorrect")
+
+}
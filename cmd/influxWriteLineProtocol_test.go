@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/stretchr/testify/assert"
+)
+
+type influxFilesMock struct {
+	mock.FilesMock
+	written map[string]string
+}
+
+func (f *influxFilesMock) FileWrite(path string, content []byte, perm os.FileMode) error {
+	f.written[path] = string(content)
+	return nil
+}
+
+func TestRunInfluxWriteLineProtocol(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal("Failed to create temporary directory")
+	}
+	// clean up tmp dir
+	defer os.RemoveAll(dir)
+
+	piperenv.SetResourceParameter(dir, "influx", "checkmarx_data/fields/high_issues", "3")
+	piperenv.SetResourceParameter(dir, "influx", "checkmarx_data/tags/project", "myProject")
+	timestamp := time.Unix(1586000000, 0)
+	expectedLines := "checkmarx_data,project=myProject high_issues=3i 1586000000000000000\n"
+
+	t.Run("write file and send to InfluxDB", func(t *testing.T) {
+		var passedURL, passedBody string
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			passedURL = req.URL.String()
+			body, _ := ioutil.ReadAll(req.Body)
+			passedBody = string(body)
+			rw.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		config := influxWriteLineProtocolOptions{ServerURL: server.URL, APIVersion: "v1", Database: "jenkins", LineProtocolFile: "influx_data.txt"}
+		files := influxFilesMock{written: map[string]string{}}
+
+		err := runInfluxWriteLineProtocol(&config, dir, &piperhttp.Client{}, &files, timestamp)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedLines, files.written["influx_data.txt"])
+		assert.Equal(t, "/write?db=jenkins&precision=ns", passedURL)
+		assert.Equal(t, expectedLines, passedBody)
+	})
+
+	t.Run("only write file", func(t *testing.T) {
+		config := influxWriteLineProtocolOptions{LineProtocolFile: "influx_data.txt"}
+		files := influxFilesMock{written: map[string]string{}}
+
+		err := runInfluxWriteLineProtocol(&config, dir, &piperhttp.Client{}, &files, timestamp)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedLines, files.written["influx_data.txt"])
+	})
+
+	t.Run("no data available", func(t *testing.T) {
+		config := influxWriteLineProtocolOptions{ServerURL: "http://not.reachable", LineProtocolFile: "influx_data.txt"}
+		files := influxFilesMock{written: map[string]string{}}
+
+		err := runInfluxWriteLineProtocol(&config, filepath.Join(dir, "empty"), &piperhttp.Client{}, &files, timestamp)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(files.written))
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		config := influxWriteLineProtocolOptions{ServerURL: "http://not.reachable", APIVersion: "v2"}
+
+		err := runInfluxWriteLineProtocol(&config, dir, &piperhttp.Client{}, &influxFilesMock{}, timestamp)
+
+		assert.EqualError(t, err, "organization and bucket are required for InfluxDB API version v2")
+		assert.Equal(t, log.ErrorConfiguration, log.ErrorCategoryOf(err))
+	})
+
+	t.Run("InfluxDB not available", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+		config := influxWriteLineProtocolOptions{ServerURL: server.URL, APIVersion: "v2", Organization: "org", Bucket: "bucket"}
+
+		err := runInfluxWriteLineProtocol(&config, dir, &piperhttp.Client{}, &influxFilesMock{}, timestamp)
+
+		assert.Contains(t, err.Error(), "failed to write data to InfluxDB")
+		assert.Equal(t, log.ErrorInfrastructure, log.ErrorCategoryOf(err))
+	})
+}
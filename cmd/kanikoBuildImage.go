@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/docker"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+const (
+	kanikoExecutor     = "/kaniko/executor"
+	kanikoDockerConfig = "/kaniko/.docker/config.json"
+	kanikoCertificates = "/kaniko/ssl/certs/ca-certificates.crt"
+	// kanikoDigestFile receives the digest of the built image
+	kanikoDigestFile = ".piperKanikoDigest"
+)
+
+// kanikoBuildImageUtils defines an interface for utility functionality used from external packages,
+// so it can be easily mocked for testing.
+type kanikoBuildImageUtils interface {
+	FileExists(path string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	downloadFile(url, filename string) error
+	getExecRunner() execRunner
+}
+
+type kanikoBuildImageUtilsBundle struct {
+	piperutils.Files
+	httpClient piperhttp.Client
+	execRunner *command.Command
+}
+
+func (u *kanikoBuildImageUtilsBundle) downloadFile(url, filename string) error {
+	return u.httpClient.DownloadFile(url, filename, nil, nil)
+}
+
+func (u *kanikoBuildImageUtilsBundle) getExecRunner() execRunner {
+	if u.execRunner == nil {
+		u.execRunner = &command.Command{}
+		u.execRunner.Stdout(log.Entry().Writer())
+		u.execRunner.Stderr(log.Entry().Writer())
+	}
+	return u.execRunner
+}
+
+func kanikoBuildImage(config kanikoBuildImageOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *kanikoBuildImageCommonPipelineEnvironment) {
+	utils := kanikoBuildImageUtilsBundle{}
+
+	err := runKanikoBuildImage(&config, &utils, commonPipelineEnvironment)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runKanikoBuildImage(config *kanikoBuildImageOptions, utils kanikoBuildImageUtils, commonPipelineEnvironment *kanikoBuildImageCommonPipelineEnvironment) error {
+	destinations := []string{}
+	if len(config.ContainerImageNameAndTag) > 0 {
+		destinations = append(destinations, config.ContainerImageNameAndTag)
+	}
+	destinations = append(destinations, config.AdditionalDestinations...)
+
+	if err := installKanikoCertificates(config.CustomTLSCertificateLinks, utils); err != nil {
+		return err
+	}
+
+	if err := writeKanikoDockerConfig(config, destinations, utils); err != nil {
+		return err
+	}
+
+	workspace, err := filepath.Abs(".")
+	if err != nil {
+		return errors.Wrap(err, "failed to resolve the workspace")
+	}
+	digestFile := filepath.Join(workspace, kanikoDigestFile)
+
+	kanikoOptions := []string{
+		"--dockerfile", filepath.Join(workspace, config.Dockerfile),
+		"--context", workspace,
+		"--digest-file", digestFile,
+	}
+	buildOptions := strings.Fields(config.ContainerBuildOptions)
+	kanikoOptions = append(kanikoOptions, buildOptions...)
+	for _, buildArg := range config.BuildArgs {
+		kanikoOptions = append(kanikoOptions, "--build-arg", buildArg)
+	}
+	if len(config.Target) > 0 {
+		kanikoOptions = append(kanikoOptions, "--target", config.Target)
+	}
+	if containsKanikoDestination(buildOptions) {
+		log.Entry().Info("Destination contained in containerBuildOptions, the configured destinations are not added")
+	} else {
+		if len(destinations) == 0 {
+			log.Entry().Info("No destination configured, the image is not pushed")
+			kanikoOptions = append(kanikoOptions, "--no-push")
+		}
+		for _, destination := range destinations {
+			kanikoOptions = append(kanikoOptions, "--destination", destination)
+		}
+	}
+
+	if err := utils.getExecRunner().RunExecutable(kanikoExecutor, kanikoOptions...); err != nil {
+		return errors.Wrap(err, "execution of kaniko failed")
+	}
+
+	if len(destinations) == 0 {
+		return nil
+	}
+
+	image, err := docker.ParseImageReference(destinations[0])
+	if err != nil {
+		return log.WrapError(log.ErrorConfiguration, err)
+	}
+	commonPipelineEnvironment.container.registryURL = image.RegistryURL
+	commonPipelineEnvironment.container.imageNameTag = image.ImageNameTag
+	commonPipelineEnvironment.container.image = destinations[0]
+
+	if exists, _ := utils.FileExists(digestFile); exists {
+		digest, err := utils.FileRead(digestFile)
+		if err != nil {
+			return errors.Wrap(err, "failed to read the digest of the image")
+		}
+		commonPipelineEnvironment.container.imageDigest = strings.TrimSpace(string(digest))
+		log.Entry().Infof("Image %v pushed with digest %v", destinations[0], commonPipelineEnvironment.container.imageDigest)
+	}
+	return nil
+}
+
+// containsKanikoDestination checks whether the build options already define a destination
+func containsKanikoDestination(buildOptions []string) bool {
+	for _, option := range buildOptions {
+		if option == "--destination" || strings.HasPrefix(option, "--destination=") {
+			return true
+		}
+	}
+	return false
+}
+
+// installKanikoCertificates appends the certificates to the certificates trusted by kaniko
+func installKanikoCertificates(certificateLinks []string, utils kanikoBuildImageUtils) error {
+	if len(certificateLinks) == 0 {
+		return nil
+	}
+	certificates, err := utils.FileRead(kanikoCertificates)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the certificates of kaniko")
+	}
+	for i, link := range certificateLinks {
+		certificateFile := filepath.Join(os.TempDir(), fmt.Sprintf("certificate%d.crt", i))
+		if err := utils.downloadFile(link, certificateFile); err != nil {
+			return log.WrapError(log.ErrorInfrastructure, errors.Wrapf(err, "failed to download certificate from %v", link))
+		}
+		certificate, err := utils.FileRead(certificateFile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read certificate downloaded from %v", link)
+		}
+		certificates = append(certificates, '\n')
+		certificates = append(certificates, certificate...)
+	}
+	if err := utils.FileWrite(kanikoCertificates, certificates, 0644); err != nil {
+		return errors.Wrap(err, "failed to update the certificates of kaniko")
+	}
+	return nil
+}
+
+// writeKanikoDockerConfig writes the credentials for the registries to the Docker config.json of kaniko.
+// Without credentials an empty config is written to allow anonymous access.
+func writeKanikoDockerConfig(config *kanikoBuildImageOptions, destinations []string, utils kanikoBuildImageUtils) error {
+	dockerConfig := []byte(`{"auths":{}}`)
+	if len(config.DockerConfigJSON) > 0 {
+		var err error
+		if dockerConfig, err = utils.FileRead(config.DockerConfigJSON); err != nil {
+			return log.WrapError(log.ErrorConfiguration, errors.Wrapf(err, "failed to read Docker config.json %v", config.DockerConfigJSON))
+		}
+	}
+
+	if len(config.ContainerRegistryUser) > 0 {
+		registryURL := config.ContainerRegistryURL
+		if len(registryURL) == 0 {
+			if len(destinations) == 0 {
+				return log.NewError(log.ErrorConfiguration, "no registry for the credentials, please configure containerRegistryUrl")
+			}
+			image, err := docker.ParseImageReference(destinations[0])
+			if err != nil {
+				return log.WrapError(log.ErrorConfiguration, err)
+			}
+			registryURL = image.RegistryURL
+		}
+		var err error
+		if dockerConfig, err = docker.AddRegistryAuth(dockerConfig, registryURL, config.ContainerRegistryUser, config.ContainerRegistryPassword); err != nil {
+			return log.WrapError(log.ErrorConfiguration, err)
+		}
+	}
+
+	if err := utils.FileWrite(kanikoDockerConfig, dockerConfig, 0644); err != nil {
+		return errors.Wrap(err, "failed to write the Docker config.json of kaniko")
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKanikoBuildImageCommand(t *testing.T) {
+
+	testCmd := KanikoBuildImageCommand()
+
+	// only high level testing performed - details are tested in step generation procudure
+	assert.Equal(t, "kanikoBuildImage", testCmd.Use, "command name incorrect")
+
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type kanikoBuildImageMockUtils struct {
+	mock.FileSystemMock
+	downloads  map[string][]byte
+	execRunner mock.ExecMockRunner
+}
+
+func newKanikoBuildImageMockUtils() kanikoBuildImageMockUtils {
+	return kanikoBuildImageMockUtils{FileSystemMock: mock.NewFileSystemMock(), downloads: map[string][]byte{}}
+}
+
+func (m *kanikoBuildImageMockUtils) downloadFile(url, filename string) error {
+	content, ok := m.downloads[url]
+	if !ok {
+		return fmt.Errorf("not found")
+	}
+	m.Files[filename] = content
+	return nil
+}
+
+func (m *kanikoBuildImageMockUtils) getExecRunner() execRunner {
+	return &m.execRunner
+}
+
+func TestRunKanikoBuildImage(t *testing.T) {
+	workspace, _ := filepath.Abs(".")
+	digestFile := filepath.Join(workspace, ".piperKanikoDigest")
+
+	t.Run("build and push", func(t *testing.T) {
+		utils := newKanikoBuildImageMockUtils()
+		utils.Files[digestFile] = []byte("sha256:1234\n")
+		config := kanikoBuildImageOptions{
+			ContainerBuildOptions:     "--skip-tls-verify-pull --cache=true",
+			ContainerImageNameAndTag:  "my.registry.io/path/image:1.0",
+			AdditionalDestinations:    []string{"my.registry.io/path/image:latest"},
+			BuildArgs:                 []string{"VERSION=1.0"},
+			Target:                    "release",
+			Dockerfile:                "Dockerfile",
+			ContainerRegistryUser:     "user",
+			ContainerRegistryPassword: "secret",
+		}
+		cpe := kanikoBuildImageCommonPipelineEnvironment{}
+
+		err := runKanikoBuildImage(&config, &utils, &cpe)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{{Exec: "/kaniko/executor", Params: []string{
+			"--dockerfile", filepath.Join(workspace, "Dockerfile"),
+			"--context", workspace,
+			"--digest-file", digestFile,
+			"--skip-tls-verify-pull", "--cache=true",
+			"--build-arg", "VERSION=1.0",
+			"--target", "release",
+			"--destination", "my.registry.io/path/image:1.0",
+			"--destination", "my.registry.io/path/image:latest",
+		}}}, utils.execRunner.Calls)
+		assert.JSONEq(t, `{"auths":{"my.registry.io":{"auth":"dXNlcjpzZWNyZXQ="}}}`, string(utils.Files["/kaniko/.docker/config.json"]))
+		assert.Equal(t, "https://my.registry.io", cpe.container.registryURL)
+		assert.Equal(t, "path/image:1.0", cpe.container.imageNameTag)
+		assert.Equal(t, "my.registry.io/path/image:1.0", cpe.container.image)
+		assert.Equal(t, "sha256:1234", cpe.container.imageDigest)
+	})
+
+	t.Run("build without push", func(t *testing.T) {
+		utils := newKanikoBuildImageMockUtils()
+		config := kanikoBuildImageOptions{Dockerfile: "docker/Dockerfile"}
+		cpe := kanikoBuildImageCommonPipelineEnvironment{}
+
+		err := runKanikoBuildImage(&config, &utils, &cpe)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{{Exec: "/kaniko/executor", Params: []string{
+			"--dockerfile", filepath.Join(workspace, "docker", "Dockerfile"),
+			"--context", workspace,
+			"--digest-file", digestFile,
+			"--no-push",
+		}}}, utils.execRunner.Calls)
+		assert.Equal(t, `{"auths":{}}`, string(utils.Files["/kaniko/.docker/config.json"]), "anonymous access expected")
+		assert.Empty(t, cpe.container.image)
+	})
+
+	t.Run("destination in build options", func(t *testing.T) {
+		utils := newKanikoBuildImageMockUtils()
+		config := kanikoBuildImageOptions{Dockerfile: "Dockerfile", ContainerBuildOptions: "--skip-tls-verify-pull --destination=my.registry.io/image:1.0.0"}
+		cpe := kanikoBuildImageCommonPipelineEnvironment{}
+
+		err := runKanikoBuildImage(&config, &utils, &cpe)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{{Exec: "/kaniko/executor", Params: []string{
+			"--dockerfile", filepath.Join(workspace, "Dockerfile"),
+			"--context", workspace,
+			"--digest-file", digestFile,
+			"--skip-tls-verify-pull", "--destination=my.registry.io/image:1.0.0",
+		}}}, utils.execRunner.Calls)
+	})
+
+	t.Run("docker config and certificates", func(t *testing.T) {
+		utils := newKanikoBuildImageMockUtils()
+		utils.Files["/tmp/config.json"] = []byte(`{"auths":{"other.registry.io":{"auth":"b3RoZXI="}}}`)
+		utils.Files["/kaniko/ssl/certs/ca-certificates.crt"] = []byte("initial")
+		utils.downloads["https://example.org/custom.crt"] = []byte("custom")
+		config := kanikoBuildImageOptions{
+			ContainerImageNameAndTag:  "my.registry.io/image:1.0",
+			CustomTLSCertificateLinks: []string{"https://example.org/custom.crt"},
+			DockerConfigJSON:          "/tmp/config.json",
+			ContainerRegistryURL:      "https://push.registry.io",
+			ContainerRegistryUser:     "user",
+			ContainerRegistryPassword: "secret",
+		}
+		cpe := kanikoBuildImageCommonPipelineEnvironment{}
+
+		err := runKanikoBuildImage(&config, &utils, &cpe)
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"auths":{"other.registry.io":{"auth":"b3RoZXI="},"push.registry.io":{"auth":"dXNlcjpzZWNyZXQ="}}}`, string(utils.Files["/kaniko/.docker/config.json"]))
+		assert.Equal(t, "initial\ncustom", string(utils.Files["/kaniko/ssl/certs/ca-certificates.crt"]))
+		assert.Empty(t, cpe.container.imageDigest, "no digest written")
+	})
+
+	t.Run("certificate download fails", func(t *testing.T) {
+		utils := newKanikoBuildImageMockUtils()
+		utils.Files["/kaniko/ssl/certs/ca-certificates.crt"] = []byte("initial")
+		config := kanikoBuildImageOptions{CustomTLSCertificateLinks: []string{"https://example.org/missing.crt"}}
+
+		err := runKanikoBuildImage(&config, &utils, &kanikoBuildImageCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to download certificate from https://example.org/missing.crt: not found")
+		assert.Empty(t, utils.execRunner.Calls)
+	})
+
+	t.Run("credentials without registry", func(t *testing.T) {
+		utils := newKanikoBuildImageMockUtils()
+		config := kanikoBuildImageOptions{ContainerRegistryUser: "user", ContainerRegistryPassword: "secret"}
+
+		err := runKanikoBuildImage(&config, &utils, &kanikoBuildImageCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "no registry for the credentials, please configure containerRegistryUrl")
+	})
+
+	t.Run("kaniko fails", func(t *testing.T) {
+		utils := newKanikoBuildImageMockUtils()
+		utils.execRunner.ShouldFailOnCommand = map[string]error{"/kaniko/executor": fmt.Errorf("exit status 1")}
+		config := kanikoBuildImageOptions{ContainerImageNameAndTag: "my.registry.io/image:1.0"}
+		cpe := kanikoBuildImageCommonPipelineEnvironment{}
+
+		err := runKanikoBuildImage(&config, &utils, &cpe)
+
+		assert.EqualError(t, err, "execution of kaniko failed: exit status 1")
+		assert.Empty(t, cpe.container.image)
+	})
+}
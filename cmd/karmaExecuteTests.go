@@ -33,7 +33,7 @@ func runKarma(config karmaExecuteTestsOptions, command execRunner) {
 	err = command.RunExecutable(runCommandTokens[0], runCommandTokens[1:]...)
 	if err != nil {
 		log.Entry().
-			WithError(err).
+			WithError(log.WrapError(log.ErrorTest, err)).
 			WithField("command", config.RunCommand).
 			Fatal("failed to execute run command")
 	}
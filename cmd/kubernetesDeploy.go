@@ -6,13 +6,21 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/bmatcuk/doublestar"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
 )
 
 func kubernetesDeploy(config kubernetesDeployOptions, telemetryData *telemetry.CustomData) {
@@ -23,21 +31,24 @@ func kubernetesDeploy(config kubernetesDeployOptions, telemetryData *telemetry.C
 }
 
 func runKubernetesDeploy(config kubernetesDeployOptions, command execRunner, stdout io.Writer) {
-	if config.DeployTool == "helm" {
-		runHelmDeploy(config, command, stdout)
+	if config.DeployTool == "helm" || config.DeployTool == "helm3" {
+		// errors are returned instead of calling Fatal, otherwise the generated values file containing the registry secret is not removed
+		if err := runHelmDeploy(config, command, stdout); err != nil {
+			log.Entry().WithError(err).Fatal("Deployment with helm failed")
+		}
 	} else {
 		runKubectlDeploy(config, command)
 	}
 }
 
-func runHelmDeploy(config kubernetesDeployOptions, command execRunner, stdout io.Writer) {
+func runHelmDeploy(config kubernetesDeployOptions, command execRunner, stdout io.Writer) error {
 	_, containerRegistry, err := splitRegistryURL(config.ContainerRegistryURL)
 	if err != nil {
-		log.Entry().WithError(err).Fatalf("Container registry url '%v' incorrect", config.ContainerRegistryURL)
+		return errors.Wrapf(err, "Container registry url '%v' incorrect", config.ContainerRegistryURL)
 	}
 	containerImageName, containerImageTag, err := splitFullImageName(config.Image)
 	if err != nil {
-		log.Entry().WithError(err).Fatalf("Container image '%v' incorrect", config.Image)
+		return errors.Wrapf(err, "Container image '%v' incorrect", config.Image)
 	}
 	helmLogFields := map[string]interface{}{}
 	helmLogFields["Chart Path"] = config.ChartPath
@@ -48,20 +59,176 @@ func runHelmDeploy(config kubernetesDeployOptions, command execRunner, stdout io
 	log.Entry().WithFields(helmLogFields).Debug("Calling Helm")
 
 	helmEnv := []string{fmt.Sprintf("KUBECONFIG=%v", config.KubeConfig)}
-	if len(config.TillerNamespace) > 0 {
+	if config.DeployTool == "helm" && len(config.TillerNamespace) > 0 {
 		helmEnv = append(helmEnv, fmt.Sprintf("TILLER_NAMESPACE=%v", config.TillerNamespace))
 	}
 	log.Entry().Debugf("Helm SetEnv: %v", helmEnv)
 	command.SetEnv(helmEnv)
 	command.Stdout(stdout)
 
-	initParams := []string{"init", "--client-only"}
-	if err := command.RunExecutable("helm", initParams...); err != nil {
-		log.Entry().WithError(err).Fatal("Helm init called failed")
+	if config.DeployTool == "helm" {
+		initParams := []string{"init", "--client-only"}
+		if err := command.RunExecutable("helm", initParams...); err != nil {
+			return errors.Wrap(err, "Helm init called failed")
+		}
+	}
+
+	helmValues := map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": fmt.Sprintf("%v/%v", containerRegistry, containerImageName),
+			"tag":        containerImageTag,
+		},
+	}
+	if len(config.ContainerRegistryUser)+len(config.ContainerRegistryPassword) > 0 {
+		dockerConfig, err := dockerConfigJSON(config, containerRegistry, command, stdout)
+		if err != nil {
+			return err
+		}
+		helmValues["secret"] = map[string]interface{}{
+			"dockerconfigjson": dockerConfig,
+		}
+	}
+	if len(config.IngressHosts) > 0 {
+		helmValues["ingress"] = map[string]interface{}{"hosts": config.IngressHosts}
+	}
+	mergeHelmValues(helmValues, config.ValuesMap)
+
+	valuesFile, err := writeHelmValuesFile(helmValues)
+	if err != nil {
+		return err
 	}
+	defer os.Remove(valuesFile)
 
+	upgradeParams := []string{
+		"upgrade",
+		config.DeploymentName,
+		config.ChartPath,
+		"--install",
+		"--namespace",
+		config.Namespace,
+	}
+	if config.DeployTool == "helm" {
+		upgradeParams = append(upgradeParams, "--force", "--wait", "--timeout", strconv.Itoa(config.HelmDeployWaitSeconds))
+	} else {
+		upgradeParams = append(upgradeParams, "--create-namespace")
+		// with rollbackOnFailure the failed release is rolled back after its events and logs have been collected
+		if !config.RollbackOnFailure {
+			upgradeParams = append(upgradeParams, "--atomic")
+		}
+		upgradeParams = append(upgradeParams, "--wait", "--timeout", fmt.Sprintf("%vs", config.HelmDeployWaitSeconds))
+	}
+
+	// values passed later take precedence, thus the values of the step configuration win over the user values files
+	for _, v := range config.HelmValues {
+		upgradeParams = append(upgradeParams, "--values", v)
+	}
+	upgradeParams = append(upgradeParams, "--values", valuesFile)
+
+	if len(config.KubeContext) > 0 {
+		upgradeParams = append(upgradeParams, "--kube-context", config.KubeContext)
+	}
+
+	if len(config.AdditionalParameters) > 0 {
+		upgradeParams = append(upgradeParams, config.AdditionalParameters...)
+	}
+
+	command.Stdout(stdout)
+	log.Entry().Info("Calling helm upgrade ...")
+	log.Entry().Debugf("Helm parameters %v", upgradeParams)
+	if err := command.RunExecutable("helm", upgradeParams...); err != nil {
+		collectHelmDiagnostics(config, command, stdout)
+		if config.RollbackOnFailure {
+			rollbackHelmDeployment(config, command, stdout)
+		}
+		return errors.Wrap(err, "Helm upgrade call failed")
+	}
+	return nil
+}
+
+// helmReleaseParams adds the namespace of the release (helm3 only, helm 2 uses the tiller namespace) and the kube context
+func helmReleaseParams(config kubernetesDeployOptions, params ...string) []string {
+	if config.DeployTool != "helm" {
+		params = append(params, "--namespace", config.Namespace)
+	}
+	if len(config.KubeContext) > 0 {
+		params = append(params, "--kube-context", config.KubeContext)
+	}
+	return params
+}
+
+// collectHelmDiagnostics writes the events and logs of the Deployments and StatefulSets of the release to the log
+func collectHelmDiagnostics(config kubernetesDeployOptions, command execRunner, stdout io.Writer) {
+	var manifest bytes.Buffer
+	command.Stdout(&manifest)
+	err := command.RunExecutable("helm", helmReleaseParams(config, "get", "manifest", config.DeploymentName)...)
+	command.Stdout(stdout)
+	if err != nil {
+		log.Entry().WithError(err).Warnf("Failed to retrieve the manifest of release '%v'", config.DeploymentName)
+		return
+	}
+	workloads, err := kubernetesWorkloads(manifest.Bytes(), config.Namespace)
+	if err != nil {
+		log.Entry().WithError(err).Warnf("Failed to read the workloads of release '%v'", config.DeploymentName)
+		return
+	}
+
+	kubeParams := []string{"--insecure-skip-tls-verify=true", fmt.Sprintf("--namespace=%v", config.Namespace)}
+	if len(config.KubeContext) > 0 {
+		kubeParams = append(kubeParams, fmt.Sprintf("--context=%v", config.KubeContext))
+	}
+	collectKubernetesDiagnostics(kubeParams, workloads, command)
+}
+
+// rollbackHelmDeployment rolls back the release to its last deployed revision
+func rollbackHelmDeployment(config kubernetesDeployOptions, command execRunner, stdout io.Writer) {
+	revision, err := previousHelmRevision(config, command, stdout)
+	if err != nil {
+		log.Entry().WithError(err).Warnf("Rollback of release '%v' failed", config.DeploymentName)
+		return
+	}
+	if revision == 0 {
+		log.Entry().Infof("Release '%v' has no deployed revision to roll back to", config.DeploymentName)
+		return
+	}
+	log.Entry().Infof("Rolling back release '%v' to revision %v", config.DeploymentName, revision)
+	if err := command.RunExecutable("helm", helmReleaseParams(config, "rollback", config.DeploymentName, strconv.Itoa(revision))...); err != nil {
+		log.Entry().WithError(err).Warnf("Rollback of release '%v' failed", config.DeploymentName)
+	}
+}
+
+// previousHelmRevision returns the latest deployed or superseded revision of the release as listed by helm history,
+// helm 2 does not support revision 0 for the previous revision
+func previousHelmRevision(config kubernetesDeployOptions, command execRunner, stdout io.Writer) (int, error) {
+	var history bytes.Buffer
+	command.Stdout(&history)
+	err := command.RunExecutable("helm", helmReleaseParams(config, "history", config.DeploymentName, "--output", "json")...)
+	command.Stdout(stdout)
+	if err != nil {
+		return 0, errors.Wrap(err, "Retrieving the release history failed")
+	}
+	var revisions []struct {
+		Revision int    `json:"revision"`
+		Status   string `json:"status"`
+	}
+	if err := json.Unmarshal(history.Bytes(), &revisions); err != nil {
+		return 0, errors.Wrap(err, "Reading the release history failed")
+	}
+	previous := 0
+	for _, revision := range revisions {
+		// helm 2 reports the status in upper case
+		status := strings.ToLower(revision.Status)
+		if (status == "deployed" || status == "superseded") && revision.Revision > previous {
+			previous = revision.Revision
+		}
+	}
+	return previous, nil
+}
+
+// dockerConfigJSON returns the base64 encoded docker config of the container registry as calculated by kubectl
+func dockerConfigJSON(config kubernetesDeployOptions, containerRegistry string, command execRunner, stdout io.Writer) (string, error) {
 	var dockerRegistrySecret bytes.Buffer
 	command.Stdout(&dockerRegistrySecret)
+	defer command.Stdout(stdout)
 	kubeParams := []string{
 		"--insecure-skip-tls-verify=true",
 		"create",
@@ -75,11 +242,9 @@ func runHelmDeploy(config kubernetesDeployOptions, command execRunner, stdout io
 		"--output=json",
 	}
 	log.Entry().Infof("Calling kubectl create secret --dry-run=true ...")
-	log.Entry().Debugf("kubectl parameters %v", kubeParams)
 	if err := command.RunExecutable("kubectl", kubeParams...); err != nil {
-		log.Entry().WithError(err).Fatal("Retrieving Docker config via kubectl failed")
+		return "", errors.Wrap(err, "Retrieving Docker config via kubectl failed")
 	}
-	log.Entry().Debugf("Secret created: %v", string(dockerRegistrySecret.Bytes()))
 
 	var dockerRegistrySecretData struct {
 		Kind string `json:"kind"`
@@ -89,45 +254,40 @@ func runHelmDeploy(config kubernetesDeployOptions, command execRunner, stdout io
 		Type string `json:"type"`
 	}
 	if err := json.Unmarshal(dockerRegistrySecret.Bytes(), &dockerRegistrySecretData); err != nil {
-		log.Entry().WithError(err).Fatal("Reading docker registry secret json failed")
-	}
-
-	ingressHosts := ""
-	for i, h := range config.IngressHosts {
-		ingressHosts += fmt.Sprintf(",ingress.hosts[%v]=%v", i, h)
+		return "", errors.Wrap(err, "Reading docker registry secret json failed")
 	}
+	return dockerRegistrySecretData.Data.DockerConfJSON, nil
+}
 
-	upgradeParams := []string{
-		"upgrade",
-		config.DeploymentName,
-		config.ChartPath,
-		"--install",
-		"--force",
-		"--namespace",
-		config.Namespace,
-		"--wait",
-		"--timeout",
-		strconv.Itoa(config.HelmDeployWaitSeconds),
-		"--set",
-		fmt.Sprintf("image.repository=%v/%v,image.tag=%v,secret.dockerconfigjson=%v%v", containerRegistry, containerImageName, containerImageTag, dockerRegistrySecretData.Data.DockerConfJSON, ingressHosts),
+// mergeHelmValues merges the values into target, nested maps are merged recursively
+func mergeHelmValues(target, values map[string]interface{}) {
+	for key, value := range values {
+		targetMap, targetIsMap := target[key].(map[string]interface{})
+		valueMap, valueIsMap := value.(map[string]interface{})
+		if targetIsMap && valueIsMap {
+			mergeHelmValues(targetMap, valueMap)
+			continue
+		}
+		target[key] = value
 	}
+}
 
-	if len(config.KubeContext) > 0 {
-		upgradeParams = append(upgradeParams, "--kube-context", config.KubeContext)
+// writeHelmValuesFile writes the values to a temporary file, which has to be removed by the caller
+func writeHelmValuesFile(values map[string]interface{}) (string, error) {
+	content, err := yaml.Marshal(values)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to generate helm values")
 	}
-
-	if len(config.AdditionalParameters) > 0 {
-		upgradeParams = append(upgradeParams, config.AdditionalParameters...)
+	valuesFile, err := ioutil.TempFile("", "values-*.yaml")
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to create helm values file")
 	}
-
-	command.Stdout(stdout)
-	log.Entry().Info("Calling helm upgrade ...")
-	log.Entry().Debugf("Helm parameters %v", upgradeParams)
-	command.RunExecutable("helm", upgradeParams...)
-	if err := command.RunExecutable("helm", upgradeParams...); err != nil {
-		log.Entry().WithError(err).Fatal("Helm upgrade call failed")
+	defer valuesFile.Close()
+	if _, err := valuesFile.Write(content); err != nil {
+		os.Remove(valuesFile.Name())
+		return "", errors.Wrap(err, "Failed to write helm values file")
 	}
-
+	return valuesFile.Name(), nil
 }
 
 func runKubectlDeploy(config kubernetesDeployOptions, command execRunner) {
@@ -181,21 +341,36 @@ func runKubectlDeploy(config kubernetesDeployOptions, command execRunner) {
 		}
 	}
 
-	appTemplate, err := ioutil.ReadFile(config.AppTemplate)
+	templates, err := kubernetesManifestFiles(append([]string{config.AppTemplate}, config.AppTemplates...))
 	if err != nil {
-		log.Entry().WithError(err).Fatalf("Error when reading appTemplate '%v'", config.AppTemplate)
+		log.Entry().WithError(err).Fatal("Error when collecting the app templates")
 	}
-
-	// Update image name in deployment yaml, expects placeholder like 'image: <image-name>'
-	re := regexp.MustCompile(`image:[ ]*<image-name>`)
-	appTemplate = []byte(re.ReplaceAllString(string(appTemplate), fmt.Sprintf("image: %v/%v", containerRegistry, config.Image)))
-
-	err = ioutil.WriteFile(config.AppTemplate, appTemplate, 0700)
+	containerImageName, containerImageTag, err := splitFullImageName(config.Image)
+	if err != nil {
+		log.Entry().WithError(err).Fatalf("Container image '%v' incorrect", config.Image)
+	}
+	manifest, err := renderKubernetesManifests(templates, kubernetesManifestData{
+		Image:           fmt.Sprintf("%v/%v", containerRegistry, config.Image),
+		ImageRepository: fmt.Sprintf("%v/%v", containerRegistry, containerImageName),
+		ImageTag:        containerImageTag,
+		ImageDigest:     config.ImageDigest,
+		Namespace:       config.Namespace,
+		DeploymentName:  config.DeploymentName,
+		Values:          config.ValuesMap,
+	}, GeneralConfig.EnvRootPath)
 	if err != nil {
-		log.Entry().WithError(err).Fatalf("Error when updating appTemplate '%v'", config.AppTemplate)
+		log.Entry().WithError(err).Fatal("Error when rendering the app templates")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(config.RenderedManifest), 0755); err != nil {
+		log.Entry().WithError(err).Fatalf("Error when creating the directory of '%v'", config.RenderedManifest)
 	}
+	if err := ioutil.WriteFile(config.RenderedManifest, manifest, 0644); err != nil {
+		log.Entry().WithError(err).Fatalf("Error when writing the rendered manifest '%v'", config.RenderedManifest)
+	}
+	log.Entry().Infof("Rendered %v app template(s) to '%v'", len(templates), config.RenderedManifest)
 
-	kubeApplyParams := append(kubeParams, "apply", "--filename", config.AppTemplate)
+	kubeApplyParams := append(kubeParams, "apply", "--filename", config.RenderedManifest)
 	if len(config.AdditionalParameters) > 0 {
 		kubeApplyParams = append(kubeApplyParams, config.AdditionalParameters...)
 	}
@@ -204,6 +379,202 @@ func runKubectlDeploy(config kubernetesDeployOptions, command execRunner) {
 		log.Entry().Debugf("Running kubectl with following parameters: %v", kubeApplyParams)
 		log.Entry().WithError(err).Fatal("Deployment with kubectl failed.")
 	}
+
+	if config.WaitForRollout {
+		workloads, err := kubernetesWorkloads(manifest, config.Namespace)
+		if err != nil {
+			log.Entry().WithError(err).Fatalf("Error when reading the workloads of '%v'", config.RenderedManifest)
+		}
+		if err := waitForKubernetesRollout(config, kubeParams, workloads, command); err != nil {
+			log.Entry().WithError(err).Fatal("Deployment with kubectl failed.")
+		}
+	}
+}
+
+// kubernetesWorkload is a Deployment or StatefulSet of the deployed manifest
+type kubernetesWorkload struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+func (w kubernetesWorkload) String() string {
+	return fmt.Sprintf("%v/%v", strings.ToLower(w.Kind), w.Name)
+}
+
+// kubernetesWorkloads returns the Deployments and StatefulSets of the multi-document manifest,
+// resources without namespace are deployed to the given namespace
+func kubernetesWorkloads(manifest []byte, namespace string) ([]kubernetesWorkload, error) {
+	workloads := []kubernetesWorkload{}
+	for i, document := range yamlDocumentSeparator.Split(string(manifest), -1) {
+		var resource struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		}
+		if err := yaml.Unmarshal([]byte(document), &resource); err != nil {
+			return nil, fmt.Errorf("document %v is not a valid Kubernetes resource: %w", i+1, err)
+		}
+		if (resource.Kind != "Deployment" && resource.Kind != "StatefulSet") || len(resource.Metadata.Name) == 0 {
+			continue
+		}
+		workload := kubernetesWorkload{Kind: resource.Kind, Name: resource.Metadata.Name, Namespace: resource.Metadata.Namespace}
+		if len(workload.Namespace) == 0 {
+			workload.Namespace = namespace
+		}
+		workloads = append(workloads, workload)
+	}
+	return workloads, nil
+}
+
+// waitForKubernetesRollout waits for the rollout of the workloads. For failed rollouts the events and logs
+// are collected and, if configured, the workloads are rolled back.
+func waitForKubernetesRollout(config kubernetesDeployOptions, kubeParams []string, workloads []kubernetesWorkload, command execRunner) error {
+	failed := []kubernetesWorkload{}
+	for _, workload := range workloads {
+		log.Entry().Infof("Waiting for rollout of %v", workload)
+		rolloutParams := kubernetesWorkloadParams(kubeParams, workload, "rollout", "status", workload.String(), fmt.Sprintf("--timeout=%vs", config.RolloutTimeoutSeconds))
+		if err := command.RunExecutable("kubectl", rolloutParams...); err != nil {
+			log.Entry().WithError(err).Errorf("Rollout of %v failed", workload)
+			failed = append(failed, workload)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	collectKubernetesDiagnostics(kubeParams, failed, command)
+	if config.RollbackOnFailure {
+		for _, workload := range failed {
+			log.Entry().Infof("Rolling back %v", workload)
+			if err := command.RunExecutable("kubectl", kubernetesWorkloadParams(kubeParams, workload, "rollout", "undo", workload.String())...); err != nil {
+				log.Entry().WithError(err).Warnf("Rollback of %v failed", workload)
+			}
+		}
+	}
+
+	names := []string{}
+	for _, workload := range failed {
+		names = append(names, workload.String())
+	}
+	return fmt.Errorf("rollout of %v failed", strings.Join(names, ", "))
+}
+
+// collectKubernetesDiagnostics writes the events and logs of the workloads to the log
+func collectKubernetesDiagnostics(kubeParams []string, workloads []kubernetesWorkload, command execRunner) {
+	for _, workload := range workloads {
+		log.Entry().Infof("Collecting events and logs of %v", workload)
+		if err := command.RunExecutable("kubectl", kubernetesWorkloadParams(kubeParams, workload, "describe", workload.String())...); err != nil {
+			log.Entry().WithError(err).Warnf("Failed to describe %v", workload)
+		}
+		if err := command.RunExecutable("kubectl", kubernetesWorkloadParams(kubeParams, workload, "logs", workload.String(), "--all-containers=true", "--tail=100")...); err != nil {
+			log.Entry().WithError(err).Warnf("Failed to retrieve the logs of %v", workload)
+		}
+	}
+}
+
+// kubernetesWorkloadParams returns the kubectl parameters for the workload, the last --namespace flag wins,
+// so the namespace of the workload overrides the one of the step
+func kubernetesWorkloadParams(kubeParams []string, workload kubernetesWorkload, params ...string) []string {
+	result := append([]string{}, kubeParams...)
+	result = append(result, fmt.Sprintf("--namespace=%v", workload.Namespace))
+	return append(result, params...)
+}
+
+// kubernetesManifestData is available within the app templates
+type kubernetesManifestData struct {
+	// Image is the full image name including registry and tag
+	Image           string
+	ImageRepository string
+	ImageTag        string
+	ImageDigest     string
+	Namespace       string
+	DeploymentName  string
+	// Values contains the valuesMap of the step configuration
+	Values map[string]interface{}
+}
+
+var yamlDocumentSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+// kubernetesManifestFiles returns the app templates, directories are replaced by the YAML files they contain
+func kubernetesManifestFiles(paths []string) ([]string, error) {
+	files := []string{}
+	for _, path := range paths {
+		if len(path) == 0 {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("app template '%v' not found: %w", path, err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		directoryFiles := []string{}
+		for _, pattern := range []string{"**/*.yaml", "**/*.yml"} {
+			matches, err := doublestar.Glob(filepath.Join(path, pattern))
+			if err != nil {
+				return nil, fmt.Errorf("failed to list app templates of directory '%v': %w", path, err)
+			}
+			directoryFiles = append(directoryFiles, matches...)
+		}
+		sort.Strings(directoryFiles)
+		files = append(files, directoryFiles...)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no app template found, please configure appTemplate")
+	}
+	return files, nil
+}
+
+// renderKubernetesManifests renders the app templates as Go templates and combines them into a multi-document YAML
+func renderKubernetesManifests(templates []string, data kubernetesManifestData, envRootPath string) ([]byte, error) {
+	funcs := template.FuncMap{
+		// cpe returns a value of the commonPipelineEnvironment, e.g. {{cpe "git/commitId"}}
+		"cpe": func(name string) string {
+			return piperenv.GetResourceParameter(envRootPath, "commonPipelineEnvironment", filepath.FromSlash(name))
+		},
+	}
+	// legacy placeholder of app templates which do not use Go templates
+	imagePlaceholder := regexp.MustCompile(`image:[ ]*<image-name>`)
+
+	var manifest bytes.Buffer
+	for _, file := range templates {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read app template '%v': %w", file, err)
+		}
+		tmpl, err := template.New(filepath.Base(file)).Funcs(funcs).Option("missingkey=error").Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse app template '%v': %w", file, err)
+		}
+		var rendered bytes.Buffer
+		if err := tmpl.Execute(&rendered, data); err != nil {
+			return nil, fmt.Errorf("failed to render app template '%v': %w", file, err)
+		}
+		content = imagePlaceholder.ReplaceAll(rendered.Bytes(), []byte("image: "+data.Image))
+
+		for i, document := range yamlDocumentSeparator.Split(string(content), -1) {
+			if len(strings.TrimSpace(document)) == 0 {
+				continue
+			}
+			var parsed interface{}
+			if err := yaml.Unmarshal([]byte(document), &parsed); err != nil {
+				return nil, fmt.Errorf("document %v of app template '%v' is not valid YAML: %w", i+1, file, err)
+			}
+			if manifest.Len() > 0 {
+				manifest.WriteString("---\n")
+			}
+			manifest.WriteString(strings.TrimLeft(document, "\n"))
+			if !strings.HasSuffix(document, "\n") {
+				manifest.WriteString("\n")
+			}
+		}
+	}
+	return manifest.Bytes(), nil
 }
 
 func splitRegistryURL(registryURL string) (protocol, registry string, err error) {
@@ -14,25 +14,33 @@ import (
 )
 
 type kubernetesDeployOptions struct {
-	AdditionalParameters       []string `json:"additionalParameters,omitempty"`
-	APIServer                  string   `json:"apiServer,omitempty"`
-	AppTemplate                string   `json:"appTemplate,omitempty"`
-	ChartPath                  string   `json:"chartPath,omitempty"`
-	ContainerRegistryPassword  string   `json:"containerRegistryPassword,omitempty"`
-	ContainerRegistryURL       string   `json:"containerRegistryUrl,omitempty"`
-	ContainerRegistryUser      string   `json:"containerRegistryUser,omitempty"`
-	ContainerRegistrySecret    string   `json:"containerRegistrySecret,omitempty"`
-	CreateDockerRegistrySecret bool     `json:"createDockerRegistrySecret,omitempty"`
-	DeploymentName             string   `json:"deploymentName,omitempty"`
-	DeployTool                 string   `json:"deployTool,omitempty"`
-	HelmDeployWaitSeconds      int      `json:"helmDeployWaitSeconds,omitempty"`
-	Image                      string   `json:"image,omitempty"`
-	IngressHosts               []string `json:"ingressHosts,omitempty"`
-	KubeConfig                 string   `json:"kubeConfig,omitempty"`
-	KubeContext                string   `json:"kubeContext,omitempty"`
-	KubeToken                  string   `json:"kubeToken,omitempty"`
-	Namespace                  string   `json:"namespace,omitempty"`
-	TillerNamespace            string   `json:"tillerNamespace,omitempty"`
+	AdditionalParameters       []string               `json:"additionalParameters,omitempty"`
+	APIServer                  string                 `json:"apiServer,omitempty"`
+	AppTemplate                string                 `json:"appTemplate,omitempty"`
+	AppTemplates               []string               `json:"appTemplates,omitempty"`
+	ChartPath                  string                 `json:"chartPath,omitempty"`
+	ContainerRegistryPassword  string                 `json:"containerRegistryPassword,omitempty"`
+	ContainerRegistryURL       string                 `json:"containerRegistryUrl,omitempty"`
+	ContainerRegistryUser      string                 `json:"containerRegistryUser,omitempty"`
+	ContainerRegistrySecret    string                 `json:"containerRegistrySecret,omitempty"`
+	CreateDockerRegistrySecret bool                   `json:"createDockerRegistrySecret,omitempty"`
+	DeploymentName             string                 `json:"deploymentName,omitempty"`
+	DeployTool                 string                 `json:"deployTool,omitempty"`
+	HelmDeployWaitSeconds      int                    `json:"helmDeployWaitSeconds,omitempty"`
+	HelmValues                 []string               `json:"helmValues,omitempty"`
+	Image                      string                 `json:"image,omitempty"`
+	ImageDigest                string                 `json:"imageDigest,omitempty"`
+	IngressHosts               []string               `json:"ingressHosts,omitempty"`
+	KubeConfig                 string                 `json:"kubeConfig,omitempty"`
+	KubeContext                string                 `json:"kubeContext,omitempty"`
+	KubeToken                  string                 `json:"kubeToken,omitempty"`
+	Namespace                  string                 `json:"namespace,omitempty"`
+	RenderedManifest           string                 `json:"renderedManifest,omitempty"`
+	RollbackOnFailure          bool                   `json:"rollbackOnFailure,omitempty"`
+	RolloutTimeoutSeconds      int                    `json:"rolloutTimeoutSeconds,omitempty"`
+	TillerNamespace            string                 `json:"tillerNamespace,omitempty"`
+	ValuesMap                  map[string]interface{} `json:"valuesMap,omitempty"`
+	WaitForRollout             bool                   `json:"waitForRollout,omitempty"`
 }
 
 // KubernetesDeployCommand Deployment to Kubernetes test or production namespace within the specified Kubernetes cluster.
@@ -56,15 +64,55 @@ func KubernetesDeployCommand() *cobra.Command {
 Following helm command will be executed by default:
 
 ` + "`" + `` + "`" + `` + "`" + `
-helm upgrade <deploymentName> <chartPath> --install --force --namespace <namespace> --wait --timeout <helmDeployWaitSeconds> --set "image.repository=<yourRegistry>/<yourImageName>,image.tag=<yourImageTag>,secret.dockerconfigjson=<dockerSecret>,ingress.hosts[0]=<ingressHosts[0]>,,ingress.hosts[1]=<ingressHosts[1]>,...
+helm upgrade <deploymentName> <chartPath> --install --force --namespace <namespace> --wait --timeout <helmDeployWaitSeconds> --values <helmValues[0]> ... --values <generatedValues>
+` + "`" + `` + "`" + `` + "`" + `
+
+With ` + "`" + `deployTool: helm3` + "`" + ` Helm 3 is used, which does not need an initialization and no Tiller:
+
+` + "`" + `` + "`" + `` + "`" + `
+helm upgrade <deploymentName> <chartPath> --install --namespace <namespace> --create-namespace --wait --timeout <helmDeployWaitSeconds>s --values <helmValues[0]> ... --values <generatedValues>
+` + "`" + `` + "`" + `` + "`" + `
+
+The generated values file contains the following values, it is passed last and thus takes precedence over the files of ` + "`" + `helmValues` + "`" + `:
+
+` + "`" + `` + "`" + `` + "`" + `
+image:
+  repository: <yourRegistry>/<yourImageName>
+  tag: <yourImageTag>
+secret:
+  dockerconfigjson: <dockerSecret>
+ingress:
+  hosts: <ingressHosts>
 ` + "`" + `` + "`" + `` + "`" + `
 
 * ` + "`" + `yourRegistry` + "`" + ` will be retrieved from ` + "`" + `containerRegistryUrl` + "`" + `
 * ` + "`" + `yourImageName` + "`" + `, ` + "`" + `yourImageTag` + "`" + ` will be retrieved from ` + "`" + `image` + "`" + `
-* ` + "`" + `dockerSecret` + "`" + ` will be calculated with a call to ` + "`" + `kubectl create secret docker-registry regsecret --docker-server=<yourRegistry> --docker-username=<containerRegistryUser> --docker-password=<containerRegistryPassword> --dry-run=true --output=json'` + "`" + ``,
+* ` + "`" + `dockerSecret` + "`" + ` will be calculated with a call to ` + "`" + `kubectl create secret docker-registry regsecret --docker-server=<yourRegistry> --docker-username=<containerRegistryUser> --docker-password=<containerRegistryPassword> --dry-run=true --output=json'` + "`" + `, it is only added if registry credentials are provided
+* additional values can be defined via ` + "`" + `valuesMap` + "`" + `
+
+## kubectl
+The app templates (` + "`" + `appTemplate` + "`" + ` and ` + "`" + `appTemplates` + "`" + `) are rendered as [Go templates](https://golang.org/pkg/text/template/) into a single multi-document YAML file ` + "`" + `renderedManifest` + "`" + `,
+which is deployed via ` + "`" + `kubectl apply --filename <renderedManifest>` + "`" + `. The following values are available within the templates:
+
+* ` + "`" + `{{.Image}}` + "`" + `: full image name ` + "`" + `<yourRegistry>/<yourImageName>:<yourImageTag>` + "`" + `
+* ` + "`" + `{{.ImageRepository}}` + "`" + `, ` + "`" + `{{.ImageTag}}` + "`" + ` and ` + "`" + `{{.ImageDigest}}` + "`" + `
+* ` + "`" + `{{.Namespace}}` + "`" + ` and ` + "`" + `{{.DeploymentName}}` + "`" + `
+* ` + "`" + `{{.Values}}` + "`" + `: the ` + "`" + `valuesMap` + "`" + `, e.g. ` + "`" + `{{.Values.replicaCount}}` + "`" + `
+* ` + "`" + `{{cpe "<name>"}}` + "`" + `: values of the ` + "`" + `commonPipelineEnvironment` + "`" + `, e.g. ` + "`" + `{{cpe "git/commitId"}}` + "`" + `
+
+The placeholder ` + "`" + `image: <image-name>` + "`" + ` is still replaced by the full image name.
+
+After the deployment the step waits via ` + "`" + `kubectl rollout status` + "`" + ` for the rollout of all Deployments and StatefulSets of the rendered manifest (` + "`" + `waitForRollout` + "`" + `).
+For failed rollouts the events (` + "`" + `kubectl describe` + "`" + `) and the logs of the workloads are written to the log and,
+with ` + "`" + `rollbackOnFailure` + "`" + `, the workloads are rolled back via ` + "`" + `kubectl rollout undo` + "`" + ` before the step fails.
+
+If ` + "`" + `helm upgrade` + "`" + ` fails, the events and logs of the Deployments and StatefulSets of the release (` + "`" + `helm get manifest` + "`" + `) are written to the log as well and,
+with ` + "`" + `rollbackOnFailure` + "`" + `, the release is rolled back via ` + "`" + `helm rollback` + "`" + ` to its last deployed revision according to ` + "`" + `helm history` + "`" + `.
+Without ` + "`" + `rollbackOnFailure` + "`" + `, ` + "`" + `helm upgrade` + "`" + ` of helm3 is called with ` + "`" + `--atomic` + "`" + `, i.e. helm rolls back the failed release itself.`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			startTime = time.Now()
 			log.SetStepName("kubernetesDeploy")
+			log.RegisterFatalHook("kubernetesDeploy", ".")
 			log.SetVerbose(GeneralConfig.Verbose)
 			return PrepareConfig(cmd, &metadata, "kubernetesDeploy", &stepConfig, config.OpenPiperFile)
 		},
@@ -73,12 +121,18 @@ helm upgrade <deploymentName> <chartPath> --install --force --namespace <namespa
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				if telemetryData.ErrorCode != "0" {
+					telemetryData.ErrorCategory = log.GetErrorCategory().String()
+					telemetryData.ErrorCode = fmt.Sprintf("%v", log.GetErrorCategory().ExitCode())
+				}
 				telemetry.Send(&telemetryData)
 			}
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetry.Initialize(GeneralConfig.NoTelemetry, "kubernetesDeploy")
+			log.StartGroup("Execution")
 			kubernetesDeploy(stepConfig, &telemetryData)
+			log.EndGroup()
 			telemetryData.ErrorCode = "0"
 		},
 	}
@@ -90,7 +144,8 @@ helm upgrade <deploymentName> <chartPath> --install --force --namespace <namespa
 func addKubernetesDeployFlags(cmd *cobra.Command, stepConfig *kubernetesDeployOptions) {
 	cmd.Flags().StringSliceVar(&stepConfig.AdditionalParameters, "additionalParameters", []string{}, "Defines additional parameters for \"helm install\" or \"kubectl apply\" command.")
 	cmd.Flags().StringVar(&stepConfig.APIServer, "apiServer", os.Getenv("PIPER_apiServer"), "Defines the Url of the API Server of the Kubernetes cluster.")
-	cmd.Flags().StringVar(&stepConfig.AppTemplate, "appTemplate", os.Getenv("PIPER_appTemplate"), "Defines the filename for the kubernetes app template (e.g. k8s_apptemplate.yaml)")
+	cmd.Flags().StringVar(&stepConfig.AppTemplate, "appTemplate", os.Getenv("PIPER_appTemplate"), "Defines the filename for the kubernetes app template (e.g. k8s_apptemplate.yaml). The app template is rendered as Go template, see the description of the step.")
+	cmd.Flags().StringSliceVar(&stepConfig.AppTemplates, "appTemplates", []string{}, "List of additional app templates for deployments using kubectl. Directories are replaced by the YAML files they contain.")
 	cmd.Flags().StringVar(&stepConfig.ChartPath, "chartPath", os.Getenv("PIPER_chartPath"), "Defines the chart path for deployments using helm.")
 	cmd.Flags().StringVar(&stepConfig.ContainerRegistryPassword, "containerRegistryPassword", os.Getenv("PIPER_containerRegistryPassword"), "Password for container registry access - typically provided by the CI/CD environment.")
 	cmd.Flags().StringVar(&stepConfig.ContainerRegistryURL, "containerRegistryUrl", os.Getenv("PIPER_containerRegistryUrl"), "http(s) url of the Container registry.")
@@ -100,19 +155,25 @@ func addKubernetesDeployFlags(cmd *cobra.Command, stepConfig *kubernetesDeployOp
 	cmd.Flags().StringVar(&stepConfig.DeploymentName, "deploymentName", os.Getenv("PIPER_deploymentName"), "Defines the name of the deployment.")
 	cmd.Flags().StringVar(&stepConfig.DeployTool, "deployTool", "kubectl", "Defines the tool which should be used for deployment.")
 	cmd.Flags().IntVar(&stepConfig.HelmDeployWaitSeconds, "helmDeployWaitSeconds", 300, "Number of seconds before helm deploy returns.")
+	cmd.Flags().StringSliceVar(&stepConfig.HelmValues, "helmValues", []string{}, "List of helm values files passed to the deployment, e.g. `values-production.yaml`.")
 	cmd.Flags().StringVar(&stepConfig.Image, "image", os.Getenv("PIPER_image"), "Full name of the image to be deployed.")
+	cmd.Flags().StringVar(&stepConfig.ImageDigest, "imageDigest", os.Getenv("PIPER_imageDigest"), "Digest of the image to be deployed, it is available as `{{.ImageDigest}}` within the app templates.")
 	cmd.Flags().StringSliceVar(&stepConfig.IngressHosts, "ingressHosts", []string{}, "List of ingress hosts to be exposed via helm deployment.")
 	cmd.Flags().StringVar(&stepConfig.KubeConfig, "kubeConfig", os.Getenv("PIPER_kubeConfig"), "Defines the path to the \"kubeconfig\" file.")
 	cmd.Flags().StringVar(&stepConfig.KubeContext, "kubeContext", os.Getenv("PIPER_kubeContext"), "Defines the context to use from the \"kubeconfig\" file.")
 	cmd.Flags().StringVar(&stepConfig.KubeToken, "kubeToken", os.Getenv("PIPER_kubeToken"), "Contains the id_token used by kubectl for authentication. Consider using kubeConfig parameter instead.")
 	cmd.Flags().StringVar(&stepConfig.Namespace, "namespace", "default", "Defines the target Kubernetes namespace for the deployment.")
-	cmd.Flags().StringVar(&stepConfig.TillerNamespace, "tillerNamespace", os.Getenv("PIPER_tillerNamespace"), "Defines optional tiller namespace for deployments using helm.")
+	cmd.Flags().StringVar(&stepConfig.RenderedManifest, "renderedManifest", ".pipeline/kubernetesDeploy/manifest.yaml", "File the rendered app templates are written to for deployments using kubectl. The app templates themselves are not changed.")
+	cmd.Flags().BoolVar(&stepConfig.RollbackOnFailure, "rollbackOnFailure", false, "Defines if failed deployments are rolled back, via `kubectl rollout undo` for deployments using kubectl and via `helm rollback` to the last deployed revision for deployments using helm and helm3.")
+	cmd.Flags().IntVar(&stepConfig.RolloutTimeoutSeconds, "rolloutTimeoutSeconds", 300, "Number of seconds to wait for the rollout of each Deployment and StatefulSet for deployments using kubectl.")
+	cmd.Flags().StringVar(&stepConfig.TillerNamespace, "tillerNamespace", os.Getenv("PIPER_tillerNamespace"), "Defines optional tiller namespace for deployments using helm. Not used for helm3.")
+	cmd.Flags().BoolVar(&stepConfig.WaitForRollout, "waitForRollout", true, "Defines if the step waits for the rollout of the Deployments and StatefulSets of the rendered manifest for deployments using kubectl.")
 
 	cmd.MarkFlagRequired("chartPath")
 	cmd.MarkFlagRequired("containerRegistryUrl")
 	cmd.MarkFlagRequired("deploymentName")
-	cmd.MarkFlagRequired("deployTool")
 	cmd.MarkFlagRequired("image")
+	cmd.RegisterFlagCompletionFunc("deployTool", CompleteValues("kubectl", "helm", "helm3"))
 }
 
 // retrieve step metadata
@@ -149,6 +210,14 @@ func kubernetesDeployMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{{Name: "k8sAppTemplate"}},
 					},
+					{
+						Name:        "appTemplates",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "chartPath",
 						ResourceRef: []config.ResourceReference{},
@@ -206,18 +275,27 @@ func kubernetesDeployMetadata() config.StepData {
 						Aliases:     []config.Alias{{Name: "helmDeploymentName"}},
 					},
 					{
-						Name:        "deployTool",
+						Name:           "deployTool",
+						ResourceRef:    []config.ResourceReference{},
+						Scope:          []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:           "string",
+						Mandatory:      false,
+						Aliases:        []config.Alias{},
+						PossibleValues: []interface{}{"kubectl", "helm", "helm3"},
+					},
+					{
+						Name:        "helmDeployWaitSeconds",
 						ResourceRef: []config.ResourceReference{},
 						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "string",
-						Mandatory:   true,
+						Type:        "int",
+						Mandatory:   false,
 						Aliases:     []config.Alias{},
 					},
 					{
-						Name:        "helmDeployWaitSeconds",
+						Name:        "helmValues",
 						ResourceRef: []config.ResourceReference{},
 						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
-						Type:        "int",
+						Type:        "[]string",
 						Mandatory:   false,
 						Aliases:     []config.Alias{},
 					},
@@ -229,6 +307,14 @@ func kubernetesDeployMetadata() config.StepData {
 						Mandatory:   true,
 						Aliases:     []config.Alias{{Name: "deployImage"}},
 					},
+					{
+						Name:        "imageDigest",
+						ResourceRef: []config.ResourceReference{{Name: "commonPipelineEnvironment", Param: "container/imageDigest"}},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "ingressHosts",
 						ResourceRef: []config.ResourceReference{},
@@ -269,6 +355,30 @@ func kubernetesDeployMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{{Name: "helmDeploymentNamespace"}, {Name: "k8sDeploymentNamespace"}},
 					},
+					{
+						Name:        "renderedManifest",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "rollbackOnFailure",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "rolloutTimeoutSeconds",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "tillerNamespace",
 						ResourceRef: []config.ResourceReference{},
@@ -277,6 +387,22 @@ func kubernetesDeployMetadata() config.StepData {
 						Mandatory:   false,
 						Aliases:     []config.Alias{{Name: "helmTillerNamespace"}},
 					},
+					{
+						Name:        "valuesMap",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "map[string]interface{}",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "waitForRollout",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 				},
 			},
 		},
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,20 +30,22 @@ func TestRunKubernetesDeploy(t *testing.T) {
 			AdditionalParameters:      []string{"--testParam", "testValue"},
 			KubeContext:               "testCluster",
 			Namespace:                 "deploymentNamespace",
+			TillerNamespace:           "tillerNamespace",
 		}
 
 		dockerConfigJSON := `{"kind": "Secret","data":{".dockerconfigjson": "ThisIsOurBase64EncodedSecret=="}}`
 
-		e := mock.ExecMockRunner{
-			StdoutReturn: map[string]string{
-				"kubectl --insecure-skip-tls-verify=true create secret docker-registry regsecret --docker-server=my.registry:55555 --docker-username=registryUser --docker-password=******** --dry-run=true --output=json": dockerConfigJSON,
-			},
+		e := helmMockRunner{}
+		e.StdoutReturn = map[string]string{
+			regexp.QuoteMeta("kubectl --insecure-skip-tls-verify=true create secret docker-registry regsecret --docker-server=my.registry:55555 --docker-username=registryUser --docker-password=******** --dry-run=true --output=json"): dockerConfigJSON,
 		}
 
 		var stdout bytes.Buffer
 
 		runKubernetesDeploy(opts, &e, &stdout)
 
+		assert.Equal(t, []string{"KUBECONFIG=", "TILLER_NAMESPACE=tillerNamespace"}, e.Env)
+
 		assert.Equal(t, "helm", e.Calls[0].Exec, "Wrong init command")
 		assert.Equal(t, []string{"init", "--client-only"}, e.Calls[0].Params, "Wrong init parameters")
 
@@ -54,19 +58,85 @@ func TestRunKubernetesDeploy(t *testing.T) {
 			"deploymentName",
 			"path/to/chart",
 			"--install",
-			"--force",
 			"--namespace",
 			"deploymentNamespace",
+			"--force",
 			"--wait",
 			"--timeout",
 			"400",
-			"--set",
-			"image.repository=my.registry:55555/path/to/Image,image.tag=latest,secret.dockerconfigjson=ThisIsOurBase64EncodedSecret==,ingress.hosts[0]=ingress.host1,ingress.hosts[1]=ingress.host2",
+			"--values",
+			e.valuesFile,
 			"--kube-context",
 			"testCluster",
 			"--testParam",
 			"testValue",
 		}, e.Calls[2].Params, "Wrong upgrade parameters")
+		assert.Len(t, e.Calls, 3)
+
+		assert.Equal(t, `image:
+  repository: my.registry:55555/path/to/Image
+  tag: latest
+ingress:
+  hosts:
+  - ingress.host1
+  - ingress.host2
+secret:
+  dockerconfigjson: ThisIsOurBase64EncodedSecret==
+`, e.values)
+		_, err := os.Stat(e.valuesFile)
+		assert.True(t, os.IsNotExist(err), "values file not removed")
+	})
+
+	t.Run("test helm3", func(t *testing.T) {
+		opts := kubernetesDeployOptions{
+			ContainerRegistryURL:  "https://my.registry:55555",
+			ChartPath:             "path/to/chart",
+			DeploymentName:        "deploymentName",
+			DeployTool:            "helm3",
+			HelmDeployWaitSeconds: 400,
+			HelmValues:            []string{"values1.yaml", "values2.yaml"},
+			Image:                 "path/to/Image:latest",
+			KubeConfig:            "kubeconfig.yaml",
+			Namespace:             "deploymentNamespace",
+			TillerNamespace:       "tillerNamespace",
+			ValuesMap: map[string]interface{}{
+				"replicaCount": 2,
+				"image":        map[string]interface{}{"pullPolicy": "Always"},
+			},
+		}
+
+		e := helmMockRunner{}
+		var stdout bytes.Buffer
+
+		runKubernetesDeploy(opts, &e, &stdout)
+
+		assert.Equal(t, []string{"KUBECONFIG=kubeconfig.yaml"}, e.Env)
+		assert.Equal(t, []mock.ExecCall{{Exec: "helm", Params: []string{
+			"upgrade",
+			"deploymentName",
+			"path/to/chart",
+			"--install",
+			"--namespace",
+			"deploymentNamespace",
+			"--create-namespace",
+			"--atomic",
+			"--wait",
+			"--timeout",
+			"400s",
+			"--values",
+			"values1.yaml",
+			"--values",
+			"values2.yaml",
+			"--values",
+			e.valuesFile,
+		}}}, e.Calls)
+
+		assert.Equal(t, `image:
+  pullPolicy: Always
+  repository: my.registry:55555/path/to/Image
+  tag: latest
+replicaCount: 2
+`, e.values)
 	})
 
 	t.Run("test kubectl - create secret/kubeconfig", func(t *testing.T) {
@@ -76,6 +146,7 @@ func TestRunKubernetesDeploy(t *testing.T) {
 
 		opts := kubernetesDeployOptions{
 			AppTemplate:                filepath.Join(dir, "test.yaml"),
+			RenderedManifest:           filepath.Join(dir, "rendered", "manifest.yaml"),
 			ContainerRegistryURL:       "https://my.registry:55555",
 			ContainerRegistryUser:      "registryUser",
 			ContainerRegistryPassword:  "********",
@@ -138,13 +209,17 @@ spec:
 			fmt.Sprintf("--context=%v", opts.KubeContext),
 			"apply",
 			"--filename",
-			opts.AppTemplate,
+			opts.RenderedManifest,
 			"--testParam",
 			"testValue",
 		}, e.Calls[2].Params, "kubectl parameters incorrect")
 
+		manifest, err := ioutil.ReadFile(opts.RenderedManifest)
+		assert.NoError(t, err)
+		assert.Contains(t, string(manifest), "my.registry:55555/path/to/Image:latest")
+
 		appTemplate, err := ioutil.ReadFile(opts.AppTemplate)
-		assert.Contains(t, string(appTemplate), "my.registry:55555/path/to/Image:latest")
+		assert.Equal(t, kubeYaml, string(appTemplate), "app template must not be changed")
 	})
 
 	t.Run("test kubectl - lookup secret/kubeconfig", func(t *testing.T) {
@@ -154,6 +229,7 @@ spec:
 
 		opts := kubernetesDeployOptions{
 			AppTemplate:                filepath.Join(dir, "test.yaml"),
+			RenderedManifest:           filepath.Join(dir, "rendered", "manifest.yaml"),
 			ContainerRegistryURL:       "https://my.registry:55555",
 			ContainerRegistryUser:      "registryUser",
 			ContainerRegistryPassword:  "********",
@@ -165,7 +241,7 @@ spec:
 			Namespace:                  "deploymentNamespace",
 		}
 
-		ioutil.WriteFile(opts.AppTemplate, []byte("testYaml"), 0755)
+		ioutil.WriteFile(opts.AppTemplate, []byte("kind: Deployment"), 0755)
 
 		e := mock.ExecMockRunner{}
 
@@ -187,7 +263,7 @@ spec:
 			fmt.Sprintf("--namespace=%v", opts.Namespace),
 			"apply",
 			"--filename",
-			opts.AppTemplate,
+			opts.RenderedManifest,
 		}, e.Calls[1].Params, "kubectl parameters incorrect")
 	})
 
@@ -199,6 +275,7 @@ spec:
 		opts := kubernetesDeployOptions{
 			APIServer:                 "https://my.api.server",
 			AppTemplate:               filepath.Join(dir, "test.yaml"),
+			RenderedManifest:          filepath.Join(dir, "manifest.yaml"),
 			ContainerRegistryURL:      "https://my.registry:55555",
 			ContainerRegistryUser:     "registryUser",
 			ContainerRegistryPassword: "********",
@@ -207,9 +284,11 @@ spec:
 			Image:                     "path/to/Image:latest",
 			KubeToken:                 "testToken",
 			Namespace:                 "deploymentNamespace",
+			RolloutTimeoutSeconds:     300,
+			WaitForRollout:            true,
 		}
 
-		ioutil.WriteFile(opts.AppTemplate, []byte("testYaml"), 0755)
+		ioutil.WriteFile(opts.AppTemplate, []byte("kind: Deployment\nmetadata:\n  name: app"), 0755)
 
 		e := mock.ExecMockRunner{
 			ShouldFailOnCommand: map[string]error{},
@@ -225,8 +304,351 @@ spec:
 			fmt.Sprintf("--token=%v", opts.KubeToken),
 			"apply",
 			"--filename",
-			opts.AppTemplate,
+			opts.RenderedManifest,
 		}, e.Calls[0].Params, "kubectl parameters incorrect")
+
+		assert.Equal(t, "kubectl", e.Calls[1].Exec, "Wrong rollout command")
+		assert.Equal(t, []string{
+			"--insecure-skip-tls-verify=true",
+			fmt.Sprintf("--namespace=%v", opts.Namespace),
+			fmt.Sprintf("--server=%v", opts.APIServer),
+			fmt.Sprintf("--token=%v", opts.KubeToken),
+			fmt.Sprintf("--namespace=%v", opts.Namespace),
+			"rollout",
+			"status",
+			"deployment/app",
+			"--timeout=300s",
+		}, e.Calls[1].Params, "kubectl parameters incorrect")
+	})
+}
+
+func TestRunHelmDeploy(t *testing.T) {
+	t.Run("values file is removed on failure", func(t *testing.T) {
+		opts := kubernetesDeployOptions{
+			ContainerRegistryURL: "https://my.registry:55555",
+			DeployTool:           "helm3",
+			ChartPath:            "path/to/chart",
+			DeploymentName:       "deploymentName",
+			Image:                "path/to/Image:latest",
+			Namespace:            "deploymentNamespace",
+		}
+
+		e := helmMockRunner{}
+		e.ShouldFailOnCommand = map[string]error{"helm upgrade": fmt.Errorf("upgrade failed")}
+		var stdout bytes.Buffer
+
+		err := runHelmDeploy(opts, &e, &stdout)
+
+		assert.EqualError(t, err, "Helm upgrade call failed: upgrade failed")
+		assert.NotEmpty(t, e.valuesFile)
+		_, err = os.Stat(e.valuesFile)
+		assert.True(t, os.IsNotExist(err), "values file has not been removed")
+	})
+
+	t.Run("events, logs and rollback on failure", func(t *testing.T) {
+		opts := kubernetesDeployOptions{
+			ContainerRegistryURL: "https://my.registry:55555",
+			DeployTool:           "helm3",
+			ChartPath:            "path/to/chart",
+			DeploymentName:       "deploymentName",
+			Image:                "path/to/Image:latest",
+			KubeContext:          "testCluster",
+			Namespace:            "deploymentNamespace",
+			RollbackOnFailure:    true,
+		}
+
+		e := helmMockRunner{}
+		e.ShouldFailOnCommand = map[string]error{"helm upgrade": fmt.Errorf("timed out waiting for the condition")}
+		e.StdoutReturn = map[string]string{
+			"helm get manifest": `---
+# Source: chart/templates/service.yaml
+kind: Service
+metadata:
+  name: app
+---
+# Source: chart/templates/deployment.yaml
+kind: Deployment
+metadata:
+  name: app`,
+			"helm history": `[{"revision":1,"status":"superseded"},{"revision":2,"status":"failed"}]`,
+		}
+		var stdout bytes.Buffer
+
+		err := runHelmDeploy(opts, &e, &stdout)
+
+		assert.EqualError(t, err, "Helm upgrade call failed: timed out waiting for the condition")
+		// the release is rolled back by the step, not by helm
+		assert.NotContains(t, e.Calls[0].Params, "--atomic")
+		kubeParams := []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "--context=testCluster", "--namespace=deploymentNamespace"}
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "helm", Params: []string{"get", "manifest", "deploymentName", "--namespace", "deploymentNamespace", "--kube-context", "testCluster"}},
+			{Exec: "kubectl", Params: append(append([]string{}, kubeParams...), "describe", "deployment/app")},
+			{Exec: "kubectl", Params: append(append([]string{}, kubeParams...), "logs", "deployment/app", "--all-containers=true", "--tail=100")},
+			{Exec: "helm", Params: []string{"history", "deploymentName", "--output", "json", "--namespace", "deploymentNamespace", "--kube-context", "testCluster"}},
+			{Exec: "helm", Params: []string{"rollback", "deploymentName", "1", "--namespace", "deploymentNamespace", "--kube-context", "testCluster"}},
+		}, e.Calls[1:])
+	})
+}
+
+// helmMockRunner captures the generated values file, which is removed after the deployment
+type helmMockRunner struct {
+	mock.ExecMockRunner
+	valuesFile string
+	values     string
+}
+
+func (m *helmMockRunner) RunExecutable(e string, p ...string) error {
+	if e == "helm" && len(p) > 0 && p[0] == "upgrade" {
+		for i, param := range p[:len(p)-1] {
+			if param == "--values" {
+				m.valuesFile = p[i+1]
+			}
+		}
+		values, _ := ioutil.ReadFile(m.valuesFile)
+		m.values = string(values)
+	}
+	return m.ExecMockRunner.RunExecutable(e, p...)
+}
+
+func TestKubernetesWorkloads(t *testing.T) {
+	manifest := []byte(`kind: Deployment
+metadata:
+  name: app
+---
+kind: Service
+metadata:
+  name: app
+---
+kind: StatefulSet
+metadata:
+  name: db
+  namespace: data
+`)
+
+	workloads, err := kubernetesWorkloads(manifest, "deploymentNamespace")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []kubernetesWorkload{
+		{Kind: "Deployment", Name: "app", Namespace: "deploymentNamespace"},
+		{Kind: "StatefulSet", Name: "db", Namespace: "data"},
+	}, workloads)
+	assert.Equal(t, "statefulset/db", workloads[1].String())
+}
+
+func TestWaitForKubernetesRollout(t *testing.T) {
+	kubeParams := []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace"}
+	workloads := []kubernetesWorkload{
+		{Kind: "Deployment", Name: "app", Namespace: "deploymentNamespace"},
+		{Kind: "StatefulSet", Name: "db", Namespace: "data"},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		e := mock.ExecMockRunner{}
+
+		err := waitForKubernetesRollout(kubernetesDeployOptions{RolloutTimeoutSeconds: 120}, kubeParams, workloads, &e)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "kubectl", Params: []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "--namespace=deploymentNamespace", "rollout", "status", "deployment/app", "--timeout=120s"}},
+			{Exec: "kubectl", Params: []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "--namespace=data", "rollout", "status", "statefulset/db", "--timeout=120s"}},
+		}, e.Calls)
+	})
+
+	t.Run("failure with rollback", func(t *testing.T) {
+		e := mock.ExecMockRunner{ShouldFailOnCommand: map[string]error{
+			"rollout status deployment/app": fmt.Errorf("timed out waiting for the condition"),
+			"logs deployment/app":           fmt.Errorf("container is waiting to start"),
+		}}
+
+		err := waitForKubernetesRollout(kubernetesDeployOptions{RolloutTimeoutSeconds: 120, RollbackOnFailure: true}, kubeParams, workloads, &e)
+
+		assert.EqualError(t, err, "rollout of deployment/app failed")
+		workloadParams := []string{"--insecure-skip-tls-verify=true", "--namespace=deploymentNamespace", "--namespace=deploymentNamespace"}
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "kubectl", Params: append(workloadParams, "describe", "deployment/app")},
+			{Exec: "kubectl", Params: append(workloadParams, "logs", "deployment/app", "--all-containers=true", "--tail=100")},
+			{Exec: "kubectl", Params: append(workloadParams, "rollout", "undo", "deployment/app")},
+		}, e.Calls[2:])
+	})
+
+	t.Run("failure without rollback", func(t *testing.T) {
+		e := mock.ExecMockRunner{ShouldFailOnCommand: map[string]error{
+			"rollout status": fmt.Errorf("timed out waiting for the condition"),
+		}}
+
+		err := waitForKubernetesRollout(kubernetesDeployOptions{RolloutTimeoutSeconds: 120}, kubeParams, workloads, &e)
+
+		assert.EqualError(t, err, "rollout of deployment/app, statefulset/db failed")
+		assert.Len(t, e.Calls, 6)
+		for _, call := range e.Calls {
+			assert.NotContains(t, call.Params, "undo")
+		}
+	})
+}
+
+func TestRollbackHelmDeployment(t *testing.T) {
+	t.Run("helm", func(t *testing.T) {
+		e := mock.ExecMockRunner{StdoutReturn: map[string]string{
+			"helm history": `[{"revision":1,"status":"SUPERSEDED"},{"revision":2,"status":"DEPLOYED"},{"revision":3,"status":"FAILED"}]`,
+		}}
+		var stdout bytes.Buffer
+
+		rollbackHelmDeployment(kubernetesDeployOptions{DeployTool: "helm", DeploymentName: "deploymentName", KubeContext: "testCluster"}, &e, &stdout)
+
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "helm", Params: []string{"history", "deploymentName", "--output", "json", "--kube-context", "testCluster"}},
+			{Exec: "helm", Params: []string{"rollback", "deploymentName", "2", "--kube-context", "testCluster"}},
+		}, e.Calls)
+	})
+
+	t.Run("helm3", func(t *testing.T) {
+		e := mock.ExecMockRunner{StdoutReturn: map[string]string{
+			"helm history": `[{"revision":1,"status":"superseded"},{"revision":2,"status":"superseded"},{"revision":3,"status":"failed"}]`,
+		}}
+		var stdout bytes.Buffer
+
+		rollbackHelmDeployment(kubernetesDeployOptions{DeployTool: "helm3", DeploymentName: "deploymentName", Namespace: "deploymentNamespace"}, &e, &stdout)
+
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "helm", Params: []string{"history", "deploymentName", "--output", "json", "--namespace", "deploymentNamespace"}},
+			{Exec: "helm", Params: []string{"rollback", "deploymentName", "2", "--namespace", "deploymentNamespace"}},
+		}, e.Calls)
+	})
+
+	t.Run("no deployed revision", func(t *testing.T) {
+		e := mock.ExecMockRunner{StdoutReturn: map[string]string{
+			"helm history": `[{"revision":1,"status":"failed"}]`,
+		}}
+		var stdout bytes.Buffer
+
+		rollbackHelmDeployment(kubernetesDeployOptions{DeployTool: "helm3", DeploymentName: "deploymentName", Namespace: "deploymentNamespace"}, &e, &stdout)
+
+		assert.Len(t, e.Calls, 1)
+	})
+}
+
+func TestRenderKubernetesManifests(t *testing.T) {
+	data := kubernetesManifestData{
+		Image:           "my.registry:55555/path/to/image:1.0",
+		ImageRepository: "my.registry:55555/path/to/image",
+		ImageTag:        "1.0",
+		ImageDigest:     "sha256:abc",
+		Namespace:       "deploymentNamespace",
+		DeploymentName:  "deploymentName",
+		Values:          map[string]interface{}{"replicaCount": 2},
+	}
+
+	t.Run("multiple templates", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		defer os.RemoveAll(dir)
+		assert.NoError(t, err, "Error when creating temp dir")
+		envRootPath := filepath.Join(dir, ".pipeline")
+		assert.NoError(t, piperenv.SetResourceParameter(envRootPath, "commonPipelineEnvironment", "git/commitId", "abc123"))
+
+		deployment := filepath.Join(dir, "deployment.yaml")
+		ioutil.WriteFile(deployment, []byte(`---
+kind: Deployment
+metadata:
+  name: {{.DeploymentName}}
+  namespace: {{.Namespace}}
+  annotations:
+    commit: {{cpe "git/commitId"}}
+spec:
+  replicas: {{.Values.replicaCount}}
+  template:
+    spec:
+      containers:
+      - image: {{.ImageRepository}}@{{.ImageDigest}}
+---
+kind: Service
+metadata:
+  name: {{.DeploymentName}}
+`), 0644)
+		legacy := filepath.Join(dir, "legacy.yaml")
+		ioutil.WriteFile(legacy, []byte("kind: Pod\nspec:\n  image: <image-name>"), 0644)
+
+		manifest, err := renderKubernetesManifests([]string{deployment, legacy}, data, envRootPath)
+
+		assert.NoError(t, err)
+		assert.Equal(t, `kind: Deployment
+metadata:
+  name: deploymentName
+  namespace: deploymentNamespace
+  annotations:
+    commit: abc123
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - image: my.registry:55555/path/to/image@sha256:abc
+---
+kind: Service
+metadata:
+  name: deploymentName
+---
+kind: Pod
+spec:
+  image: my.registry:55555/path/to/image:1.0
+`, string(manifest))
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		defer os.RemoveAll(dir)
+		assert.NoError(t, err, "Error when creating temp dir")
+		deployment := filepath.Join(dir, "deployment.yaml")
+		ioutil.WriteFile(deployment, []byte("replicas: {{.Values.replicas}}"), 0644)
+
+		_, err = renderKubernetesManifests([]string{deployment}, data, dir)
+
+		assert.Contains(t, err.Error(), fmt.Sprintf("failed to render app template '%v'", deployment))
+	})
+
+	t.Run("invalid YAML", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "")
+		defer os.RemoveAll(dir)
+		assert.NoError(t, err, "Error when creating temp dir")
+		deployment := filepath.Join(dir, "deployment.yaml")
+		ioutil.WriteFile(deployment, []byte("kind: Deployment\n---\nkind: [Service"), 0644)
+
+		_, err = renderKubernetesManifests([]string{deployment}, data, dir)
+
+		assert.Contains(t, err.Error(), fmt.Sprintf("document 2 of app template '%v' is not valid YAML", deployment))
+	})
+}
+
+func TestKubernetesManifestFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	defer os.RemoveAll(dir)
+	assert.NoError(t, err, "Error when creating temp dir")
+	os.MkdirAll(filepath.Join(dir, "manifests", "sub"), 0755)
+	for _, file := range []string{"app.yaml", "manifests/b.yaml", "manifests/a.yml", "manifests/sub/c.yaml", "manifests/README.md"} {
+		ioutil.WriteFile(filepath.Join(dir, file), []byte("kind: Deployment"), 0644)
+	}
+
+	t.Run("files and directories", func(t *testing.T) {
+		files, err := kubernetesManifestFiles([]string{filepath.Join(dir, "app.yaml"), filepath.Join(dir, "manifests")})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			filepath.Join(dir, "app.yaml"),
+			filepath.Join(dir, "manifests", "a.yml"),
+			filepath.Join(dir, "manifests", "b.yaml"),
+			filepath.Join(dir, "manifests", "sub", "c.yaml"),
+		}, files)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := kubernetesManifestFiles([]string{filepath.Join(dir, "missing.yaml")})
+
+		assert.Contains(t, err.Error(), "app template '"+filepath.Join(dir, "missing.yaml")+"' not found")
+	})
+
+	t.Run("no template", func(t *testing.T) {
+		_, err := kubernetesManifestFiles([]string{""})
+
+		assert.EqualError(t, err, "no app template found, please configure appTemplate")
 	})
 }
 
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+// ManPagesCommand is the command for creating the man pages of the piper binary
+func ManPagesCommand() *cobra.Command {
+	var targetDir string
+	var createManPagesCmd = &cobra.Command{
+		Use:   "manPages",
+		Short: "Generates the man pages for the piper binary and all of its steps",
+		Long: `Generates the man pages for the piper binary and all of its steps.
+
+To make the man pages available, add the target directory to your MANPATH, e.g.
+
+  piper manPages --targetDir ~/.local/share/man/man1
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return generateManPages(cmd.Root(), targetDir)
+		},
+	}
+
+	createManPagesCmd.Flags().StringVar(&targetDir, "targetDir", "./man", "Directory the man pages are written to")
+	createManPagesCmd.MarkFlagDirname("targetDir")
+	return createManPagesCmd
+}
+
+func generateManPages(root *cobra.Command, targetDir string) error {
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory '%v'", targetDir)
+	}
+
+	header := &doc.GenManHeader{Title: "PIPER", Section: "1", Source: "project 'Piper'"}
+	if err := doc.GenManTree(root, header, targetDir); err != nil {
+		return errors.Wrap(err, "failed to generate man pages")
+	}
+	return nil
+}
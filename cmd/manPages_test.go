@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateManPages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manPages")
+	if err != nil {
+		t.Fatal("Failed to create temporary directory")
+	}
+	// clean up tmp dir
+	defer os.RemoveAll(dir)
+
+	testRootCmd := &cobra.Command{Use: "piper", Short: "Test root"}
+	testRootCmd.AddCommand(&cobra.Command{Use: "testStep", Short: "Test step", Run: func(_ *cobra.Command, _ []string) {}})
+
+	targetDir := filepath.Join(dir, "man")
+	err = generateManPages(testRootCmd, targetDir)
+
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(targetDir, "piper.1"))
+	content, err := ioutil.ReadFile(filepath.Join(targetDir, "piper-testStep.1"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "Test step")
+}
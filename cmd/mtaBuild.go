@@ -62,7 +62,7 @@ func ValueOfBuildTarget(str string) (MTABuildTarget, error) {
 	case "XSA":
 		return XSA, nil
 	default:
-		return -1, fmt.Errorf("Unknown BuildTarget/Platform: '%s'", str)
+		return -1, log.NewError(log.ErrorConfiguration, "Unknown BuildTarget/Platform: '%s'", str)
 	}
 }
 
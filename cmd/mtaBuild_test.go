@@ -2,6 +2,7 @@ package cmd
 
 import (
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/maven"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
@@ -228,6 +229,23 @@ func TestMarBuild(t *testing.T) {
 		assert.Equal(t, "myName.mtar", cpe.mtarFilePath)
 	})
 
+	t.Run("Mta build mbt toolset, unknown platform", func(t *testing.T) {
+
+		e := mock.ExecMockRunner{}
+
+		options := mtaBuildOptions{ApplicationName: "myApp", MtaBuildTool: "cloudMbt", MtarName: "myName"}
+
+		existingFiles := make(map[string]string)
+		existingFiles["package.json"] = "{\"name\": \"myName\", \"version\": \"1.2.3\"}"
+		fileUtils := MtaTestFileUtilsMock{existingFiles: existingFiles}
+
+		err := runMtaBuild(options, &cpe, &e, &fileUtils, &httpClient)
+
+		assert.EqualError(t, err, "Unknown BuildTarget/Platform: ''")
+		assert.Equal(t, log.ErrorConfiguration, log.ErrorCategoryOf(err))
+		assert.Empty(t, e.Calls)
+	})
+
 	t.Run("Settings file releatd tests", func(t *testing.T) {
 
 		var settingsFile string
@@ -136,6 +136,9 @@ func uploadMTA(utils nexusUploadUtils, uploader nexus.Uploader, options *nexusUp
 		mtaPath = "mta.yml"
 	}
 	version, err := getVersionFromMtaFile(utils, mtaPath)
+	if err == nil && options.ArtifactVersion != "" {
+		version = options.ArtifactVersion
+	}
 	var artifactID = options.ArtifactID
 	if artifactID == "" {
 		artifactID = utils.getEnvParameter(".pipeline/commonPipelineEnvironment/configuration", "artifactId")
@@ -377,8 +380,8 @@ func uploadMavenArtifacts(utils nexusUploadUtils, uploader nexus.Uploader, optio
 		groupID = options.GroupID
 	}
 	artifactID, err := utils.evaluate(pomFile, "project.artifactId")
-	var artifactsVersion string
-	if err == nil {
+	artifactsVersion := options.ArtifactVersion
+	if err == nil && artifactsVersion == "" {
 		artifactsVersion, err = utils.evaluate(pomFile, "project.version")
 	}
 	if err == nil {
@@ -260,6 +260,20 @@ func TestUploadMTAProjects(t *testing.T) {
 			assert.Equal(t, "mtar", artifacts[1].Type)
 		}
 	})
+	t.Run("Test uploading mta.yaml project with artifactVersion works", func(t *testing.T) {
+		utils := newMockUtilsBundle(true, false)
+		utils.files["mta.yaml"] = testMtaYml
+		utils.files["test.mtar"] = []byte("contentsOfMtar")
+		utils.cpe[".pipeline/commonPipelineEnvironment/mtarFilePath"] = "test.mtar"
+		uploader := mockUploader{}
+		options := createOptions()
+		options.ArtifactVersion = "0.3.0-20200401123000+abc123"
+
+		err := runNexusUpload(&utils, &uploader, &options)
+		assert.NoError(t, err, "expected mta.yaml project upload to work")
+
+		assert.Equal(t, "0.3.0-20200401123000+abc123", uploader.GetArtifactsVersion())
+	})
 	t.Run("Test uploading mta.yml project works", func(t *testing.T) {
 		utils := newMockUtilsBundle(true, false)
 		utils.files["mta.yml"] = testMtaYml
@@ -447,6 +461,21 @@ func TestUploadMavenProjects(t *testing.T) {
 			assert.Equal(t, "jar", artifacts[1].Type)
 		}
 	})
+	t.Run("Test uploading Maven project with artifactVersion works", func(t *testing.T) {
+		utils := newMockUtilsBundle(false, true)
+		utils.setProperty("pom.xml", "project.groupId", "com.mycompany.app")
+		utils.setProperty("pom.xml", "project.artifactId", "my-app")
+		utils.setProperty("pom.xml", "project.packaging", "pom")
+		utils.files["pom.xml"] = testPomXml
+		uploader := mockUploader{}
+		options := createOptions()
+		options.ArtifactVersion = "1.0-20200401123000"
+
+		err := runNexusUpload(&utils, &uploader, &options)
+		assert.NoError(t, err, "expected Maven upload to work")
+
+		assert.Equal(t, "1.0-20200401123000", uploader.GetArtifactsVersion())
+	})
 	t.Run("Test uploading Maven project with fall-back to JAR packaging works", func(t *testing.T) {
 		utils := newMockUtilsBundle(false, true)
 		utils.setProperty("pom.xml", "project.version", "1.0")
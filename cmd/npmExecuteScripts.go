@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/bmatcuk/doublestar"
+	"github.com/pkg/errors"
+)
+
+// npmrcFile is the npm configuration file containing the user configuration and the registries configured for the step
+const npmrcFile = ".piperNpmrc"
+
+// npmExecuteScriptsUtils defines an interface for utility functionality used from external packages,
+// so it can be easily mocked for testing.
+type npmExecuteScriptsUtils interface {
+	FileExists(path string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	FileRemove(path string) error
+	Glob(pattern string) ([]string, error)
+	getExecRunner() execRunner
+}
+
+type npmExecuteScriptsUtilsBundle struct {
+	piperutils.Files
+	execRunner *command.Command
+}
+
+func (u *npmExecuteScriptsUtilsBundle) FileRemove(path string) error {
+	return os.Remove(path)
+}
+
+func (u *npmExecuteScriptsUtilsBundle) Glob(pattern string) ([]string, error) {
+	return doublestar.Glob(pattern)
+}
+
+func (u *npmExecuteScriptsUtilsBundle) getExecRunner() execRunner {
+	if u.execRunner == nil {
+		u.execRunner = &command.Command{}
+		u.execRunner.Stdout(log.Entry().Writer())
+		u.execRunner.Stderr(log.Entry().Writer())
+	}
+	return u.execRunner
+}
+
+// npmPackage contains the parts of a package.json which are relevant for executing scripts
+type npmPackage struct {
+	Scripts map[string]string `json:"scripts"`
+}
+
+// npmExecutedScript describes a script which has been executed, the list of executed scripts is provided via the commonPipelineEnvironment
+type npmExecutedScript struct {
+	Script      string `json:"script"`
+	PackageJSON string `json:"packageJson"`
+}
+
+func npmExecuteScripts(config npmExecuteScriptsOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *npmExecuteScriptsCommonPipelineEnvironment) {
+	utils := npmExecuteScriptsUtilsBundle{}
+
+	err := runNpmExecuteScripts(&config, &utils, commonPipelineEnvironment)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runNpmExecuteScripts(config *npmExecuteScriptsOptions, utils npmExecuteScriptsUtils, commonPipelineEnvironment *npmExecuteScriptsCommonPipelineEnvironment) error {
+	packageJSONFiles, err := findPackageJSONFiles(utils)
+	if err != nil {
+		return err
+	}
+	if len(packageJSONFiles) == 0 {
+		return log.WrapError(log.ErrorConfiguration, errors.New("no package.json file found"))
+	}
+
+	execRunner := utils.getExecRunner()
+
+	npmrc, err := writeNpmrc(config, userNpmrc(), utils)
+	if err != nil {
+		return err
+	}
+	if len(npmrc) > 0 {
+		defer func() {
+			if err := utils.FileRemove(npmrc); err != nil {
+				log.Entry().WithError(err).Warnf("failed to remove %v", npmrc)
+			}
+		}()
+		execRunner.SetEnv([]string{"NPM_CONFIG_USERCONFIG=" + npmrc})
+	}
+
+	if config.Install {
+		for _, packageJSON := range packageJSONFiles {
+			if err := installDependencies(packageJSON, utils); err != nil {
+				return err
+			}
+		}
+	}
+
+	executed := []npmExecutedScript{}
+	for _, script := range config.RunScripts {
+		found := false
+		for _, packageJSON := range packageJSONFiles {
+			hasScript, err := packageHasScript(packageJSON, script, utils)
+			if err != nil {
+				return err
+			}
+			if !hasScript {
+				continue
+			}
+			found = true
+
+			log.Entry().Infof("Executing npm script '%v' of %v", script, packageJSON)
+			execRunner.SetDir(filepath.Dir(packageJSON))
+			if err := execRunner.RunExecutable("npm", "run", script); err != nil {
+				return errors.Wrapf(err, "failed to execute npm script '%v' of %v", script, packageJSON)
+			}
+			executed = append(executed, npmExecutedScript{Script: script, PackageJSON: packageJSON})
+		}
+		if !found {
+			log.Entry().Warnf("npm script '%v' is not implemented by any package.json", script)
+		}
+	}
+
+	if len(executed) > 0 {
+		descriptions := []string{}
+		for _, e := range executed {
+			descriptions = append(descriptions, fmt.Sprintf("%v (%v)", e.Script, e.PackageJSON))
+		}
+		log.Entry().Infof("Executed npm scripts: %v", strings.Join(descriptions, ", "))
+	} else {
+		log.Entry().Info("No npm scripts executed")
+	}
+
+	executedJSON, err := json.Marshal(executed)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize the list of executed npm scripts")
+	}
+	commonPipelineEnvironment.npm.executedScripts = string(executedJSON)
+	return nil
+}
+
+// findPackageJSONFiles returns all package.json files of the project except the ones of installed dependencies
+func findPackageJSONFiles(utils npmExecuteScriptsUtils) ([]string, error) {
+	matches, err := utils.Glob("**/package.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search for package.json files")
+	}
+
+	packageJSONFiles := []string{}
+	for _, match := range matches {
+		if strings.Contains(filepath.ToSlash(match), "node_modules/") {
+			continue
+		}
+		packageJSONFiles = append(packageJSONFiles, match)
+	}
+	sort.Strings(packageJSONFiles)
+	return packageJSONFiles, nil
+}
+
+// userNpmrc returns the path of the npm user configuration of the execution environment
+func userNpmrc() string {
+	for _, name := range []string{"NPM_CONFIG_USERCONFIG", "npm_config_userconfig"} {
+		if path := os.Getenv(name); len(path) > 0 {
+			return path
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".npmrc")
+}
+
+// writeNpmrc writes the npm user configuration followed by the configured registries to the npm configuration file of the step
+// and returns its absolute path. Since the file replaces the user configuration, its content has to be retained.
+// An empty path is returned in case no registry is configured.
+func writeNpmrc(config *npmExecuteScriptsOptions, userNpmrc string, utils npmExecuteScriptsUtils) (string, error) {
+	lines := []string{}
+	if len(config.DefaultNpmRegistry) > 0 {
+		log.Entry().Debugf("Setting default npm registry to %v", config.DefaultNpmRegistry)
+		lines = append(lines, "registry="+config.DefaultNpmRegistry)
+	}
+
+	scopes := []string{}
+	for scope := range config.ScopedNpmRegistries {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	for _, scope := range scopes {
+		registry := fmt.Sprint(config.ScopedNpmRegistries[scope])
+		if !strings.HasPrefix(scope, "@") {
+			scope = "@" + scope
+		}
+		log.Entry().Debugf("Setting npm registry for scope %v to %v", scope, registry)
+		lines = append(lines, fmt.Sprintf("%v:registry=%v", scope, registry))
+	}
+
+	if len(lines) == 0 {
+		log.Entry().Debug("No npm registry provided via configuration. Leaving npm config untouched.")
+		return "", nil
+	}
+
+	content := []byte{}
+	if len(userNpmrc) > 0 {
+		exists, err := utils.FileExists(userNpmrc)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to check for %v", userNpmrc)
+		}
+		if exists {
+			log.Entry().Debugf("Retaining the npm user configuration %v", userNpmrc)
+			if content, err = utils.FileRead(userNpmrc); err != nil {
+				return "", errors.Wrapf(err, "failed to read %v", userNpmrc)
+			}
+			if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+				content = append(content, '\n')
+			}
+		}
+	}
+
+	npmrc, err := filepath.Abs(npmrcFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve path of %v", npmrcFile)
+	}
+	content = append(content, []byte(strings.Join(lines, "\n")+"\n")...)
+	if err := utils.FileWrite(npmrc, content, 0644); err != nil {
+		return "", errors.Wrapf(err, "failed to write %v", npmrcFile)
+	}
+	return npmrc, nil
+}
+
+// installDependencies installs the dependencies of a package, using `npm ci` in case the dependency versions are locked
+func installDependencies(packageJSON string, utils npmExecuteScriptsUtils) error {
+	dir := filepath.Dir(packageJSON)
+
+	locked := false
+	for _, lockFile := range []string{"package-lock.json", "npm-shrinkwrap.json"} {
+		exists, err := utils.FileExists(filepath.Join(dir, lockFile))
+		if err != nil {
+			return errors.Wrapf(err, "failed to check for %v", filepath.Join(dir, lockFile))
+		}
+		locked = locked || exists
+	}
+
+	installCommand := "install"
+	if locked {
+		installCommand = "ci"
+	}
+
+	log.Entry().Infof("Installing dependencies of %v via npm %v", packageJSON, installCommand)
+	execRunner := utils.getExecRunner()
+	execRunner.SetDir(dir)
+	if err := execRunner.RunExecutable("npm", installCommand); err != nil {
+		return errors.Wrapf(err, "failed to install dependencies of %v", packageJSON)
+	}
+	return nil
+}
+
+// packageHasScript checks whether the package.json implements the script
+func packageHasScript(packageJSON, script string, utils npmExecuteScriptsUtils) (bool, error) {
+	content, err := utils.FileRead(packageJSON)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read %v", packageJSON)
+	}
+
+	var npmPackage npmPackage
+	if err := json.Unmarshal(content, &npmPackage); err != nil {
+		return false, log.WrapError(log.ErrorConfiguration, errors.Wrapf(err, "failed to parse %v", packageJSON))
+	}
+
+	_, ok := npmPackage.Scripts[script]
+	return ok, nil
+}
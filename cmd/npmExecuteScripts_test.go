@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type npmExecuteScriptsMockUtils struct {
+	mock.FileSystemMock
+	execRunner mock.ExecMockRunner
+}
+
+func newNpmExecuteScriptsMockUtils() npmExecuteScriptsMockUtils {
+	return npmExecuteScriptsMockUtils{FileSystemMock: mock.NewFileSystemMock()}
+}
+
+func (m *npmExecuteScriptsMockUtils) getExecRunner() execRunner {
+	return &m.execRunner
+}
+
+func TestRunNpmExecuteScripts(t *testing.T) {
+	t.Run("install and run scripts", func(t *testing.T) {
+		utils := newNpmExecuteScriptsMockUtils()
+		utils.Files["package.json"] = []byte(`{"scripts": {"ci-build": "tsc", "ci-test": "jest"}}`)
+		utils.Files["package-lock.json"] = []byte("{}")
+		utils.Files["src/frontend/package.json"] = []byte(`{"scripts": {"ci-build": "webpack"}}`)
+		utils.Files["node_modules/dep/package.json"] = []byte(`{"scripts": {"ci-build": "make"}}`)
+		options := npmExecuteScriptsOptions{Install: true, RunScripts: []string{"ci-build", "ci-test", "ci-lint"}}
+		cpe := npmExecuteScriptsCommonPipelineEnvironment{}
+
+		err := runNpmExecuteScripts(&options, &utils, &cpe)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{
+			{Exec: "npm", Params: []string{"ci"}},
+			{Exec: "npm", Params: []string{"install"}},
+			{Exec: "npm", Params: []string{"run", "ci-build"}},
+			{Exec: "npm", Params: []string{"run", "ci-build"}},
+			{Exec: "npm", Params: []string{"run", "ci-test"}},
+		}, utils.execRunner.Calls)
+		assert.Equal(t, []string{".", "src/frontend", ".", "src/frontend", "."}, utils.execRunner.Dir)
+		assert.Empty(t, utils.execRunner.Env, "npm configuration must not be changed without configured registries")
+		assert.JSONEq(t, `[
+			{"script": "ci-build", "packageJson": "package.json"},
+			{"script": "ci-build", "packageJson": "src/frontend/package.json"},
+			{"script": "ci-test", "packageJson": "package.json"}
+		]`, cpe.npm.executedScripts)
+	})
+
+	t.Run("without install", func(t *testing.T) {
+		utils := newNpmExecuteScriptsMockUtils()
+		utils.Files["package.json"] = []byte(`{"scripts": {"ci-build": "tsc"}}`)
+		options := npmExecuteScriptsOptions{RunScripts: []string{"ci-build"}}
+
+		err := runNpmExecuteScripts(&options, &utils, &npmExecuteScriptsCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{{Exec: "npm", Params: []string{"run", "ci-build"}}}, utils.execRunner.Calls)
+	})
+
+	t.Run("with registries", func(t *testing.T) {
+		utils := newNpmExecuteScriptsMockUtils()
+		utils.Files["package.json"] = []byte(`{}`)
+		options := npmExecuteScriptsOptions{
+			DefaultNpmRegistry:  "https://registry.example.org/",
+			ScopedNpmRegistries: map[string]interface{}{"@sap": "https://npm.sap.com", "internal": "https://npm.example.org/"},
+		}
+
+		err := runNpmExecuteScripts(&options, &utils, &npmExecuteScriptsCommonPipelineEnvironment{})
+
+		assert.NoError(t, err)
+		npmrc, _ := filepath.Abs(".piperNpmrc")
+		assert.Equal(t, []string{"NPM_CONFIG_USERCONFIG=" + npmrc}, utils.execRunner.Env)
+		exists, _ := utils.FileExists(npmrc)
+		assert.False(t, exists, "npm configuration of the step must be removed")
+	})
+
+	t.Run("no package.json", func(t *testing.T) {
+		utils := newNpmExecuteScriptsMockUtils()
+		options := npmExecuteScriptsOptions{Install: true}
+
+		err := runNpmExecuteScripts(&options, &utils, &npmExecuteScriptsCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "no package.json file found")
+	})
+
+	t.Run("invalid package.json", func(t *testing.T) {
+		utils := newNpmExecuteScriptsMockUtils()
+		utils.Files["package.json"] = []byte(`{"scripts": `)
+		options := npmExecuteScriptsOptions{RunScripts: []string{"ci-build"}}
+
+		err := runNpmExecuteScripts(&options, &utils, &npmExecuteScriptsCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to parse package.json: unexpected end of JSON input")
+	})
+
+	t.Run("failing script", func(t *testing.T) {
+		utils := newNpmExecuteScriptsMockUtils()
+		utils.Files["package.json"] = []byte(`{"scripts": {"ci-build": "tsc"}}`)
+		utils.execRunner.ShouldFailOnCommand = map[string]error{"npm run ci-build": fmt.Errorf("exit status 2")}
+		options := npmExecuteScriptsOptions{RunScripts: []string{"ci-build"}}
+
+		err := runNpmExecuteScripts(&options, &utils, &npmExecuteScriptsCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to execute npm script 'ci-build' of package.json: exit status 2")
+	})
+}
+
+func TestWriteNpmrc(t *testing.T) {
+	npmrc, _ := filepath.Abs(".piperNpmrc")
+	options := npmExecuteScriptsOptions{
+		DefaultNpmRegistry:  "https://registry.example.org/",
+		ScopedNpmRegistries: map[string]interface{}{"@sap": "https://npm.sap.com", "internal": "https://npm.example.org/"},
+	}
+
+	t.Run("registries", func(t *testing.T) {
+		utils := newNpmExecuteScriptsMockUtils()
+
+		path, err := writeNpmrc(&options, "/home/user/.npmrc", &utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, npmrc, path)
+		assert.Equal(t, "registry=https://registry.example.org/\n@sap:registry=https://npm.sap.com\n@internal:registry=https://npm.example.org/\n", string(utils.Files[npmrc]))
+	})
+
+	t.Run("retains user configuration", func(t *testing.T) {
+		utils := newNpmExecuteScriptsMockUtils()
+		utils.Files["/home/user/.npmrc"] = []byte("//registry.example.org/:_authToken=secret")
+
+		_, err := writeNpmrc(&options, "/home/user/.npmrc", &utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "//registry.example.org/:_authToken=secret\nregistry=https://registry.example.org/\n@sap:registry=https://npm.sap.com\n@internal:registry=https://npm.example.org/\n", string(utils.Files[npmrc]))
+	})
+
+	t.Run("no registries", func(t *testing.T) {
+		utils := newNpmExecuteScriptsMockUtils()
+		utils.Files["/home/user/.npmrc"] = []byte("registry=https://registry.example.org/\n")
+
+		path, err := writeNpmrc(&npmExecuteScriptsOptions{}, "/home/user/.npmrc", &utils)
+
+		assert.NoError(t, err)
+		assert.Empty(t, path)
+		assert.Len(t, utils.Files, 1)
+	})
+}
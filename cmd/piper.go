@@ -11,6 +11,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -21,6 +22,7 @@ type GeneralConfigOptions struct {
 	DefaultConfig  []string //ordered list of Piper default configurations. Can be filePath or ENV containing JSON in format 'ENV:MY_ENV_VAR'
 	ParametersJSON string
 	EnvRootPath    string
+	LogFormat      string
 	NoTelemetry    bool
 	StageName      string
 	StepConfigJSON string
@@ -47,6 +49,9 @@ func Execute() {
 
 	rootCmd.AddCommand(ConfigCommand())
 	rootCmd.AddCommand(VersionCommand())
+	rootCmd.AddCommand(CompletionCommand())
+	rootCmd.AddCommand(ManPagesCommand())
+	rootCmd.AddCommand(CpeCommand())
 	rootCmd.AddCommand(DetectExecuteScanCommand())
 	rootCmd.AddCommand(KarmaExecuteTestsCommand())
 	rootCmd.AddCommand(SonarExecuteScanCommand())
@@ -63,11 +68,20 @@ func Execute() {
 	rootCmd.AddCommand(MavenBuildCommand())
 	rootCmd.AddCommand(MavenExecuteStaticCodeChecksCommand())
 	rootCmd.AddCommand(NexusUploadCommand())
+	rootCmd.AddCommand(InfluxWriteLineProtocolCommand())
+	rootCmd.AddCommand(NpmExecuteScriptsCommand())
+	rootCmd.AddCommand(GradleBuildCommand())
+	rootCmd.AddCommand(GolangBuildCommand())
+	rootCmd.AddCommand(KanikoBuildImageCommand())
+	rootCmd.AddCommand(CloudFoundryDeployApplicationCommand())
+	rootCmd.AddCommand(CloudFoundryProvisionServicesCommand())
+	rootCmd.AddCommand(ArtifactPrepareVersionCommand())
 
 	addRootFlags(rootCmd)
+	cobra.OnInitialize(func() { log.SetFormatter(GeneralConfig.LogFormat) })
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(log.ErrorCategoryOf(err).ExitCode())
 	}
 }
 
@@ -79,20 +93,32 @@ func addRootFlags(rootCmd *cobra.Command) {
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.EnvRootPath, "envRootPath", ".pipeline", "Root path to Piper pipeline shared environments")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.StageName, "stageName", os.Getenv("STAGE_NAME"), "Name of the stage for which configuration should be included")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.StepConfigJSON, "stepConfigJSON", os.Getenv("PIPER_stepConfigJSON"), "Step configuration in JSON format")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.LogFormat, "logFormat", os.Getenv("PIPER_logFormat"), "Format of the log output, one of text, json, github or azure. If not set the format is detected from the environment")
 	rootCmd.PersistentFlags().BoolVar(&GeneralConfig.NoTelemetry, "noTelemetry", false, "Disables telemetry reporting")
 	rootCmd.PersistentFlags().BoolVarP(&GeneralConfig.Verbose, "verbose", "v", false, "verbose output")
 
 }
 
+// CompleteValues provides the shell completion of a flag with the given values, e.g. the possible values defined in the step metadata
+func CompleteValues(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
 // PrepareConfig reads step configuration from various sources and merges it (defaults, config file, flags, ...)
 func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName string, options interface{}, openFile func(s string) (io.ReadCloser, error)) error {
 
+	log.StartGroup("Configuration")
+	defer log.EndGroup()
+
 	filters := metadata.GetParameterFilters()
 
-	// add telemetry parameter "collectTelemetryData" to ALL, GENERAL and PARAMETER filters
-	filters.All = append(filters.All, "collectTelemetryData")
-	filters.General = append(filters.General, "collectTelemetryData")
-	filters.Parameters = append(filters.Parameters, "collectTelemetryData")
+	// add telemetry parameters to ALL, GENERAL and PARAMETER filters
+	telemetryParams := []string{"collectTelemetryData", "telemetrySinks", "telemetryFilePath", "telemetryOtlpEndpoint"}
+	filters.All = append(filters.All, telemetryParams...)
+	filters.General = append(filters.General, telemetryParams...)
+	filters.Parameters = append(filters.Parameters, telemetryParams...)
 
 	resourceParams := metadata.GetResourceParameters(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 
@@ -132,13 +158,18 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 
 		stepConfig, err = myConfig.GetStepConfig(flagValues, GeneralConfig.ParametersJSON, customConfig, defaultConfig, filters, metadata.Spec.Inputs.Parameters, resourceParams, GeneralConfig.StageName, stepName, metadata.Metadata.Aliases)
 		if err != nil {
-			return errors.Wrap(err, "retrieving step configuration failed")
+			return log.WrapError(log.ErrorConfiguration, errors.Wrap(err, "retrieving step configuration failed"))
 		}
 	}
 
+	if err := metadata.ValidateParameters(stepConfig.Config); err != nil {
+		return log.WrapError(log.ErrorConfiguration, err)
+	}
+
 	if fmt.Sprintf("%v", stepConfig.Config["collectTelemetryData"]) == "false" {
 		GeneralConfig.NoTelemetry = true
 	}
+	telemetry.Configure(telemetrySinkConfig(stepConfig.Config))
 
 	if !GeneralConfig.Verbose {
 		if stepConfig.Config["verbose"] != nil && stepConfig.Config["verbose"].(bool) {
@@ -154,6 +185,25 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 	return nil
 }
 
+func telemetrySinkConfig(stepConfig map[string]interface{}) telemetry.SinkConfig {
+	var sinkConfig telemetry.SinkConfig
+	switch sinks := stepConfig["telemetrySinks"].(type) {
+	case string:
+		sinkConfig.Sinks = strings.Split(sinks, ",")
+	case []interface{}:
+		for _, sink := range sinks {
+			sinkConfig.Sinks = append(sinkConfig.Sinks, fmt.Sprintf("%v", sink))
+		}
+	}
+	if filePath, ok := stepConfig["telemetryFilePath"].(string); ok {
+		sinkConfig.FilePath = filePath
+	}
+	if otlpEndpoint, ok := stepConfig["telemetryOtlpEndpoint"].(string); ok {
+		sinkConfig.OtlpEndpoint = otlpEndpoint
+	}
+	return sinkConfig
+}
+
 func getProjectConfigFile(name string) string {
 
 	var altName string
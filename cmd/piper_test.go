@@ -9,6 +9,7 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
@@ -24,9 +25,16 @@ func TestAddRootFlags(t *testing.T) {
 	assert.NotNil(t, testRootCmd.Flag("stageName"), "expected flag not available")
 	assert.NotNil(t, testRootCmd.Flag("stepConfigJSON"), "expected flag not available")
 	assert.NotNil(t, testRootCmd.Flag("verbose"), "expected flag not available")
+	assert.NotNil(t, testRootCmd.Flag("logFormat"), "expected flag not available")
 
 }
 
+func TestCompleteValues(t *testing.T) {
+	values, directive := CompleteValues("a", "b")(&cobra.Command{}, []string{}, "")
+	assert.Equal(t, []string{"a", "b"}, values)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+}
+
 func TestPrepareConfig(t *testing.T) {
 	defaultsBak := GeneralConfig.DefaultConfig
 	GeneralConfig.DefaultConfig = []string{"testDefaults.yml"}
@@ -94,6 +102,28 @@ func TestPrepareConfig(t *testing.T) {
 	})
 }
 
+func TestTelemetrySinkConfig(t *testing.T) {
+	t.Run("from config file", func(t *testing.T) {
+		sinkConfig := telemetrySinkConfig(map[string]interface{}{
+			"telemetrySinks":        []interface{}{"swa", "otlp"},
+			"telemetryOtlpEndpoint": "http://collector:4318",
+		})
+		assert.Equal(t, telemetry.SinkConfig{Sinks: []string{"swa", "otlp"}, OtlpEndpoint: "http://collector:4318"}, sinkConfig)
+	})
+
+	t.Run("from parameters", func(t *testing.T) {
+		sinkConfig := telemetrySinkConfig(map[string]interface{}{
+			"telemetrySinks":    "file",
+			"telemetryFilePath": "telemetry.jsonl",
+		})
+		assert.Equal(t, telemetry.SinkConfig{Sinks: []string{"file"}, FilePath: "telemetry.jsonl"}, sinkConfig)
+	})
+
+	t.Run("without configuration", func(t *testing.T) {
+		assert.Equal(t, telemetry.SinkConfig{}, telemetrySinkConfig(map[string]interface{}{}))
+	})
+}
+
 func TestGetProjectConfigFile(t *testing.T) {
 
 	tt := []struct {
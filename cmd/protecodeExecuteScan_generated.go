@@ -96,6 +96,7 @@ func ProtecodeExecuteScanCommand() *cobra.Command {
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			startTime = time.Now()
 			log.SetStepName("protecodeExecuteScan")
+			log.RegisterFatalHook("protecodeExecuteScan", ".")
 			log.SetVerbose(GeneralConfig.Verbose)
 			return PrepareConfig(cmd, &metadata, "protecodeExecuteScan", &stepConfig, config.OpenPiperFile)
 		},
@@ -105,12 +106,21 @@ func ProtecodeExecuteScanCommand() *cobra.Command {
 			handler := func() {
 				influx.persist(GeneralConfig.EnvRootPath, "influx")
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				if telemetryData.ErrorCode != "0" {
+					telemetryData.ErrorCategory = log.GetErrorCategory().String()
+					telemetryData.ErrorCode = fmt.Sprintf("%v", log.GetErrorCategory().ExitCode())
+				}
 				telemetry.Send(&telemetryData)
 			}
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetry.Initialize(GeneralConfig.NoTelemetry, "protecodeExecuteScan")
-			protecodeExecuteScan(stepConfig, &telemetryData, &influx)
+			log.StartGroup("Execution")
+			err := protecodeExecuteScan(stepConfig, &telemetryData, &influx)
+			log.EndGroup()
+			if err != nil {
+				log.Entry().WithError(err).Fatal("step execution failed")
+			}
 			telemetryData.ErrorCode = "0"
 		},
 	}
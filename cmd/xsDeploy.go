@@ -30,18 +30,11 @@ const (
 	BGDeploy DeployMode = iota
 )
 
-// ValueOfMode ...
-func ValueOfMode(str string) (DeployMode, error) {
-	switch str {
-	case "NONE":
-		return NoDeploy, nil
-	case "DEPLOY":
-		return Deploy, nil
-	case "BG_DEPLOY":
-		return BGDeploy, nil
-	default:
-		return NoDeploy, errors.New(fmt.Sprintf("Unknown DeployMode: '%s'", str))
-	}
+// deployModes maps the values of the mode parameter
+var deployModes = map[string]DeployMode{
+	"NONE":      NoDeploy,
+	"DEPLOY":    Deploy,
+	"BG_DEPLOY": BGDeploy,
 }
 
 // String ...
@@ -67,21 +60,12 @@ const (
 	Retry Action = iota
 )
 
-// ValueOfAction ...
-func ValueOfAction(str string) (Action, error) {
-	switch str {
-	case "NONE":
-		return None, nil
-	case "RESUME":
-		return Resume, nil
-	case "ABORT":
-		return Abort, nil
-	case "RETRY":
-		return Retry, nil
-
-	default:
-		return None, errors.New(fmt.Sprintf("Unknown Action: '%s'", str))
-	}
+// actions maps the values of the action parameter
+var actions = map[string]Action{
+	"NONE":   None,
+	"RESUME": Resume,
+	"ABORT":  Abort,
+	"RETRY":  Retry,
 }
 
 // String ...
@@ -124,9 +108,11 @@ func runXsDeploy(XsDeployOptions xsDeployOptions, piperEnvironment *xsDeployComm
 	fRemove func(string) error,
 	stdout io.Writer) error {
 
-	mode, err := ValueOfMode(XsDeployOptions.Mode)
-	if err != nil {
-		return errors.Wrapf(err, "Extracting mode failed: '%s'", XsDeployOptions.Mode)
+	var err error
+
+	mode, ok := deployModes[XsDeployOptions.Mode]
+	if !ok {
+		return log.NewError(log.ErrorConfiguration, "Unknown DeployMode: '%s'", XsDeployOptions.Mode)
 	}
 
 	if mode == NoDeploy {
@@ -134,9 +120,9 @@ func runXsDeploy(XsDeployOptions xsDeployOptions, piperEnvironment *xsDeployComm
 		return nil
 	}
 
-	action, err := ValueOfAction(XsDeployOptions.Action)
-	if err != nil {
-		return errors.Wrapf(err, "Extracting action failed: '%s'", XsDeployOptions.Action)
+	action, ok := actions[XsDeployOptions.Action]
+	if !ok {
+		return log.NewError(log.ErrorConfiguration, "Unknown Action: '%s'", XsDeployOptions.Action)
 	}
 
 	if mode == Deploy && action != None {
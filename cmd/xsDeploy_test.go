@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/stretchr/testify/assert"
 	"io"
@@ -161,6 +162,31 @@ func TestDeploy(t *testing.T) {
 		assert.EqualError(t, e, "Cannot perform action 'RETRY' in mode 'DEPLOY'. Only action 'NONE' is allowed.")
 	})
 
+	t.Run("Deploy fails, unknown mode", func(t *testing.T) {
+
+		myXsDeployOptions.Mode = ""
+		defer func() {
+			myXsDeployOptions.Mode = "DEPLOY"
+		}()
+
+		e := runXsDeploy(myXsDeployOptions, &cpeOut, &s, &fileUtilsMock, fRemove, ioutil.Discard)
+		assert.EqualError(t, e, "Unknown DeployMode: ''")
+		assert.Equal(t, log.ErrorConfiguration, log.ErrorCategoryOf(e))
+		assert.Empty(t, s.Calls)
+	})
+
+	t.Run("Deploy fails, unknown action", func(t *testing.T) {
+
+		myXsDeployOptions.Action = "PAUSE"
+		defer func() {
+			myXsDeployOptions.Action = "NONE"
+		}()
+
+		e := runXsDeploy(myXsDeployOptions, &cpeOut, &s, &fileUtilsMock, fRemove, ioutil.Discard)
+		assert.EqualError(t, e, "Unknown Action: 'PAUSE'")
+		assert.Empty(t, s.Calls)
+	})
+
 	t.Run("Standard deploy fails, error from underlying process", func(t *testing.T) {
 
 		defer func() {
@@ -0,0 +1,166 @@
+package cloudfoundry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// ExecRunner executes the cf CLI
+type ExecRunner interface {
+	SetEnv(e []string)
+	Stdout(out io.Writer)
+	RunExecutable(e string, p ...string) error
+}
+
+// LoginOptions contains the parameters of the Cloud Foundry login
+type LoginOptions struct {
+	CfAPIEndpoint string
+	CfOrg         string
+	CfSpace       string
+	Username      string
+	Password      string
+	// CfAPIOpts are passed as command line options to cf api, which is only called if options are given
+	CfAPIOpts []string
+	// CfLoginOpts are passed as additional command line options to cf login
+	CfLoginOpts []string
+}
+
+// Session is a login session of the cf CLI. The session uses a temporary CF_HOME,
+// so that parallel steps do not share the target and the token of the cf CLI.
+// The installed cf CLI plugins are still taken from the original plugin home.
+type Session struct {
+	runner     ExecRunner
+	home       string
+	pluginHome string
+	space      string
+	spaceGUID  string
+}
+
+// Login creates a temporary CF_HOME and logs into Cloud Foundry. The session has to be closed via Logout.
+func Login(options LoginOptions, runner ExecRunner) (*Session, error) {
+	home, err := ioutil.TempDir("", "cf-home")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary CF_HOME: %w", err)
+	}
+	session := &Session{runner: runner, home: home, pluginHome: pluginHome(), space: options.CfSpace}
+	session.SetEnv(nil)
+
+	if len(options.CfAPIOpts) > 0 {
+		if err := runner.RunExecutable("cf", append([]string{"api", options.CfAPIEndpoint}, options.CfAPIOpts...)...); err != nil {
+			session.cleanup()
+			return nil, fmt.Errorf("failed to set the Cloud Foundry API endpoint: %w", err)
+		}
+	}
+
+	loginParameters := []string{"login", "-a", options.CfAPIEndpoint, "-o", options.CfOrg, "-s", options.CfSpace, "-u", options.Username, "-p", options.Password}
+	loginParameters = append(loginParameters, options.CfLoginOpts...)
+
+	log.Entry().WithField("cfAPI", options.CfAPIEndpoint).WithField("cfOrg", options.CfOrg).WithField("space", options.CfSpace).Info("Logging into Cloud Foundry")
+	if err := runner.RunExecutable("cf", loginParameters...); err != nil {
+		session.cleanup()
+		return nil, fmt.Errorf("failed to login to Cloud Foundry: %w", err)
+	}
+	log.Entry().Info("Logged in successfully to Cloud Foundry")
+	return session, nil
+}
+
+// Logout logs out of Cloud Foundry and removes the temporary CF_HOME of the session
+func (s *Session) Logout() error {
+	log.Entry().Info("Logging out of Cloud Foundry")
+	err := s.runner.RunExecutable("cf", "logout")
+	s.cleanup()
+	if err != nil {
+		return fmt.Errorf("failed to logout of Cloud Foundry: %w", err)
+	}
+	log.Entry().Info("Logged out successfully")
+	return nil
+}
+
+// SetEnv sets additional environment variables for the cf CLI, retaining the CF_HOME and the CF_PLUGIN_HOME of the session
+func (s *Session) SetEnv(env []string) {
+	sessionEnv := []string{"CF_HOME=" + s.home}
+	if len(s.pluginHome) > 0 {
+		sessionEnv = append(sessionEnv, "CF_PLUGIN_HOME="+s.pluginHome)
+	}
+	s.runner.SetEnv(append(sessionEnv, env...))
+}
+
+// pluginHome returns the directory the cf CLI looks up the installed plugins in without a temporary CF_HOME
+func pluginHome() string {
+	for _, name := range []string{"CF_PLUGIN_HOME", "CF_HOME", "HOME"} {
+		if home := os.Getenv(name); len(home) > 0 {
+			return home
+		}
+	}
+	return ""
+}
+
+func (s *Session) cleanup() {
+	s.runner.SetEnv(nil)
+	if err := os.RemoveAll(s.home); err != nil {
+		log.Entry().WithError(err).Warnf("Failed to remove temporary CF_HOME %v", s.home)
+	}
+}
+
+// apiError covers the error responses of the v2 and the v3 Cloud Controller API
+type apiError struct {
+	Description string `json:"description"`
+	ErrorCode   string `json:"error_code"`
+	Errors      []struct {
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+	} `json:"errors"`
+}
+
+// Curl performs a GET request against the Cloud Controller API via cf curl and unmarshals the JSON response into result
+func (s *Session) Curl(path string, result interface{}) error {
+	response, err := s.output("curl", path)
+	if err != nil {
+		return fmt.Errorf("request to %v failed: %w", path, err)
+	}
+
+	var apiErr apiError
+	if err := json.Unmarshal(response, &apiErr); err != nil {
+		return fmt.Errorf("failed to parse response of %v: %w", path, err)
+	}
+	if len(apiErr.ErrorCode) > 0 {
+		return fmt.Errorf("request to %v failed: %v (%v)", path, apiErr.Description, apiErr.ErrorCode)
+	}
+	if len(apiErr.Errors) > 0 {
+		return fmt.Errorf("request to %v failed: %v (%v)", path, apiErr.Errors[0].Detail, apiErr.Errors[0].Title)
+	}
+
+	if err := json.Unmarshal(response, result); err != nil {
+		return fmt.Errorf("failed to parse response of %v: %w", path, err)
+	}
+	return nil
+}
+
+// SpaceGUID returns the GUID of the space targeted by the session
+func (s *Session) SpaceGUID() (string, error) {
+	if len(s.spaceGUID) > 0 {
+		return s.spaceGUID, nil
+	}
+	output, err := s.output("space", s.space, "--guid")
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve GUID of space %v: %w", s.space, err)
+	}
+	s.spaceGUID = strings.TrimSpace(string(output))
+	return s.spaceGUID, nil
+}
+
+// output runs the cf CLI and returns its output instead of logging it
+func (s *Session) output(parameters ...string) ([]byte, error) {
+	var output bytes.Buffer
+	s.runner.Stdout(&output)
+	err := s.runner.RunExecutable("cf", parameters...)
+	s.runner.Stdout(log.Entry().Writer())
+	return output.Bytes(), err
+}
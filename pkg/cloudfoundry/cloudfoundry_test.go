@@ -0,0 +1,185 @@
+package cloudfoundry
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+var testLoginOptions = LoginOptions{
+	CfAPIEndpoint: "https://api.endpoint.com",
+	CfOrg:         "testOrg",
+	CfSpace:       "testSpace",
+	Username:      "testUser",
+	Password:      "testPassword",
+}
+
+// curlCommand returns the pattern for mocking the output of cf curl
+func curlCommand(path string) string {
+	return "^" + regexp.QuoteMeta("cf curl "+path) + "$"
+}
+
+func TestLogin(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		runner := mock.ExecMockRunner{}
+		options := testLoginOptions
+		options.CfLoginOpts = []string{"--origin", "ldap"}
+
+		session, err := Login(options, &runner)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{{Exec: "cf", Params: []string{"login", "-a", "https://api.endpoint.com", "-o", "testOrg", "-s", "testSpace", "-u", "testUser", "-p", "testPassword", "--origin", "ldap"}}}, runner.Calls)
+			assert.Equal(t, []string{"CF_HOME=" + session.home, "CF_PLUGIN_HOME=" + session.pluginHome}, runner.Env)
+			assert.DirExists(t, session.home)
+
+			assert.NoError(t, session.Logout())
+			assert.Equal(t, mock.ExecCall{Exec: "cf", Params: []string{"logout"}}, runner.Calls[1])
+			_, err := os.Stat(session.home)
+			assert.True(t, os.IsNotExist(err), "temporary CF_HOME not removed")
+		}
+	})
+
+	t.Run("api options", func(t *testing.T) {
+		runner := mock.ExecMockRunner{}
+		options := testLoginOptions
+		options.CfAPIOpts = []string{"--skip-ssl-validation"}
+
+		session, err := Login(options, &runner)
+
+		if assert.NoError(t, err) {
+			defer session.Logout()
+			assert.Equal(t, mock.ExecCall{Exec: "cf", Params: []string{"api", "https://api.endpoint.com", "--skip-ssl-validation"}}, runner.Calls[0])
+			assert.Equal(t, "login", runner.Calls[1].Params[0])
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		runner := mock.ExecMockRunner{ShouldFailOnCommand: map[string]error{"^cf login": fmt.Errorf("wrong password")}}
+
+		_, err := Login(testLoginOptions, &runner)
+
+		assert.EqualError(t, err, "failed to login to Cloud Foundry: wrong password")
+		home := strings.TrimPrefix(runner.Env[0], "CF_HOME=")
+		_, statErr := os.Stat(home)
+		assert.True(t, os.IsNotExist(statErr), "temporary CF_HOME not removed")
+	})
+
+	t.Run("additional environment", func(t *testing.T) {
+		runner := mock.ExecMockRunner{}
+		session, err := Login(testLoginOptions, &runner)
+		if assert.NoError(t, err) {
+			defer session.Logout()
+
+			session.SetEnv([]string{"CF_DOCKER_PASSWORD=secret"})
+
+			assert.Equal(t, []string{
+				"CF_HOME=" + session.home, "CF_PLUGIN_HOME=" + session.pluginHome,
+				"CF_HOME=" + session.home, "CF_PLUGIN_HOME=" + session.pluginHome, "CF_DOCKER_PASSWORD=secret",
+			}, runner.Env)
+		}
+	})
+}
+
+func TestPluginHome(t *testing.T) {
+	for _, name := range []string{"CF_PLUGIN_HOME", "CF_HOME", "HOME"} {
+		defer os.Setenv(name, os.Getenv(name))
+	}
+
+	t.Run("plugin home", func(t *testing.T) {
+		os.Setenv("CF_PLUGIN_HOME", "/plugins")
+		os.Setenv("CF_HOME", "/cf")
+		os.Setenv("HOME", "/home/user")
+
+		runner := mock.ExecMockRunner{}
+		session, err := Login(testLoginOptions, &runner)
+		if assert.NoError(t, err) {
+			defer session.Logout()
+			assert.Equal(t, []string{"CF_HOME=" + session.home, "CF_PLUGIN_HOME=/plugins"}, runner.Env)
+		}
+	})
+
+	t.Run("original CF_HOME", func(t *testing.T) {
+		os.Unsetenv("CF_PLUGIN_HOME")
+		os.Setenv("CF_HOME", "/cf")
+		os.Setenv("HOME", "/home/user")
+
+		assert.Equal(t, "/cf", pluginHome())
+	})
+
+	t.Run("home directory", func(t *testing.T) {
+		os.Unsetenv("CF_PLUGIN_HOME")
+		os.Unsetenv("CF_HOME")
+		os.Setenv("HOME", "/home/user")
+
+		assert.Equal(t, "/home/user", pluginHome())
+	})
+}
+
+func TestCurl(t *testing.T) {
+	runner := mock.ExecMockRunner{}
+	session, err := Login(testLoginOptions, &runner)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer session.Logout()
+
+	t.Run("success", func(t *testing.T) {
+		runner.StdoutReturn = map[string]string{curlCommand("/v2/info"): `{"name": "cf", "api_version": "2.150.0"}`}
+		var info struct {
+			APIVersion string `json:"api_version"`
+		}
+
+		err := session.Curl("/v2/info", &info)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2.150.0", info.APIVersion)
+	})
+
+	t.Run("v2 error", func(t *testing.T) {
+		runner.StdoutReturn = map[string]string{curlCommand("/v2/apps/1"): `{"description": "The app could not be found: 1", "error_code": "CF-AppNotFound", "code": 100004}`}
+
+		err := session.Curl("/v2/apps/1", &struct{}{})
+
+		assert.EqualError(t, err, "request to /v2/apps/1 failed: The app could not be found: 1 (CF-AppNotFound)")
+	})
+
+	t.Run("v3 error", func(t *testing.T) {
+		runner.StdoutReturn = map[string]string{curlCommand("/v3/apps/1"): `{"errors": [{"detail": "App not found", "title": "CF-ResourceNotFound", "code": 10010}]}`}
+
+		err := session.Curl("/v3/apps/1", &struct{}{})
+
+		assert.EqualError(t, err, "request to /v3/apps/1 failed: App not found (CF-ResourceNotFound)")
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		runner.StdoutReturn = map[string]string{curlCommand("/v2/info"): `Not logged in`}
+
+		err := session.Curl("/v2/info", &struct{}{})
+
+		assert.Contains(t, err.Error(), "failed to parse response of /v2/info")
+	})
+}
+
+func TestSpaceGUID(t *testing.T) {
+	runner := mock.ExecMockRunner{StdoutReturn: map[string]string{"^cf space testSpace --guid$": "space-guid\n"}}
+	session, err := Login(testLoginOptions, &runner)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer session.Logout()
+
+	guid, err := session.SpaceGUID()
+	assert.NoError(t, err)
+	assert.Equal(t, "space-guid", guid)
+
+	// the GUID is only retrieved once
+	guid, err = session.SpaceGUID()
+	assert.NoError(t, err)
+	assert.Equal(t, "space-guid", guid)
+	assert.Len(t, runner.Calls, 2)
+}
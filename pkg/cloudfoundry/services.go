@@ -0,0 +1,144 @@
+package cloudfoundry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+// ServiceInstance is a service instance within the targeted space
+type ServiceInstance struct {
+	GUID          string
+	Name          string
+	LastOperation LastOperation
+}
+
+// LastOperation is the state of the last create, update or delete operation on a service instance
+type LastOperation struct {
+	Type        string `json:"type"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+}
+
+// InProgress returns true as long as the operation has not finished
+func (o LastOperation) InProgress() bool {
+	return o.State == "in progress"
+}
+
+// Failed returns true if the operation failed
+func (o LastOperation) Failed() bool {
+	return o.State == "failed"
+}
+
+// ServiceKey is a service key of a service instance
+type ServiceKey struct {
+	GUID        string
+	Name        string
+	Credentials json.RawMessage
+}
+
+// resource is an entry of a v2 list response
+type resource struct {
+	Metadata struct {
+		GUID string `json:"guid"`
+	} `json:"metadata"`
+	Entity json.RawMessage `json:"entity"`
+}
+
+type resourceList struct {
+	NextURL   string     `json:"next_url"`
+	Resources []resource `json:"resources"`
+}
+
+// listResources returns the resources of all pages of a v2 list response
+func (s *Session) listResources(path string) ([]resource, error) {
+	resources := []resource{}
+	for len(path) > 0 {
+		var page resourceList
+		if err := s.Curl(path, &page); err != nil {
+			return nil, err
+		}
+		resources = append(resources, page.Resources...)
+		path = page.NextURL
+	}
+	return resources, nil
+}
+
+// ServiceInstance returns the service instance with the given name in the targeted space, or nil if the service does not exist
+func (s *Session) ServiceInstance(name string) (*ServiceInstance, error) {
+	spaceGUID, err := s.SpaceGUID()
+	if err != nil {
+		return nil, err
+	}
+	resources, err := s.listResources(fmt.Sprintf("/v2/spaces/%v/service_instances?q=%v", spaceGUID, url.QueryEscape("name:"+name)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve service instance %v: %w", name, err)
+	}
+	if len(resources) == 0 {
+		return nil, nil
+	}
+
+	var entity struct {
+		Name          string        `json:"name"`
+		LastOperation LastOperation `json:"last_operation"`
+	}
+	if err := json.Unmarshal(resources[0].Entity, &entity); err != nil {
+		return nil, fmt.Errorf("failed to parse service instance %v: %w", name, err)
+	}
+	return &ServiceInstance{GUID: resources[0].Metadata.GUID, Name: entity.Name, LastOperation: entity.LastOperation}, nil
+}
+
+// ServiceKeys returns the service keys of the service instance
+func (s *Session) ServiceKeys(instance *ServiceInstance) ([]ServiceKey, error) {
+	return s.serviceKeys(fmt.Sprintf("/v2/service_instances/%v/service_keys", instance.GUID))
+}
+
+// ServiceKey returns the service key with the given name of the service instance, or nil if the service key does not exist
+func (s *Session) ServiceKey(instance *ServiceInstance, name string) (*ServiceKey, error) {
+	keys, err := s.serviceKeys(fmt.Sprintf("/v2/service_instances/%v/service_keys?q=%v", instance.GUID, url.QueryEscape("name:"+name)))
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return &keys[0], nil
+}
+
+// ReadServiceKey returns the credentials of the service key of the service instance with the given names
+func (s *Session) ReadServiceKey(instanceName, keyName string) (json.RawMessage, error) {
+	instance, err := s.ServiceInstance(instanceName)
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("service instance %v not found", instanceName)
+	}
+	key, err := s.ServiceKey(instance, keyName)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, fmt.Errorf("service key %v of service instance %v not found", keyName, instanceName)
+	}
+	return key.Credentials, nil
+}
+
+func (s *Session) serviceKeys(path string) ([]ServiceKey, error) {
+	resources, err := s.listResources(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve service keys: %w", err)
+	}
+	keys := []ServiceKey{}
+	for _, resource := range resources {
+		var entity struct {
+			Name        string          `json:"name"`
+			Credentials json.RawMessage `json:"credentials"`
+		}
+		if err := json.Unmarshal(resource.Entity, &entity); err != nil {
+			return nil, fmt.Errorf("failed to parse service key: %w", err)
+		}
+		keys = append(keys, ServiceKey{GUID: resource.Metadata.GUID, Name: entity.Name, Credentials: entity.Credentials})
+	}
+	return keys, nil
+}
@@ -0,0 +1,103 @@
+package cloudfoundry
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testServiceInstancePath = "/v2/spaces/space-guid/service_instances?q=name%3AtestInstance"
+	testServiceKeysPath     = "/v2/service_instances/instance-guid/service_keys"
+)
+
+const testServiceInstances = `{
+  "total_results": 1,
+  "next_url": null,
+  "resources": [{
+    "metadata": {"guid": "instance-guid"},
+    "entity": {"name": "testInstance", "last_operation": {"type": "create", "state": "in progress", "description": ""}}
+  }]
+}`
+
+func newServicesTestSession(t *testing.T, stdout map[string]string) (*Session, *mock.ExecMockRunner) {
+	stdout["^cf space testSpace --guid$"] = "space-guid\n"
+	runner := &mock.ExecMockRunner{StdoutReturn: stdout}
+	session, err := Login(testLoginOptions, runner)
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	return session, runner
+}
+
+func TestServiceInstance(t *testing.T) {
+	t.Run("existing service", func(t *testing.T) {
+		session, _ := newServicesTestSession(t, map[string]string{curlCommand(testServiceInstancePath): testServiceInstances})
+		defer session.Logout()
+
+		instance, err := session.ServiceInstance("testInstance")
+
+		if assert.NoError(t, err) && assert.NotNil(t, instance) {
+			assert.Equal(t, "instance-guid", instance.GUID)
+			assert.Equal(t, "testInstance", instance.Name)
+			assert.Equal(t, "create", instance.LastOperation.Type)
+			assert.True(t, instance.LastOperation.InProgress())
+			assert.False(t, instance.LastOperation.Failed())
+		}
+	})
+
+	t.Run("missing service", func(t *testing.T) {
+		session, _ := newServicesTestSession(t, map[string]string{curlCommand(testServiceInstancePath): `{"total_results": 0, "next_url": null, "resources": []}`})
+		defer session.Logout()
+
+		instance, err := session.ServiceInstance("testInstance")
+
+		assert.NoError(t, err)
+		assert.Nil(t, instance)
+	})
+}
+
+func TestServiceKeys(t *testing.T) {
+	t.Run("multiple pages", func(t *testing.T) {
+		session, runner := newServicesTestSession(t, map[string]string{
+			curlCommand(testServiceKeysPath):             `{"next_url": "/v2/service_instances/instance-guid/service_keys?page=2", "resources": [{"metadata": {"guid": "key1-guid"}, "entity": {"name": "key1", "credentials": {"user": "a"}}}]}`,
+			curlCommand(testServiceKeysPath + "?page=2"): `{"next_url": null, "resources": [{"metadata": {"guid": "key2-guid"}, "entity": {"name": "key 2", "credentials": {"user": "b"}}}]}`,
+		})
+		defer session.Logout()
+
+		keys, err := session.ServiceKeys(&ServiceInstance{GUID: "instance-guid"})
+
+		if assert.NoError(t, err) && assert.Len(t, keys, 2) {
+			assert.Equal(t, "key1", keys[0].Name)
+			assert.Equal(t, "key 2", keys[1].Name)
+			assert.JSONEq(t, `{"user": "b"}`, string(keys[1].Credentials))
+		}
+		assert.Len(t, runner.Calls, 3)
+	})
+
+	t.Run("read service key", func(t *testing.T) {
+		session, _ := newServicesTestSession(t, map[string]string{
+			curlCommand(testServiceInstancePath):                   testServiceInstances,
+			curlCommand(testServiceKeysPath + "?q=name%3AtestKey"): `{"next_url": null, "resources": [{"metadata": {"guid": "key-guid"}, "entity": {"name": "testKey", "credentials": {"url": "https://my.abap.system"}}}]}`,
+		})
+		defer session.Logout()
+
+		credentials, err := session.ReadServiceKey("testInstance", "testKey")
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"url": "https://my.abap.system"}`, string(credentials))
+	})
+
+	t.Run("missing service key", func(t *testing.T) {
+		session, _ := newServicesTestSession(t, map[string]string{
+			curlCommand(testServiceInstancePath):                   testServiceInstances,
+			curlCommand(testServiceKeysPath + "?q=name%3AtestKey"): `{"next_url": null, "resources": []}`,
+		})
+		defer session.Logout()
+
+		_, err := session.ReadServiceKey("testInstance", "testKey")
+
+		assert.EqualError(t, err, "service key testKey of service instance testInstance not found")
+	})
+}
@@ -24,6 +24,10 @@ func AvailableFlagValues(cmd *cobra.Command, filters *StepFilters) map[string]in
 			flagValues[pflag.Name], _ = flags.GetBool(pflag.Name)
 		case "int":
 			flagValues[pflag.Name], _ = flags.GetInt(pflag.Name)
+		case "float64":
+			flagValues[pflag.Name], _ = flags.GetFloat64(pflag.Name)
+		case "intSlice":
+			flagValues[pflag.Name], _ = flags.GetIntSlice(pflag.Name)
 		default:
 			fmt.Printf("Meta data type not set or not known: '%v'\n", pflag.Value.Type())
 			os.Exit(1)
@@ -14,6 +14,8 @@ func TestAvailableFlagValues(t *testing.T) {
 	var test1 string
 	var test2 []string
 	var test3 bool
+	var test4 float64
+	var test5 []int
 
 	var c = &cobra.Command{
 		Use:   "test",
@@ -24,10 +26,14 @@ func TestAvailableFlagValues(t *testing.T) {
 	c.Flags().StringVar(&test1, "test1", "", "Test 1")
 	c.Flags().StringSliceVar(&test2, "test2", []string{}, "Test 2")
 	c.Flags().BoolVar(&test3, "test3", false, "Test 3")
+	c.Flags().Float64Var(&test4, "test4", 0, "Test 4")
+	c.Flags().IntSliceVar(&test5, "test5", []int{}, "Test 5")
 
 	c.Flags().Set("test1", "val1")
 	c.Flags().Set("test2", "val3_1")
 	c.Flags().Set("test3", "true")
+	c.Flags().Set("test4", "0.5")
+	c.Flags().Set("test5", "1,2")
 
 	v := AvailableFlagValues(c, &f)
 
@@ -38,6 +44,8 @@ func TestAvailableFlagValues(t *testing.T) {
 	assert.Equal(t, "val1", v["test1"])
 	assert.Equal(t, []string{"val3_1"}, v["test2"])
 	assert.Equal(t, true, v["test3"])
+	assert.Equal(t, 0.5, v["test4"])
+	assert.Equal(t, []int{1, 2}, v["test5"])
 
 }
 
@@ -25,6 +25,8 @@ type StepMetadata struct {
 	Aliases         []Alias `json:"aliases,omitempty"`
 	Description     string  `json:"description"`
 	LongDescription string  `json:"longDescription,omitempty"`
+	// ReturnsError declares that the step function returns an error which is handled by the generated code
+	ReturnsError bool `json:"returnsError,omitempty"`
 }
 
 // StepSpec defines the spec details for a step, like step inputs, containers, sidecars, ...
@@ -52,6 +54,7 @@ type StepParameters struct {
 	Type            string              `json:"type"`
 	Mandatory       bool                `json:"mandatory,omitempty"`
 	Default         interface{}         `json:"default,omitempty"`
+	PossibleValues  []interface{}       `json:"possibleValues,omitempty"`
 	Aliases         []Alias             `json:"aliases,omitempty"`
 	Conditions      []Condition         `json:"conditions,omitempty"`
 }
@@ -82,6 +85,10 @@ type StepSecrets struct {
 	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
 	Type        string `json:"type,omitempty"`
+	// CredentialType defines the kind of Jenkins credential: usernamePassword, token or file
+	CredentialType string `json:"credentialType,omitempty"`
+	// Params lists the step parameters which receive the credential, e.g. user and password for usernamePassword credentials
+	Params []string `json:"params,omitempty"`
 }
 
 // StepOutputs defines the outputs of a step step, typically one or multiple resources
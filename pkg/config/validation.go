@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidateParameters checks the step configuration against the parameter metadata.
+// Values of parameters with possible values must be one of them, for lists every element is checked.
+// Mandatory parameters which are not available as command line flag, e.g. maps, must be contained in the configuration.
+func (m *StepData) ValidateParameters(stepConfig map[string]interface{}) error {
+	problems := []string{}
+	for _, param := range m.Spec.Inputs.Parameters {
+		value, ok := stepConfig[param.Name]
+		if !ok || value == nil || value == "" {
+			if param.Mandatory && param.Type == "map[string]interface{}" {
+				problems = append(problems, fmt.Sprintf("mandatory parameter '%v' is missing", param.Name))
+			}
+			continue
+		}
+
+		if len(param.PossibleValues) == 0 {
+			continue
+		}
+		values := []interface{}{value}
+		if list, isList := value.([]interface{}); isList {
+			values = list
+		} else if list, isList := value.([]string); isList {
+			values = []interface{}{}
+			for _, v := range list {
+				values = append(values, v)
+			}
+		}
+		for _, v := range values {
+			if !isPossibleValue(param.PossibleValues, v) {
+				problems = append(problems, fmt.Sprintf("value '%v' of parameter '%v' is not allowed, possible values are: %v", v, param.Name, possibleValuesString(param.PossibleValues)))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("invalid step configuration: %v", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func isPossibleValue(possibleValues []interface{}, value interface{}) bool {
+	// the string representation is compared since numbers can be read e.g. as int or float64
+	for _, possibleValue := range possibleValues {
+		if fmt.Sprint(possibleValue) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func possibleValuesString(possibleValues []interface{}) string {
+	values := []string{}
+	for _, v := range possibleValues {
+		values = append(values, fmt.Sprint(v))
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateParameters(t *testing.T) {
+	metadata := StepData{
+		Spec: StepSpec{
+			Inputs: StepInputs{
+				Parameters: []StepParameters{
+					{Name: "mode", Type: "string", PossibleValues: []interface{}{"DEPLOY", "BG_DEPLOY"}},
+					{Name: "scanners", Type: "[]string", PossibleValues: []interface{}{"signature", "source"}},
+					{Name: "retries", Type: "int", PossibleValues: []interface{}{1, 3}},
+					{Name: "labels", Type: "map[string]interface{}", Mandatory: true},
+					{Name: "other", Type: "string"},
+				},
+			},
+		},
+	}
+
+	t.Run("valid configuration", func(t *testing.T) {
+		err := metadata.ValidateParameters(map[string]interface{}{
+			"mode":     "DEPLOY",
+			"scanners": []interface{}{"signature", "source"},
+			"retries":  float64(3),
+			"labels":   map[string]interface{}{"team": "piper"},
+			"other":    "anything",
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		err := metadata.ValidateParameters(map[string]interface{}{
+			"mode":     "UNDEPLOY",
+			"scanners": []string{"signature", "binary"},
+			"retries":  2,
+		})
+		assert.EqualError(t, err, "invalid step configuration: "+
+			"mandatory parameter 'labels' is missing; "+
+			"value '2' of parameter 'retries' is not allowed, possible values are: 1, 3; "+
+			"value 'UNDEPLOY' of parameter 'mode' is not allowed, possible values are: DEPLOY, BG_DEPLOY; "+
+			"value 'binary' of parameter 'scanners' is not allowed, possible values are: signature, source")
+	})
+}
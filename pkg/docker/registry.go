@@ -0,0 +1,68 @@
+package docker
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// ImageReference contains the parts of a container image reference which are relevant for subsequent steps
+type ImageReference struct {
+	// RegistryURL is the URL of the registry, e.g. https://my.registry.io
+	RegistryURL string
+	// ImageNameTag is the image name including the tag without the registry, e.g. path/image:tag
+	ImageNameTag string
+}
+
+// ParseImageReference splits a full image reference like my.registry.io/path/image:tag into registry and image name.
+// Images without registry refer to Docker Hub, images without tag to the latest tag.
+func ParseImageReference(image string) (ImageReference, error) {
+	tag, err := name.NewTag(image, name.WeakValidation)
+	if err != nil {
+		return ImageReference{}, fmt.Errorf("invalid image reference '%v': %w", image, err)
+	}
+	return ImageReference{
+		RegistryURL:  fmt.Sprintf("%v://%v", tag.Context().Registry.Scheme(), tag.Context().RegistryStr()),
+		ImageNameTag: fmt.Sprintf("%v:%v", tag.Context().RepositoryStr(), tag.TagStr()),
+	}, nil
+}
+
+// dockerHubAuthKey identifies Docker Hub within a Docker config.json
+const dockerHubAuthKey = "https://index.docker.io/v1/"
+
+// registryAuthKey returns the key which identifies the registry within a Docker config.json
+func registryAuthKey(registryURL string) string {
+	host := strings.TrimSuffix(registryURL, "/")
+	if u, err := url.Parse(registryURL); err == nil && len(u.Host) > 0 {
+		host = u.Host
+	}
+	if host == name.DefaultRegistry || host == "docker.io" {
+		return dockerHubAuthKey
+	}
+	return host
+}
+
+// AddRegistryAuth adds the credentials for the registry to the content of a Docker config.json.
+// Existing entries of the config are retained, the credentials for the same registry are replaced.
+func AddRegistryAuth(configJSON []byte, registryURL, username, password string) ([]byte, error) {
+	config := map[string]interface{}{}
+	if len(configJSON) > 0 {
+		if err := json.Unmarshal(configJSON, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse Docker config.json: %w", err)
+		}
+	}
+
+	auths, ok := config["auths"].(map[string]interface{})
+	if !ok {
+		auths = map[string]interface{}{}
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	auths[registryAuthKey(registryURL)] = map[string]interface{}{"auth": auth}
+	config["auths"] = auths
+
+	return json.Marshal(config)
+}
@@ -0,0 +1,57 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImageReference(t *testing.T) {
+	cases := []struct {
+		image string
+		want  ImageReference
+	}{
+		{"my.registry.io/path/image:tag", ImageReference{RegistryURL: "https://my.registry.io", ImageNameTag: "path/image:tag"}},
+		{"localhost:5000/image:1.0", ImageReference{RegistryURL: "http://localhost:5000", ImageNameTag: "image:1.0"}},
+		{"image", ImageReference{RegistryURL: "https://index.docker.io", ImageNameTag: "library/image:latest"}},
+	}
+	for _, c := range cases {
+		got, err := ParseImageReference(c.image)
+		if assert.NoError(t, err, c.image) {
+			assert.Equal(t, c.want, got, c.image)
+		}
+	}
+
+	t.Run("invalid reference", func(t *testing.T) {
+		_, err := ParseImageReference("my.registry.io/Image:tag")
+		assert.Contains(t, err.Error(), "invalid image reference 'my.registry.io/Image:tag'")
+	})
+}
+
+func TestAddRegistryAuth(t *testing.T) {
+	t.Run("empty config", func(t *testing.T) {
+		config, err := AddRegistryAuth(nil, "https://my.registry.io/", "user", "secret")
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"auths":{"my.registry.io":{"auth":"dXNlcjpzZWNyZXQ="}}}`, string(config))
+	})
+	t.Run("existing config", func(t *testing.T) {
+		existing := []byte(`{"auths":{"other.registry.io":{"auth":"b3RoZXI="},"my.registry.io":{"auth":"b2xk"}},"credsStore":"desktop"}`)
+
+		config, err := AddRegistryAuth(existing, "my.registry.io", "user", "secret")
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"auths":{"other.registry.io":{"auth":"b3RoZXI="},"my.registry.io":{"auth":"dXNlcjpzZWNyZXQ="}},"credsStore":"desktop"}`, string(config))
+	})
+	t.Run("docker hub", func(t *testing.T) {
+		config, err := AddRegistryAuth([]byte(`{}`), "https://index.docker.io", "user", "secret")
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"auths":{"https://index.docker.io/v1/":{"auth":"dXNlcjpzZWNyZXQ="}}}`, string(config))
+	})
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := AddRegistryAuth([]byte(`{"auths":`), "my.registry.io", "user", "secret")
+
+		assert.EqualError(t, err, "failed to parse Docker config.json: unexpected end of JSON input")
+	})
+}
@@ -68,7 +68,7 @@ func setDefaultStepParameters(stepData *config.StepData) {
 			switch param.Type {
 			case "bool":
 				param.Default = "false"
-			case "int":
+			case "int", "float64":
 				param.Default = "0"
 			}
 		} else {
@@ -76,7 +76,7 @@ func setDefaultStepParameters(stepData *config.StepData) {
 			case "string":
 			case "bool":
 				param.Default = fmt.Sprintf("\"%v\"", param.Default)
-			case "int":
+			case "int", "float64":
 				param.Default = fmt.Sprintf("%v", param.Default)
 			}
 		}
@@ -147,7 +147,7 @@ func createParametersTable(parameters []config.StepParameters) string {
 
 	for _, param := range parameters {
 		if v, ok := m[param.Name]; ok {
-			table += fmt.Sprintf(" | %v | %v | %v |  |\n ", param.Name, ifThenElse(param.Mandatory && param.Default == nil, "Yes", "No"), v)
+			table += fmt.Sprintf(" | %v | %v | %v | %v |\n ", param.Name, ifThenElse(param.Mandatory && param.Default == nil, "Yes", "No"), v, possibleValuesList(param.PossibleValues))
 			delete(m, param.Name)
 		}
 	}
@@ -171,6 +171,14 @@ func createParametersDetail(parameters []config.StepParameters) string {
 	return detail
 }
 
+func possibleValuesList(values []interface{}) string {
+	list := []string{}
+	for _, value := range values {
+		list = append(list, fmt.Sprintf("`%v`", value))
+	}
+	return strings.Join(list, ", ")
+}
+
 //combines equal parameters and the values
 func combineEqualParametersTogether(parameters []config.StepParameters) map[string]string {
 	var m map[string]string = make(map[string]string)
@@ -0,0 +1,115 @@
+package helper
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+)
+
+// groovyGeneratedMarker identifies Groovy steps which have been generated and thus may be overwritten
+const groovyGeneratedMarker = "// Code generated by piper's step-generator. DO NOT EDIT."
+
+// commands of the piper binary which are no pipeline steps and thus do not get a Groovy step, GitHub action or Azure task
+var noPipelineSteps = []string{"version"}
+
+// credentialTypes defines the Jenkins credential types supported by piperExecuteBin and the number of parameters they provide
+var credentialTypes = map[string]int{"file": 1, "token": 1, "usernamePassword": 2}
+
+type groovyCredential struct {
+	Type string
+	ID   string
+	Env  string
+}
+
+type groovyStepInfo struct {
+	MetadataFile         string
+	Credentials          []groovyCredential
+	FailOnMissingReports bool
+	FailOnMissingLinks   bool
+}
+
+const stepGroovyTemplate = groovyGeneratedMarker + `
+
+import groovy.transform.Field
+
+@Field String STEP_NAME = getClass().getName()
+@Field String METADATA_FILE = '{{ .MetadataFile }}'
+
+//Metadata maintained in file project://resources/{{ .MetadataFile }}
+
+void call(Map parameters = [:]) {
+{{- if .Credentials }}
+    List credentials = [
+        {{- range .Credentials }}
+        [type: '{{ .Type }}', id: '{{ .ID }}', env: [{{ .Env }}]],
+        {{- end }}
+    ]
+{{- else }}
+    List credentials = []
+{{- end }}
+    piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials
+{{- if or .FailOnMissingReports .FailOnMissingLinks }}, {{ .FailOnMissingReports }}{{ end }}
+{{- if .FailOnMissingLinks }}, true{{ end }})
+}
+`
+
+// groovyWrapperRequired checks whether the Groovy step in vars/ is to be (re-)generated.
+// Hand-written Groovy steps, i.e. without the generated marker, are never overwritten.
+func groovyWrapperRequired(stepName string) bool {
+	if contains(noPipelineSteps, stepName) {
+		return false
+	}
+	content, err := ioutil.ReadFile(filepath.Join("vars", stepName+".groovy"))
+	if os.IsNotExist(err) {
+		return true
+	}
+	return err == nil && strings.HasPrefix(string(content), groovyGeneratedMarker)
+}
+
+// stepGroovy creates the Groovy step which calls the piper binary via piperExecuteBin
+// and binds the Jenkins credentials as defined by the secrets of the step
+func stepGroovy(stepData *config.StepData, metadataFile string) ([]byte, error) {
+	info := groovyStepInfo{MetadataFile: "metadata/" + filepath.Base(metadataFile)}
+
+	for _, secret := range stepData.Spec.Inputs.Secrets {
+		if len(secret.CredentialType) == 0 {
+			continue
+		}
+		if count, ok := credentialTypes[secret.CredentialType]; !ok || count != len(secret.Params) {
+			return nil, fmt.Errorf("secret %v: credential type '%v' with %v parameter(s) not supported", secret.Name, secret.CredentialType, len(secret.Params))
+		}
+		env := []string{}
+		for _, param := range secret.Params {
+			env = append(env, fmt.Sprintf("'PIPER_%v'", param))
+		}
+		info.Credentials = append(info.Credentials, groovyCredential{Type: secret.CredentialType, ID: secret.Name, Env: strings.Join(env, ", ")})
+	}
+
+	// mandatory reports and links make the Groovy step fail in case the step did not provide the reports or links at all
+	for _, res := range stepData.Spec.Outputs.Resources {
+		if res.Type != "reports" {
+			continue
+		}
+		reportsResource, err := newReportsResource(stepData.Metadata.Name, res)
+		if err != nil {
+			return nil, err
+		}
+		info.FailOnMissingReports = info.FailOnMissingReports || reportsResource.HasMandatoryReport()
+		info.FailOnMissingLinks = info.FailOnMissingLinks || reportsResource.HasMandatoryLink()
+	}
+
+	tmpl, err := template.New("groovy").Parse(stepGroovyTemplate)
+	checkError(err)
+
+	var generatedCode bytes.Buffer
+	err = tmpl.Execute(&generatedCode, info)
+	checkError(err)
+
+	return generatedCode.Bytes(), nil
+}
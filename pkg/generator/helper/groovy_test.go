@@ -0,0 +1,75 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepGroovy(t *testing.T) {
+	t.Run("with credentials", func(t *testing.T) {
+		stepData := config.StepData{
+			Metadata: config.StepMetadata{Name: "testStep"},
+			Spec: config.StepSpec{Inputs: config.StepInputs{Secrets: []config.StepSecrets{
+				{Name: "kubeConfigFileCredentialsId", Type: "jenkins", CredentialType: "file", Params: []string{"kubeConfig"}},
+				{Name: "otherCredentialsId", Type: "jenkins"},
+				{Name: "dockerCredentialsId", Type: "jenkins", CredentialType: "usernamePassword", Params: []string{"user", "password"}},
+			}}},
+		}
+
+		groovy, err := stepGroovy(&stepData, "resources/metadata/testStep.yaml")
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(groovy), "@Field String METADATA_FILE = 'metadata/testStep.yaml'")
+		assert.Contains(t, string(groovy), `    List credentials = [
+        [type: 'file', id: 'kubeConfigFileCredentialsId', env: ['PIPER_kubeConfig']],
+        [type: 'usernamePassword', id: 'dockerCredentialsId', env: ['PIPER_user', 'PIPER_password']],
+    ]
+`)
+		assert.NotContains(t, string(groovy), "otherCredentialsId")
+	})
+
+	t.Run("without credentials", func(t *testing.T) {
+		stepData := config.StepData{Metadata: config.StepMetadata{Name: "testStep"}}
+
+		groovy, err := stepGroovy(&stepData, "testStep.yaml")
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(groovy), "    List credentials = []\n    piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)")
+	})
+
+	t.Run("with mandatory reports", func(t *testing.T) {
+		stepData := config.StepData{
+			Metadata: config.StepMetadata{Name: "testStep"},
+			Spec: config.StepSpec{Outputs: config.StepOutputs{Resources: []config.StepResources{
+				{Name: "reports", Type: "reports", Parameters: []map[string]interface{}{
+					{"name": "report", "type": "report"},
+					{"name": "link", "type": "link", "mandatory": true},
+				}},
+			}}},
+		}
+
+		groovy, err := stepGroovy(&stepData, "testStep.yaml")
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(groovy), "piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials, false, true)")
+	})
+
+	t.Run("unsupported credential", func(t *testing.T) {
+		stepData := config.StepData{
+			Spec: config.StepSpec{Inputs: config.StepInputs{Secrets: []config.StepSecrets{
+				{Name: "tokenCredentialsId", CredentialType: "token", Params: []string{"user", "password"}},
+			}}},
+		}
+
+		_, err := stepGroovy(&stepData, "testStep.yaml")
+
+		assert.EqualError(t, err, "secret tokenCredentialsId: credential type 'token' with 2 parameter(s) not supported")
+	})
+}
+
+func TestGroovyWrapperRequired(t *testing.T) {
+	assert.True(t, groovyWrapperRequired("notExistingStep"))
+	assert.False(t, groovyWrapperRequired("version"))
+}
@@ -7,6 +7,8 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -27,6 +29,8 @@ type stepInfo struct {
 	Short            string
 	StepFunc         string
 	StepName         string
+	// StepReturnsError is true if the metadata declares that the step function returns an error, which is then handled by the generated code
+	StepReturnsError bool
 }
 
 //StepGoTemplate ...
@@ -39,7 +43,7 @@ import (
 	{{ if .OSImport -}}
 	"os"
 	{{ end -}}
-	{{ if .OutputResources -}}
+	{{ if .OutputResources | usesPiperenv -}}
 	"path/filepath"
 	{{ end -}}
 	"time"
@@ -49,9 +53,12 @@ import (
 	{{ end -}}
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
-	{{ if .OutputResources -}}
+	{{ if .OutputResources | usesPiperenv -}}
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 	{{ end -}}
+	{{ if .OutputResources | usesPiperutils -}}
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	{{ end -}}
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/spf13/cobra"
 )
@@ -80,6 +87,7 @@ func {{.CobraCmdFuncName}}() *cobra.Command {
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			startTime = time.Now()
 			log.SetStepName("{{ .StepName }}")
+			log.RegisterFatalHook("{{ .StepName }}", ".")
 			log.SetVerbose({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.Verbose)
 			return {{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}PrepareConfig(cmd, &metadata, "{{ .StepName }}", &stepConfig, config.OpenPiperFile)
 		},
@@ -88,14 +96,32 @@ func {{.CobraCmdFuncName}}() *cobra.Command {
 			telemetryData.ErrorCode = "1"
 			handler := func() {
 				{{- range $notused, $oRes := .OutputResources }}
-				{{ index $oRes "name" }}.persist({{if $.ExportPrefix}}{{ $.ExportPrefix }}.{{end}}GeneralConfig.EnvRootPath, "{{ index $oRes "name" }}"){{ end }}
+				{{- if eq (index $oRes "type") "reports" }}
+				{{ index $oRes "name" }}.persist("./")
+				{{- else }}
+				{{ index $oRes "name" }}.persist({{if $.ExportPrefix}}{{ $.ExportPrefix }}.{{end}}GeneralConfig.EnvRootPath, "{{ index $oRes "name" }}")
+				{{- end }}{{ end }}
 				telemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				if telemetryData.ErrorCode != "0" {
+					telemetryData.ErrorCategory = log.GetErrorCategory().String()
+					telemetryData.ErrorCode = fmt.Sprintf("%v", log.GetErrorCategory().ExitCode())
+				}
 				telemetry.Send(&telemetryData)
 			}
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetry.Initialize({{if .ExportPrefix}}{{ .ExportPrefix }}.{{end}}GeneralConfig.NoTelemetry, "{{ .StepName }}")
+			log.StartGroup("Execution")
+			{{- if .StepReturnsError }}
+			err := {{.StepName}}(stepConfig, &telemetryData{{ range $notused, $oRes := .OutputResources}}, &{{ index $oRes "name" }}{{ end }})
+			log.EndGroup()
+			if err != nil {
+				log.Entry().WithError(err).Fatal("step execution failed")
+			}
+			{{- else }}
 			{{.StepName}}(stepConfig, &telemetryData{{ range $notused, $oRes := .OutputResources}}, &{{ index $oRes "name" }}{{ end }})
+			log.EndGroup()
+			{{- end }}
 			telemetryData.ErrorCode = "0"
 		},
 	}
@@ -105,11 +131,13 @@ func {{.CobraCmdFuncName}}() *cobra.Command {
 }
 
 func {{.FlagsFunc}}(cmd *cobra.Command, stepConfig *{{.StepName}}Options) {
-	{{- range $key, $value := .StepParameters }}
-	cmd.Flags().{{ $value.Type | flagType }}(&stepConfig.{{ $value.Name | golangName }}, "{{ $value.Name }}", {{ $value.Default }}, "{{ $value.Description }}"){{ end }}
+	{{- range $key, $value := .StepParameters }}{{ if $value | hasFlag }}
+	cmd.Flags().{{ $value.Type | flagType }}(&stepConfig.{{ $value.Name | golangName }}, "{{ $value.Name }}", {{ $value.Default }}, "{{ $value.Description }}"){{ end }}{{ end }}
 	{{- printf "\n" }}
-	{{- range $key, $value := .StepParameters }}{{ if $value.Mandatory }}
+	{{- range $key, $value := .StepParameters }}{{ if and $value.Mandatory ($value | hasFlag) }}
 	cmd.MarkFlagRequired("{{ $value.Name }}"){{ end }}{{ end }}
+	{{- range $key, $value := .StepParameters }}{{ if and $value.PossibleValues ($value | hasFlag) }}
+	cmd.RegisterFlagCompletionFunc("{{ $value.Name }}", {{if $.ExportPrefix}}{{ $.ExportPrefix }}.{{end}}CompleteValues({{ $value.PossibleValues | stringValues }})){{ end }}{{ end }}
 }
 
 // retrieve step metadata
@@ -130,6 +158,9 @@ func {{ .StepName }}Metadata() config.StepData {
 						Type:      "{{ $value.Type }}",
 						Mandatory: {{ $value.Mandatory }},
 						Aliases:   []config.Alias{{ "{" }}{{ range $notused, $alias := $value.Aliases }}{{ "{" }}Name: "{{ $alias.Name }}"{{ "}" }},{{ end }}{{ "}" }},
+						{{- if $value.PossibleValues }}
+						PossibleValues: []interface{}{{ "{" }}{{ range $notused, $possibleValue := $value.PossibleValues }}{{ $possibleValue | goValue }},{{ end }}{{ "}" }},
+						{{- end }}
 					},{{ end }}
 				},
 			},
@@ -189,8 +220,29 @@ func run{{.StepName | title}}(config *{{ .StepName }}Options, telemetryData *tel
 }
 `
 
+const metadataGeneratedTemplate = `// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import "github.com/SAP/jenkins-library/pkg/config"
+
+// GetAllStepMetadata returns the metadata of all steps contained in the piper binary
+func GetAllStepMetadata() map[string]config.StepData {
+	return map[string]config.StepData{
+		{{- range . }}
+		"{{ . }}": {{ . }}Metadata(),
+		{{- end }}
+	}
+}
+`
+
 // ProcessMetaFiles generates step coding based on step configuration provided in yaml files
 func ProcessMetaFiles(metadataFiles []string, stepHelperData StepHelperData, docuHelperData DocuHelperData) error {
+	if err := LintMetaFiles(metadataFiles, stepHelperData.OpenFile); err != nil {
+		return err
+	}
+
+	stepNames := []string{}
 	for key := range metadataFiles {
 
 		var stepData config.StepData
@@ -216,6 +268,10 @@ func ProcessMetaFiles(metadataFiles []string, stepHelperData StepHelperData, doc
 
 			myStepInfo, err := getStepInfo(&stepData, osImport, stepHelperData.ExportPrefix)
 			checkError(err)
+			stepNames = append(stepNames, stepData.Metadata.Name)
+
+			implementationFile := fmt.Sprintf("cmd/%v.go", stepData.Metadata.Name)
+			exists, _ := piperutils.FileExists(implementationFile)
 
 			step := stepTemplate(myStepInfo)
 			err = stepHelperData.WriteFile(fmt.Sprintf("cmd/%v_generated.go", stepData.Metadata.Name), step, 0644)
@@ -225,10 +281,17 @@ func ProcessMetaFiles(metadataFiles []string, stepHelperData StepHelperData, doc
 			err = stepHelperData.WriteFile(fmt.Sprintf("cmd/%v_generated_test.go", stepData.Metadata.Name), test, 0644)
 			checkError(err)
 
-			exists, _ := piperutils.FileExists(fmt.Sprintf("cmd/%v.go", stepData.Metadata.Name))
 			if !exists {
 				impl := stepImplementation(myStepInfo)
-				err = stepHelperData.WriteFile(fmt.Sprintf("cmd/%v.go", stepData.Metadata.Name), impl, 0644)
+				err = stepHelperData.WriteFile(implementationFile, impl, 0644)
+				checkError(err)
+			}
+
+			// Groovy steps are only provided for steps of the piper library itself
+			if len(stepHelperData.ExportPrefix) == 0 && groovyWrapperRequired(stepData.Metadata.Name) {
+				groovy, err := stepGroovy(&stepData, configFilePath)
+				checkError(err)
+				err = stepHelperData.WriteFile(fmt.Sprintf("vars/%v.groovy", stepData.Metadata.Name), groovy, 0644)
 				checkError(err)
 			}
 		} else {
@@ -238,6 +301,12 @@ func ProcessMetaFiles(metadataFiles []string, stepHelperData StepHelperData, doc
 			}
 		}
 	}
+
+	// the metadata of all steps is only provided for the piper binary itself
+	if !docuHelperData.IsGenerateDocu && len(stepHelperData.ExportPrefix) == 0 {
+		err := stepHelperData.WriteFile("cmd/metadata_generated.go", stepMetadataTemplate(stepNames), 0644)
+		checkError(err)
+	}
 	return nil
 }
 
@@ -261,12 +330,19 @@ func setDefaultParameters(stepData *config.StepData) (bool, error) {
 				param.Default = "false"
 			case "int":
 				param.Default = "0"
+			case "float64":
+				param.Default = "0"
 			case "string":
 				param.Default = fmt.Sprintf("os.Getenv(\"PIPER_%v\")", param.Name)
 				osImportRequired = true
 			case "[]string":
 				// ToDo: Check if default should be read from env
 				param.Default = "[]string{}"
+			case "[]int":
+				param.Default = "[]int{}"
+			case "map[string]interface{}":
+				// maps are not available as flag, the value is only provided via configuration
+				param.Default = "nil"
 			default:
 				return false, fmt.Errorf("Meta data type not set or not known: '%v'", param.Type)
 			}
@@ -278,12 +354,20 @@ func setDefaultParameters(stepData *config.StepData) (bool, error) {
 					boolVal = "true"
 				}
 				param.Default = boolVal
-			case "int":
+			case "int", "float64":
 				param.Default = fmt.Sprintf("%v", param.Default)
 			case "string":
-				param.Default = fmt.Sprintf("\"%v\"", param.Default)
+				param.Default = strconv.Quote(fmt.Sprintf("%v", param.Default))
 			case "[]string":
-				param.Default = fmt.Sprintf("[]string{\"%v\"}", strings.Join(getStringSliceFromInterface(param.Default), "\", \""))
+				values := []string{}
+				for _, value := range getStringSliceFromInterface(param.Default) {
+					values = append(values, strconv.Quote(value))
+				}
+				param.Default = fmt.Sprintf("[]string{%v}", strings.Join(values, ", "))
+			case "[]int":
+				param.Default = fmt.Sprintf("[]int{%v}", strings.Join(getStringSliceFromInterface(param.Default), ", "))
+			case "map[string]interface{}":
+				param.Default = "nil"
 			default:
 				return false, fmt.Errorf("Meta data type not set or not known: '%v'", param.Type)
 			}
@@ -309,6 +393,7 @@ func getStepInfo(stepData *config.StepData, osImport bool, exportPrefix string)
 			OSImport:         osImport,
 			OutputResources:  oRes,
 			ExportPrefix:     exportPrefix,
+			StepReturnsError: stepData.Metadata.ReturnsError,
 		},
 		err
 }
@@ -319,6 +404,7 @@ func getOutputResourceDetails(stepData *config.StepData) ([]map[string]string, e
 	for _, res := range stepData.Spec.Outputs.Resources {
 		currentResource := map[string]string{}
 		currentResource["name"] = res.Name
+		currentResource["type"] = res.Type
 
 		switch res.Type {
 		case "piperEnvironment":
@@ -376,6 +462,18 @@ func getOutputResourceDetails(stepData *config.StepData) ([]map[string]string, e
 			currentResource["def"] = def
 			currentResource["objectname"] = influxResource.StructName()
 			outputResources = append(outputResources, currentResource)
+		case "reports":
+			reportsResource, err := newReportsResource(stepData.Metadata.Name, res)
+			if err != nil {
+				return outputResources, err
+			}
+			def, err := reportsResource.StructString()
+			if err != nil {
+				return outputResources, err
+			}
+			currentResource["def"] = def
+			currentResource["objectname"] = reportsResource.StructName()
+			outputResources = append(outputResources, currentResource)
 		}
 	}
 
@@ -402,10 +500,15 @@ func MetadataFiles(sourceDirectory string) ([]string, error) {
 func stepTemplate(myStepInfo stepInfo) []byte {
 
 	funcMap := template.FuncMap{
-		"flagType":   flagType,
-		"golangName": golangNameTitle,
-		"title":      strings.Title,
-		"longName":   longName,
+		"flagType":       flagType,
+		"golangName":     golangNameTitle,
+		"title":          strings.Title,
+		"longName":       longName,
+		"hasFlag":        hasFlag,
+		"goValue":        goValue,
+		"usesPiperenv":   usesPiperenv,
+		"usesPiperutils": usesPiperutils,
+		"stringValues":   stringValues,
 	}
 
 	tmpl, err := template.New("step").Funcs(funcMap).Parse(stepGoTemplate)
@@ -452,6 +555,19 @@ func stepImplementation(myStepInfo stepInfo) []byte {
 	return generatedCode.Bytes()
 }
 
+func stepMetadataTemplate(stepNames []string) []byte {
+	sort.Strings(stepNames)
+
+	tmpl, err := template.New("metadata").Parse(metadataGeneratedTemplate)
+	checkError(err)
+
+	var generatedCode bytes.Buffer
+	err = tmpl.Execute(&generatedCode, stepNames)
+	checkError(err)
+
+	return generatedCode.Bytes()
+}
+
 func longName(long string) string {
 	l := strings.ReplaceAll(long, "`", "` + \"`\" + `")
 	l = strings.TrimSpace(l)
@@ -484,6 +600,10 @@ func flagType(paramType string) string {
 		theFlagType = "StringVar"
 	case "[]string":
 		theFlagType = "StringSliceVar"
+	case "float64":
+		theFlagType = "Float64Var"
+	case "[]int":
+		theFlagType = "IntSliceVar"
 	default:
 		fmt.Printf("Meta data type not set or not known: '%v'\n", paramType)
 		os.Exit(1)
@@ -491,6 +611,45 @@ func flagType(paramType string) string {
 	return theFlagType
 }
 
+// hasFlag returns true if a command line flag is generated for the parameter, maps are only available via configuration
+func hasFlag(param config.StepParameters) bool {
+	return param.Type != "map[string]interface{}"
+}
+
+// usesPiperenv checks whether the output resources are persisted via piperenv, i.e. all resources except reports
+func usesPiperenv(outputResources []map[string]string) bool {
+	for _, res := range outputResources {
+		if res["type"] != "reports" {
+			return true
+		}
+	}
+	return false
+}
+
+// usesPiperutils checks whether the output resources contain reports which are persisted via piperutils
+func usesPiperutils(outputResources []map[string]string) bool {
+	for _, res := range outputResources {
+		if res["type"] == "reports" {
+			return true
+		}
+	}
+	return false
+}
+
+// goValue returns the Go literal of a value read from the metadata
+func goValue(value interface{}) string {
+	return fmt.Sprintf("%#v", value)
+}
+
+// stringValues returns the values as list of Go string literals, e.g. for the completion of flag values
+func stringValues(values []interface{}) string {
+	literals := []string{}
+	for _, value := range values {
+		literals = append(literals, fmt.Sprintf("%q", fmt.Sprint(value)))
+	}
+	return strings.Join(literals, ", ")
+}
+
 func getStringSliceFromInterface(iSlice interface{}) []string {
 	s := []string{}
 
@@ -39,7 +39,22 @@ spec:
               - name: f1
             tags:
               - name: t1
+      - name: reports
+        type: reports
+        params:
+          - name: report1
+            type: report
+            mandatory: true
+          - name: link1
+            type: link
+            title: Test link
   inputs:
+    secrets:
+      - name: testCredentialsId
+        type: jenkins
+        credentialType: token
+        params:
+          - param1
     params:
       - name: param0
         type: string
@@ -48,7 +63,6 @@ spec:
         scope:
         - GENERAL
         - PARAMETERS
-        mandatory: true
       - name: param1
         type: string
         description: param1 description
@@ -60,6 +74,33 @@ spec:
         scope:
         - PARAMETERS
         mandatory: true
+      - name: param3
+        type: string
+        description: param3 description
+        scope:
+        - PARAMETERS
+        possibleValues:
+        - value1
+        - value2
+      - name: param4
+        type: float64
+        description: param4 description
+        default: 0.5
+        scope:
+        - PARAMETERS
+      - name: param5
+        type: "[]int"
+        description: param5 description
+        default:
+        - 1
+        - 2
+        scope:
+        - PARAMETERS
+      - name: param6
+        type: map[string]interface{}
+        description: param6 description
+        scope:
+        - PARAMETERS
 `
 	var r string
 	switch name {
@@ -106,6 +147,20 @@ func TestProcessMetaFiles(t *testing.T) {
 		assert.Equal(t, expected, files["cmd/testStep_generated_test.go"])
 	})
 
+	t.Run("groovy step", func(t *testing.T) {
+		goldenFilePath := filepath.Join("testdata", t.Name()+"_generated.golden")
+		expected, err := ioutil.ReadFile(goldenFilePath)
+		if err != nil {
+			t.Fatalf("failed reading %v", goldenFilePath)
+		}
+		assert.Equal(t, expected, files["vars/testStep.groovy"])
+	})
+
+	t.Run("step metadata", func(t *testing.T) {
+		assert.Contains(t, string(files["cmd/metadata_generated.go"]), "func GetAllStepMetadata() map[string]config.StepData {")
+		assert.Contains(t, string(files["cmd/metadata_generated.go"]), `"testStep": testStepMetadata(),`)
+	})
+
 	t.Run("custom step code", func(t *testing.T) {
 		stepHelperData = StepHelperData{configOpenFileMock, writeFileMock, "piperOsCmd"}
 		ProcessMetaFiles([]string{"test.yaml"}, stepHelperData, docuHelperData)
@@ -120,6 +175,28 @@ func TestProcessMetaFiles(t *testing.T) {
 	})
 }
 
+func TestStepTemplateReturnsError(t *testing.T) {
+	stepInfo := stepInfo{StepName: "testStep", CobraCmdFuncName: "TestStepCommand", CreateCmdVar: "createTestStepCmd", FlagsFunc: "addTestStepFlags"}
+
+	assert.Contains(t, string(stepTemplate(stepInfo)), `log.StartGroup("Execution")
+			testStep(stepConfig, &telemetryData)
+			log.EndGroup()
+`)
+
+	stepInfo.StepReturnsError = true
+	assert.Contains(t, string(stepTemplate(stepInfo)), `log.StartGroup("Execution")
+			err := testStep(stepConfig, &telemetryData)
+			log.EndGroup()
+			if err != nil {
+				log.Entry().WithError(err).Fatal("step execution failed")
+			}`)
+}
+
+func TestStringValues(t *testing.T) {
+	assert.Equal(t, `"value1", "value 2", "3"`, stringValues([]interface{}{"value1", "value 2", 3}))
+	assert.Equal(t, "", stringValues([]interface{}{}))
+}
+
 func TestSetDefaultParameters(t *testing.T) {
 	t.Run("success case", func(t *testing.T) {
 		sliceVals := []string{"val4_1", "val4_2"}
@@ -139,6 +216,12 @@ func TestSetDefaultParameters(t *testing.T) {
 						{Name: "param5", Type: "[]string"},
 						{Name: "param6", Type: "int"},
 						{Name: "param7", Type: "int", Default: 1},
+						{Name: "param8", Type: "float64"},
+						{Name: "param9", Type: "float64", Default: 0.5},
+						{Name: "param10", Type: "[]int"},
+						{Name: "param11", Type: "[]int", Default: []interface{}{1, 2}},
+						{Name: "param12", Type: "map[string]interface{}"},
+						{Name: "param13", Type: "string", Default: `^.*deploy -i (\S+) -a "abort"$`},
 					},
 				},
 			},
@@ -153,6 +236,12 @@ func TestSetDefaultParameters(t *testing.T) {
 			"[]string{}",
 			"0",
 			"1",
+			"0",
+			"0.5",
+			"[]int{}",
+			"[]int{1, 2}",
+			"nil",
+			`"^.*deploy -i (\\S+) -a \"abort\"$"`,
 		}
 
 		osImport, err := setDefaultParameters(&stepData)
@@ -203,6 +292,7 @@ func TestGetStepInfo(t *testing.T) {
 			Name:            "testStep",
 			Description:     "Test description",
 			LongDescription: "Long Test description",
+			ReturnsError:    true,
 		},
 		Spec: config.StepSpec{
 			Inputs: config.StepInputs{
@@ -225,6 +315,7 @@ func TestGetStepInfo(t *testing.T) {
 	assert.Equal(t, stepData.Spec.Inputs.Parameters, myStepInfo.StepParameters, "Metadata incorrect")
 	assert.Equal(t, "addTestStepFlags", myStepInfo.FlagsFunc, "FlagsFunc incorrect")
 	assert.Equal(t, "addTestStepFlags", myStepInfo.FlagsFunc, "FlagsFunc incorrect")
+	assert.True(t, myStepInfo.StepReturnsError, "StepReturnsError incorrect")
 
 }
 
@@ -269,6 +360,8 @@ func TestFlagType(t *testing.T) {
 		{input: "int", expected: "IntVar"},
 		{input: "string", expected: "StringVar"},
 		{input: "[]string", expected: "StringSliceVar"},
+		{input: "float64", expected: "Float64Var"},
+		{input: "[]int", expected: "IntSliceVar"},
 	}
 
 	for k, v := range tt {
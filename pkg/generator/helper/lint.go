@@ -0,0 +1,182 @@
+package helper
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+)
+
+var knownTypes = []string{"string", "bool", "int", "float64", "[]string", "[]int", "map[string]interface{}"}
+var knownScopes = []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"}
+var knownOutputTypes = []string{"piperEnvironment", "influx", "reports"}
+
+// lintStepData checks the metadata of a step for problems which would lead to broken or misleading generated code
+func lintStepData(stepData config.StepData) []string {
+	problems := []string{}
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if len(stepData.Metadata.Name) == 0 {
+		addProblem("step name missing")
+	}
+	if len(strings.TrimSpace(stepData.Metadata.Description)) == 0 {
+		addProblem("step description missing")
+	}
+
+	// names of parameters and aliases, used to detect collisions
+	names := map[string]string{}
+	for _, param := range stepData.Spec.Inputs.Parameters {
+		if len(param.Name) == 0 {
+			addProblem("parameter without name")
+			continue
+		}
+		if owner, ok := names[param.Name]; ok {
+			if owner == param.Name {
+				addProblem("parameter '%v' defined multiple times", param.Name)
+			} else {
+				addProblem("parameter '%v' collides with alias of parameter '%v'", param.Name, owner)
+			}
+		}
+		names[param.Name] = param.Name
+	}
+
+	for _, param := range stepData.Spec.Inputs.Parameters {
+		if len(param.Name) == 0 {
+			continue
+		}
+		if !contains(knownTypes, param.Type) {
+			addProblem("parameter '%v': type '%v' not known, possible types are %v", param.Name, param.Type, strings.Join(knownTypes, ", "))
+		}
+		if len(strings.TrimSpace(param.Description)) == 0 {
+			addProblem("parameter '%v': description missing", param.Name)
+		}
+		for _, scope := range param.Scope {
+			if !contains(knownScopes, scope) {
+				addProblem("parameter '%v': scope '%v' not known, possible scopes are %v", param.Name, scope, strings.Join(knownScopes, ", "))
+			}
+		}
+		if param.Mandatory && param.Default != nil {
+			addProblem("parameter '%v': mandatory parameters must not have a default value", param.Name)
+		}
+		if len(param.PossibleValues) > 0 && param.Default != nil {
+			for _, value := range getStringSliceFromInterface(param.Default) {
+				if !containsValue(param.PossibleValues, value) {
+					addProblem("parameter '%v': default value '%v' is not one of the possible values", param.Name, value)
+				}
+			}
+		}
+		for _, alias := range param.Aliases {
+			if owner, ok := names[alias.Name]; ok {
+				if owner == alias.Name {
+					addProblem("parameter '%v': alias '%v' collides with parameter '%v'", param.Name, alias.Name, owner)
+				} else {
+					addProblem("parameter '%v': alias '%v' collides with alias of parameter '%v'", param.Name, alias.Name, owner)
+				}
+				continue
+			}
+			names[alias.Name] = param.Name
+		}
+		problems = append(problems, lintConditions(fmt.Sprintf("parameter '%v'", param.Name), param.Conditions, stepData)...)
+	}
+
+	for _, secret := range stepData.Spec.Inputs.Secrets {
+		if len(secret.CredentialType) == 0 {
+			continue
+		}
+		count, ok := credentialTypes[secret.CredentialType]
+		if !ok {
+			addProblem("secret '%v': credential type '%v' not known", secret.Name, secret.CredentialType)
+		} else if count != len(secret.Params) {
+			addProblem("secret '%v': credential type '%v' requires %v parameter(s)", secret.Name, secret.CredentialType, count)
+		}
+		for _, param := range secret.Params {
+			if !hasParameter(stepData, param) {
+				addProblem("secret '%v': parameter '%v' does not exist", secret.Name, param)
+			}
+		}
+	}
+
+	for _, res := range stepData.Spec.Outputs.Resources {
+		if !contains(knownOutputTypes, res.Type) {
+			addProblem("output resource '%v': type '%v' not known, possible types are %v", res.Name, res.Type, strings.Join(knownOutputTypes, ", "))
+			continue
+		}
+		if res.Type == "reports" {
+			if _, err := newReportsResource(stepData.Metadata.Name, res); err != nil {
+				addProblem("%v", err)
+			}
+		}
+	}
+
+	for _, container := range stepData.Spec.Containers {
+		problems = append(problems, lintConditions(fmt.Sprintf("container '%v'", container.Name), container.Conditions, stepData)...)
+	}
+	for _, sidecar := range stepData.Spec.Sidecars {
+		problems = append(problems, lintConditions(fmt.Sprintf("sidecar '%v'", sidecar.Name), sidecar.Conditions, stepData)...)
+	}
+	for _, resource := range stepData.Spec.Inputs.Resources {
+		problems = append(problems, lintConditions(fmt.Sprintf("resource '%v'", resource.Name), resource.Conditions, stepData)...)
+	}
+
+	return problems
+}
+
+func lintConditions(context string, conditions []config.Condition, stepData config.StepData) []string {
+	problems := []string{}
+	for _, condition := range conditions {
+		for _, conditionParam := range condition.Params {
+			if !hasParameter(stepData, conditionParam.Name) {
+				problems = append(problems, fmt.Sprintf("%v: condition references parameter '%v' which does not exist", context, conditionParam.Name))
+			}
+		}
+	}
+	return problems
+}
+
+func hasParameter(stepData config.StepData, name string) bool {
+	for _, param := range stepData.Spec.Inputs.Parameters {
+		if param.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if fmt.Sprint(v) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// LintMetaFiles checks all metadata files and returns an error listing all problems found
+func LintMetaFiles(metadataFiles []string, openFile func(s string) (io.ReadCloser, error)) error {
+	problems := []string{}
+	for _, metadataFile := range metadataFiles {
+		file, err := openFile(metadataFile)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%v: %v", metadataFile, err))
+			continue
+		}
+		var stepData config.StepData
+		err = stepData.ReadPipelineStepData(file)
+		file.Close()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%v: %v", metadataFile, err))
+			continue
+		}
+		for _, problem := range lintStepData(stepData) {
+			problems = append(problems, fmt.Sprintf("%v: %v", metadataFile, problem))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("metadata contains %v problem(s):\n  %v", len(problems), strings.Join(problems, "\n  "))
+	}
+	return nil
+}
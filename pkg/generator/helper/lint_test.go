@@ -0,0 +1,93 @@
+package helper
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintStepData(t *testing.T) {
+	t.Run("valid metadata", func(t *testing.T) {
+		stepData := config.StepData{
+			Metadata: config.StepMetadata{Name: "testStep", Description: "Test description"},
+			Spec: config.StepSpec{
+				Inputs: config.StepInputs{Parameters: []config.StepParameters{
+					{Name: "buildTool", Type: "string", Description: "build tool", Scope: []string{"GENERAL", "STEPS"}, Default: "maven", PossibleValues: []interface{}{"maven", "npm"}},
+					{Name: "scanners", Type: "[]string", Description: "scanners", Scope: []string{"STEPS"}, Default: []interface{}{"a"}, PossibleValues: []interface{}{"a", "b"}},
+					{Name: "m2Path", Type: "string", Description: "m2 path", Mandatory: true, Aliases: []config.Alias{{Name: "maven/m2Path"}}},
+				}},
+				Containers: []config.Container{
+					{Name: "mvn", Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "buildTool", Value: "maven"}}}}},
+				},
+			},
+		}
+
+		assert.Equal(t, []string{}, lintStepData(stepData))
+	})
+
+	t.Run("invalid metadata", func(t *testing.T) {
+		stepData := config.StepData{
+			Spec: config.StepSpec{
+				Inputs: config.StepInputs{
+					Parameters: []config.StepParameters{
+						{Name: "param1", Type: "map", Description: "param1", Scope: []string{"STEP"}},
+						{Name: "param1", Type: "string", Description: "param1 again"},
+						{Name: "param2", Type: "string", Mandatory: true, Default: "value", Aliases: []config.Alias{{Name: "param1"}, {Name: "alias"}}},
+						{Name: "param3", Type: "string", Description: "param3", Aliases: []config.Alias{{Name: "alias"}}, Default: "c", PossibleValues: []interface{}{"a", "b"}},
+						{Name: "alias", Type: "bool", Description: "alias"},
+					},
+					Secrets: []config.StepSecrets{
+						{Name: "credentialsId", CredentialType: "usernamePassword", Params: []string{"param3"}},
+						{Name: "tokenCredentialsId", CredentialType: "secretText", Params: []string{"unknown"}},
+						{Name: "otherCredentialsId"},
+					},
+				},
+				Outputs: config.StepOutputs{Resources: []config.StepResources{
+					{Name: "influx", Type: "influxDB"},
+					{Name: "reports", Type: "reports", Parameters: []map[string]interface{}{{"name": "report1", "type": "file"}}},
+				}},
+				Sidecars: []config.Container{
+					{Name: "db", Conditions: []config.Condition{{ConditionRef: "strings-equal", Params: []config.Param{{Name: "unknown", Value: "x"}}}}},
+				},
+			},
+		}
+
+		assert.Equal(t, []string{
+			"step name missing",
+			"step description missing",
+			"parameter 'param1' defined multiple times",
+			"parameter 'param1': type 'map' not known, possible types are string, bool, int, float64, []string, []int, map[string]interface{}",
+			"parameter 'param1': scope 'STEP' not known, possible scopes are GENERAL, PARAMETERS, STAGES, STEPS",
+			"parameter 'param2': description missing",
+			"parameter 'param2': mandatory parameters must not have a default value",
+			"parameter 'param2': alias 'param1' collides with parameter 'param1'",
+			"parameter 'param2': alias 'alias' collides with parameter 'alias'",
+			"parameter 'param3': default value 'c' is not one of the possible values",
+			"parameter 'param3': alias 'alias' collides with parameter 'alias'",
+			"secret 'credentialsId': credential type 'usernamePassword' requires 2 parameter(s)",
+			"secret 'tokenCredentialsId': credential type 'secretText' not known",
+			"secret 'tokenCredentialsId': parameter 'unknown' does not exist",
+			"output resource 'influx': type 'influxDB' not known, possible types are piperEnvironment, influx, reports",
+			"reports resource reports: type 'file' of report1 not supported, possible types are report and link",
+			"sidecar 'db': condition references parameter 'unknown' which does not exist",
+		}, lintStepData(stepData))
+	})
+}
+
+func TestLintMetaFiles(t *testing.T) {
+	openFile := func(name string) (io.ReadCloser, error) {
+		if name == "invalid.yaml" {
+			return ioutil.NopCloser(strings.NewReader("metadata:\n  name: invalidStep\n")), nil
+		}
+		return configOpenFileMock(name)
+	}
+
+	assert.NoError(t, LintMetaFiles([]string{"test.yaml"}, openFile))
+
+	err := LintMetaFiles([]string{"test.yaml", "invalid.yaml"}, openFile)
+	assert.EqualError(t, err, "metadata contains 1 problem(s):\n  invalid.yaml: step description missing")
+}
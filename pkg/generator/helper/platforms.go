@@ -0,0 +1,364 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// platformInput describes a step parameter which can be provided as input on GitHub Actions or Azure Pipelines
+type platformInput struct {
+	Name        string
+	Description string
+	Type        string
+	Mandatory   bool
+	// Secret inputs are passed as environment variable PIPER_<name> instead of being part of the parameters JSON
+	Secret         bool
+	PossibleValues []string
+}
+
+// jqParameters converts the inputs of a GitHub action (all strings) into the parameters JSON of the piper binary.
+// Empty inputs are omitted in order not to override the project configuration.
+const jqParameters = `with_entries(select(.value != "" and $types[.key] != null) | .key as $key | .value |= (
+  if $types[$key] == "bool" then . == "true"
+  elif $types[$key] == "int" or $types[$key] == "float64" then tonumber
+  elif $types[$key] == "[]string" then [splits("[,\n]") | gsub("^\\s+|\\s+$"; "") | select(length > 0)]
+  elif $types[$key] == "[]int" then [splits("[,\n]") | gsub("\\s"; "") | select(length > 0) | tonumber]
+  elif $types[$key] == "map[string]interface{}" then fromjson
+  else . end))`
+
+const gitHubActionTemplate = `# Code generated by piper's step-generator. DO NOT EDIT.
+
+name: [[ printf "piper %v" .StepName | json ]]
+description: [[ .Description | json ]]
+inputs:
+[[- range .Inputs ]]
+  [[ .Name ]]:
+    description: [[ .Description | json ]]
+    required: [[ .Mandatory ]]
+[[- end ]]
+runs:
+  using: composite
+  steps:
+    - name: [[ printf "Run piper %v" .StepName | json ]]
+      shell: bash
+      env:
+        PIPER_inputs: ${{ toJSON(inputs) }}
+[[- range .Inputs ]][[ if .Secret ]]
+        PIPER_[[ .Name ]]: ${{ inputs.[[ .Name ]] }}
+[[- end ]][[ end ]]
+      run: |
+        parameters=$(jq --compact-output --argjson types '[[ .Types ]]' '
+[[ .JQ | indent 10 ]]
+        ' <<< "${PIPER_inputs}")
+        piper [[ .StepName ]] --parametersJSON "${parameters}"
+`
+
+const azureTaskRunnerTemplate = `// Code generated by piper's step-generator. DO NOT EDIT.
+'use strict'
+
+const childProcess = require('child_process')
+
+const stepName = '[[ .StepName ]]'
+const types = [[ .Types ]]
+const secrets = [[ .Secrets ]]
+
+// inputs of a task are provided by the agent as environment variables INPUT_<NAME>
+function input(name) {
+  return process.env['INPUT_' + name.toUpperCase()] || ''
+}
+
+function list(value) {
+  return value.split(/[,\n]/).map(v => v.trim()).filter(v => v.length > 0)
+}
+
+function convert(value, type) {
+  switch (type) {
+    case 'bool':
+      return value === 'true'
+    case 'int':
+    case 'float64':
+      return Number(value)
+    case '[]string':
+      return list(value)
+    case '[]int':
+      return list(value).map(Number)
+    case 'map[string]interface{}':
+      return JSON.parse(value)
+    default:
+      return value
+  }
+}
+
+// empty inputs are omitted in order not to override the project configuration
+const parameters = {}
+Object.keys(types).forEach(name => {
+  const value = input(name)
+  if (value.length > 0) parameters[name] = convert(value, types[name])
+})
+
+// secrets are not passed on the command line but via environment variables
+const env = Object.assign({}, process.env)
+secrets.forEach(name => {
+  const value = input(name)
+  if (value.length > 0) env['PIPER_' + name] = value
+})
+
+const result = childProcess.spawnSync('piper', [stepName, '--parametersJSON', JSON.stringify(parameters)], { stdio: 'inherit', env: env })
+if (result.error || result.status !== 0) {
+  const reason = result.error ? result.error.message : 'exit code ' + result.status
+  console.log('##vso[task.complete result=Failed;]piper ' + stepName + ' failed: ' + reason)
+  process.exit(result.status || 1)
+}
+`
+
+type azureTask struct {
+	ID                 string            `json:"id"`
+	Name               string            `json:"name"`
+	FriendlyName       string            `json:"friendlyName"`
+	Description        string            `json:"description"`
+	Category           string            `json:"category"`
+	Author             string            `json:"author"`
+	Version            azureTaskVersion  `json:"version"`
+	InstanceNameFormat string            `json:"instanceNameFormat"`
+	Inputs             []azureTaskInput  `json:"inputs"`
+	Execution          map[string]target `json:"execution"`
+}
+
+type azureTaskVersion struct {
+	Major int `json:"Major"`
+	Minor int `json:"Minor"`
+	Patch int `json:"Patch"`
+}
+
+type azureTaskInput struct {
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	Label        string            `json:"label"`
+	DefaultValue string            `json:"defaultValue"`
+	Required     bool              `json:"required"`
+	HelpMarkDown string            `json:"helpMarkDown"`
+	Options      map[string]string `json:"options,omitempty"`
+}
+
+type target struct {
+	Target string `json:"target"`
+}
+
+// ProcessGitHubActions creates a composite GitHub action <targetDir>/<stepName>/action.yml per step
+// which calls the piper binary available on the PATH
+func ProcessGitHubActions(metadataFiles []string, stepHelperData StepHelperData, targetDir string) error {
+	steps, err := readStepData(metadataFiles, stepHelperData)
+	if err != nil {
+		return err
+	}
+	for _, stepData := range steps {
+		if contains(noPipelineSteps, stepData.Metadata.Name) {
+			continue
+		}
+		action, err := gitHubAction(stepData)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create GitHub action for step %v", stepData.Metadata.Name)
+		}
+		if err := stepHelperData.WriteFile(filepath.Join(targetDir, stepData.Metadata.Name, "action.yml"), action, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProcessAzureTasks creates an Azure DevOps task per step consisting of <targetDir>/<stepName>/task.json
+// and the runner index.js which calls the piper binary available on the PATH
+func ProcessAzureTasks(metadataFiles []string, stepHelperData StepHelperData, targetDir string) error {
+	steps, err := readStepData(metadataFiles, stepHelperData)
+	if err != nil {
+		return err
+	}
+	for _, stepData := range steps {
+		if contains(noPipelineSteps, stepData.Metadata.Name) {
+			continue
+		}
+		task, runner, err := azureTaskDefinition(stepData)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create Azure task for step %v", stepData.Metadata.Name)
+		}
+		if err := stepHelperData.WriteFile(filepath.Join(targetDir, stepData.Metadata.Name, "task.json"), task, 0644); err != nil {
+			return err
+		}
+		if err := stepHelperData.WriteFile(filepath.Join(targetDir, stepData.Metadata.Name, "index.js"), runner, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// platformInputs provides the parameters which can be passed via parameters JSON, i.e. parameters with scope PARAMETERS,
+// and the parameters receiving secrets
+func platformInputs(stepData config.StepData) []platformInput {
+	secretParams := []string{}
+	for _, secret := range stepData.Spec.Inputs.Secrets {
+		secretParams = append(secretParams, secret.Params...)
+	}
+
+	inputs := []platformInput{}
+	for _, param := range stepData.Spec.Inputs.Parameters {
+		secret := contains(secretParams, param.Name)
+		if !secret && !contains(param.Scope, "PARAMETERS") {
+			continue
+		}
+		input := platformInput{
+			Name:        param.Name,
+			Description: strings.TrimSpace(param.Description),
+			Type:        param.Type,
+			Mandatory:   param.Mandatory,
+			Secret:      secret,
+		}
+		if param.Default != nil {
+			input.Description = appendSentence(input.Description, fmt.Sprintf("Default: `%v`.", platformValue(param.Default)))
+		}
+		if secret {
+			input.Description = appendSentence(input.Description, "Please provide the value via a secret.")
+		}
+		for _, value := range param.PossibleValues {
+			input.PossibleValues = append(input.PossibleValues, fmt.Sprint(value))
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs
+}
+
+func appendSentence(text, sentence string) string {
+	if len(text) == 0 {
+		return sentence
+	}
+	if !strings.HasSuffix(text, ".") {
+		text += "."
+	}
+	return text + " " + sentence
+}
+
+// platformValue formats a value the way it is entered as input, i.e. lists are comma separated and maps are JSON
+func platformValue(value interface{}) string {
+	switch v := value.(type) {
+	case []interface{}:
+		return strings.Join(getStringSliceFromInterface(v), ",")
+	case map[string]interface{}:
+		content, _ := json.Marshal(v)
+		return string(content)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// inputTypes provides the types of the inputs passed via parameters JSON as JSON object
+func inputTypes(inputs []platformInput) string {
+	types := map[string]string{}
+	for _, input := range inputs {
+		if !input.Secret {
+			types[input.Name] = input.Type
+		}
+	}
+	content, _ := json.Marshal(types)
+	return string(content)
+}
+
+func platformTemplate(name, text string) *template.Template {
+	funcMap := template.FuncMap{
+		"json": func(value interface{}) (string, error) {
+			content, err := json.Marshal(value)
+			return string(content), err
+		},
+		"indent": func(spaces int, text string) string {
+			prefix := strings.Repeat(" ", spaces)
+			return prefix + strings.Replace(text, "\n", "\n"+prefix, -1)
+		},
+	}
+	// GitHub expressions use {{ }}, therefore different delimiters are used
+	return template.Must(template.New(name).Delims("[[", "]]").Funcs(funcMap).Parse(text))
+}
+
+func gitHubAction(stepData config.StepData) ([]byte, error) {
+	inputs := platformInputs(stepData)
+	info := map[string]interface{}{
+		"StepName":    stepData.Metadata.Name,
+		"Description": stepData.Metadata.Description,
+		"Inputs":      inputs,
+		"Types":       inputTypes(inputs),
+		"JQ":          jqParameters,
+	}
+
+	var action bytes.Buffer
+	if err := platformTemplate("action", gitHubActionTemplate).Execute(&action, info); err != nil {
+		return nil, err
+	}
+	return action.Bytes(), nil
+}
+
+func azureTaskDefinition(stepData config.StepData) ([]byte, []byte, error) {
+	inputs := platformInputs(stepData)
+
+	task := azureTask{
+		// the id of a task must not change, thus it is derived from the step name
+		ID:                 uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/SAP/jenkins-library/azure/"+stepData.Metadata.Name)).String(),
+		Name:               stepData.Metadata.Name,
+		FriendlyName:       fmt.Sprintf("piper %v", stepData.Metadata.Name),
+		Description:        stepData.Metadata.Description,
+		Category:           "Utility",
+		Author:             "SAP",
+		Version:            azureTaskVersion{Major: 1},
+		InstanceNameFormat: fmt.Sprintf("piper %v", stepData.Metadata.Name),
+		Inputs:             []azureTaskInput{},
+		Execution:          map[string]target{"Node10": {Target: "index.js"}},
+	}
+
+	secrets := []string{}
+	for _, input := range inputs {
+		taskInput := azureTaskInput{
+			Name:         input.Name,
+			Type:         "string",
+			Label:        input.Name,
+			Required:     input.Mandatory,
+			HelpMarkDown: input.Description,
+		}
+		switch {
+		case input.Type == "bool":
+			taskInput.Type = "boolean"
+		case input.Type == "map[string]interface{}" || strings.HasPrefix(input.Type, "[]"):
+			taskInput.Type = "multiLine"
+		case len(input.PossibleValues) > 0:
+			taskInput.Type = "pickList"
+			taskInput.Options = map[string]string{}
+			for _, value := range input.PossibleValues {
+				taskInput.Options[value] = value
+			}
+		}
+		if input.Secret {
+			secrets = append(secrets, input.Name)
+		}
+		task.Inputs = append(task.Inputs, taskInput)
+	}
+
+	taskJSON, err := json.MarshalIndent(task, "", "  ")
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal task.json")
+	}
+
+	secretsJSON, _ := json.Marshal(secrets)
+	info := map[string]interface{}{
+		"StepName": stepData.Metadata.Name,
+		"Types":    inputTypes(inputs),
+		"Secrets":  string(secretsJSON),
+	}
+	var runner bytes.Buffer
+	if err := platformTemplate("runner", azureTaskRunnerTemplate).Execute(&runner, info); err != nil {
+		return nil, nil, err
+	}
+	return append(taskJSON, '\n'), runner.Bytes(), nil
+}
@@ -0,0 +1,103 @@
+package helper
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func platformTestStepData() config.StepData {
+	return config.StepData{
+		Metadata: config.StepMetadata{Name: "testStep", Description: "Test description"},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "credentialsId", Type: "jenkins", CredentialType: "usernamePassword", Params: []string{"user", "password"}},
+				},
+				Parameters: []config.StepParameters{
+					{Name: "mode", Type: "string", Description: "The mode", Scope: []string{"PARAMETERS", "STEPS"}, Default: "deploy", PossibleValues: []interface{}{"deploy", "undeploy"}},
+					{Name: "verbose", Type: "bool", Description: "Verbose output.", Scope: []string{"PARAMETERS"}},
+					{Name: "targets", Type: "[]string", Description: "Targets", Scope: []string{"PARAMETERS"}, Mandatory: true},
+					{Name: "stepOnly", Type: "string", Description: "Only configurable in steps section", Scope: []string{"STEPS"}},
+					{Name: "user", Type: "string", Description: "User", Scope: []string{"STEPS"}},
+					{Name: "password", Type: "string", Description: "Password", Scope: []string{"PARAMETERS", "STEPS"}},
+				},
+			},
+		},
+	}
+}
+
+func TestPlatformInputs(t *testing.T) {
+	inputs := platformInputs(platformTestStepData())
+
+	assert.Equal(t, []platformInput{
+		{Name: "mode", Description: "The mode. Default: `deploy`.", Type: "string", PossibleValues: []string{"deploy", "undeploy"}},
+		{Name: "verbose", Description: "Verbose output.", Type: "bool"},
+		{Name: "targets", Description: "Targets", Type: "[]string", Mandatory: true},
+		{Name: "user", Description: "User. Please provide the value via a secret.", Type: "string", Secret: true},
+		{Name: "password", Description: "Password. Please provide the value via a secret.", Type: "string", Secret: true},
+	}, inputs)
+	assert.Equal(t, `{"mode":"string","targets":"[]string","verbose":"bool"}`, inputTypes(inputs))
+}
+
+func TestPlatformValue(t *testing.T) {
+	assert.Equal(t, "a,b", platformValue([]interface{}{"a", "b"}))
+	assert.Equal(t, `{"key":"value"}`, platformValue(map[string]interface{}{"key": "value"}))
+	assert.Equal(t, "true", platformValue(true))
+}
+
+func TestGitHubAction(t *testing.T) {
+	content, err := gitHubAction(platformTestStepData())
+	assert.NoError(t, err)
+
+	var action map[string]interface{}
+	assert.NoError(t, yaml.Unmarshal(content, &action), "action.yml is no valid yaml")
+
+	assert.Equal(t, "piper testStep", action["name"])
+	inputs := action["inputs"].(map[string]interface{})
+	assert.Len(t, inputs, 5)
+	assert.Equal(t, map[string]interface{}{"description": "Targets", "required": true}, inputs["targets"])
+	// defaults are not provided as input values since they would override the project configuration
+	assert.Equal(t, map[string]interface{}{"description": "The mode. Default: `deploy`.", "required": false}, inputs["mode"])
+
+	runStep := action["runs"].(map[string]interface{})["steps"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"PIPER_inputs":   "${{ toJSON(inputs) }}",
+		"PIPER_user":     "${{ inputs.user }}",
+		"PIPER_password": "${{ inputs.password }}",
+	}, runStep["env"])
+	assert.Contains(t, runStep["run"], `--argjson types '{"mode":"string","targets":"[]string","verbose":"bool"}'`)
+	assert.Contains(t, runStep["run"], `piper testStep --parametersJSON "${parameters}"`)
+}
+
+func TestAzureTaskDefinition(t *testing.T) {
+	taskContent, runner, err := azureTaskDefinition(platformTestStepData())
+	assert.NoError(t, err)
+
+	var task azureTask
+	assert.NoError(t, json.Unmarshal(taskContent, &task), "task.json is no valid json")
+
+	t.Run("task", func(t *testing.T) {
+		assert.Equal(t, "testStep", task.Name)
+		assert.Equal(t, map[string]target{"Node10": {Target: "index.js"}}, task.Execution)
+
+		// the id must be stable across generator runs
+		other, _, _ := azureTaskDefinition(platformTestStepData())
+		assert.Equal(t, taskContent, other)
+
+		assert.Len(t, task.Inputs, 5)
+		assert.Equal(t, azureTaskInput{Name: "mode", Type: "pickList", Label: "mode", HelpMarkDown: "The mode. Default: `deploy`.", Options: map[string]string{"deploy": "deploy", "undeploy": "undeploy"}}, task.Inputs[0])
+		assert.Equal(t, "boolean", task.Inputs[1].Type)
+		assert.Equal(t, "multiLine", task.Inputs[2].Type)
+		assert.True(t, task.Inputs[2].Required)
+	})
+
+	t.Run("runner", func(t *testing.T) {
+		assert.Contains(t, string(runner), "const stepName = 'testStep'")
+		assert.Contains(t, string(runner), `const types = {"mode":"string","targets":"[]string","verbose":"bool"}`)
+		assert.Contains(t, string(runner), `const secrets = ["user","password"]`)
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 	"text/template"
+
+	"github.com/SAP/jenkins-library/pkg/config"
 )
 
 // PiperEnvironmentResource defines a piper environement resource which stores data across multiple pipeline steps
@@ -189,3 +191,116 @@ func (i *InfluxResource) StructString() (string, error) {
 func (i *InfluxResource) StructName() string {
 	return fmt.Sprintf("%v%v", i.StepName, strings.Title(i.Name))
 }
+
+// ReportsResource defines a reports resource which collects the report files and links created by a step
+type ReportsResource struct {
+	Name     string
+	StepName string
+	Reports  []ReportsEntry
+	Links    []ReportsEntry
+}
+
+// ReportsEntry defines a report file or a link within a reports resource
+type ReportsEntry struct {
+	Name      string
+	Title     string
+	Scope     string
+	Mandatory bool
+}
+
+const reportsStructTemplate = `type {{ .StepName }}{{ .Name | title}} struct {
+	{{- range $notused, $report := .Reports }}
+	{{ $report.Name | golangName }} string
+	{{- end }}
+	{{- range $notused, $link := .Links }}
+	{{ $link.Name | golangName }} string
+	{{- end }}
+}
+
+func (r *{{ .StepName }}{{ .Name | title}}) persist(workspace string) {
+	reports := []piperutils.Path{}
+	{{- range $notused, $report := .Reports }}
+	if len(r.{{ $report.Name | golangName }}) > 0 {
+		reports = append(reports, piperutils.Path{Target: r.{{ $report.Name | golangName }}, Mandatory: {{ $report.Mandatory }}})
+	}
+	{{- end }}
+	links := []piperutils.Path{}
+	{{- range $notused, $link := .Links }}
+	if len(r.{{ $link.Name | golangName }}) > 0 {
+		links = append(links, piperutils.Path{Name: "{{ $link.Title }}", Target: r.{{ $link.Name | golangName }}, Mandatory: {{ $link.Mandatory }}, Scope: "{{ $link.Scope }}"})
+	}
+	{{- end }}
+	piperutils.PersistReportsAndLinks("{{ .StepName }}", workspace, reports, links)
+}`
+
+// StructName returns the name of the reports resource struct
+func (r *ReportsResource) StructName() string {
+	return fmt.Sprintf("%v%v", r.StepName, strings.Title(r.Name))
+}
+
+// StructString returns the golang coding for the struct definition of the ReportsResource
+func (r *ReportsResource) StructString() (string, error) {
+	funcMap := template.FuncMap{
+		"title":      strings.Title,
+		"golangName": golangName,
+	}
+
+	tmpl, err := template.New("resources").Funcs(funcMap).Parse(reportsStructTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var generatedCode bytes.Buffer
+	err = tmpl.Execute(&generatedCode, &r)
+	if err != nil {
+		return "", err
+	}
+
+	return string(generatedCode.Bytes()), nil
+}
+
+// HasMandatoryReport checks whether the step declares a mandatory report file
+func (r *ReportsResource) HasMandatoryReport() bool {
+	for _, report := range r.Reports {
+		if report.Mandatory {
+			return true
+		}
+	}
+	return false
+}
+
+// HasMandatoryLink checks whether the step declares a mandatory link
+func (r *ReportsResource) HasMandatoryLink() bool {
+	for _, link := range r.Links {
+		if link.Mandatory {
+			return true
+		}
+	}
+	return false
+}
+
+// newReportsResource creates the reports resource from the parameters of the output resource defined in the step metadata
+func newReportsResource(stepName string, res config.StepResources) (ReportsResource, error) {
+	reportsResource := ReportsResource{Name: res.Name, StepName: stepName}
+	for _, param := range res.Parameters {
+		entry := ReportsEntry{Name: fmt.Sprint(param["name"])}
+		if title, ok := param["title"]; ok {
+			entry.Title = fmt.Sprint(title)
+		}
+		if scope, ok := param["scope"]; ok {
+			entry.Scope = fmt.Sprint(scope)
+		}
+		if mandatory, ok := param["mandatory"].(bool); ok {
+			entry.Mandatory = mandatory
+		}
+		switch param["type"] {
+		case "report":
+			reportsResource.Reports = append(reportsResource.Reports, entry)
+		case "link":
+			reportsResource.Links = append(reportsResource.Links, entry)
+		default:
+			return reportsResource, fmt.Errorf("reports resource %v: type '%v' of %v not supported, possible types are report and link", res.Name, param["type"], entry.Name)
+		}
+	}
+	return reportsResource, nil
+}
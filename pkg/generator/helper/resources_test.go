@@ -2,8 +2,10 @@ package helper
 
 import (
 	"fmt"
-	"github.com/stretchr/testify/assert"
 	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestStructString(t *testing.T) {
@@ -92,3 +94,55 @@ func (i *TestStepTestInflux) persist(path, resourceName string) {
 
 	}
 }
+
+func TestReportsResource(t *testing.T) {
+	res := config.StepResources{
+		Name: "reports",
+		Type: "reports",
+		Parameters: []map[string]interface{}{
+			{"name": "pdfReport", "type": "report", "mandatory": true},
+			{"name": "webUI", "type": "link", "title": "Web UI", "scope": "job"},
+		},
+	}
+
+	t.Run("resource", func(t *testing.T) {
+		reportsResource, err := newReportsResource("testStep", res)
+		assert.NoError(t, err)
+		assert.Equal(t, ReportsResource{
+			Name:     "reports",
+			StepName: "testStep",
+			Reports:  []ReportsEntry{{Name: "pdfReport", Mandatory: true}},
+			Links:    []ReportsEntry{{Name: "webUI", Title: "Web UI", Scope: "job"}},
+		}, reportsResource)
+		assert.Equal(t, "testStepReports", reportsResource.StructName())
+		assert.True(t, reportsResource.HasMandatoryReport())
+		assert.False(t, reportsResource.HasMandatoryLink())
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		reportsResource, _ := newReportsResource("testStep", res)
+		got, err := reportsResource.StructString()
+		assert.NoError(t, err)
+		assert.Equal(t, `type testStepReports struct {
+	pdfReport string
+	webUI string
+}
+
+func (r *testStepReports) persist(workspace string) {
+	reports := []piperutils.Path{}
+	if len(r.pdfReport) > 0 {
+		reports = append(reports, piperutils.Path{Target: r.pdfReport, Mandatory: true})
+	}
+	links := []piperutils.Path{}
+	if len(r.webUI) > 0 {
+		links = append(links, piperutils.Path{Name: "Web UI", Target: r.webUI, Mandatory: false, Scope: "job"})
+	}
+	piperutils.PersistReportsAndLinks("testStep", workspace, reports, links)
+}`, got)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := newReportsResource("testStep", config.StepResources{Name: "reports", Parameters: []map[string]interface{}{{"name": "report1"}}})
+		assert.EqualError(t, err, "reports resource reports: type '<nil>' of report1 not supported, possible types are report and link")
+	})
+}
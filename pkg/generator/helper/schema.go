@@ -0,0 +1,283 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/pkg/errors"
+)
+
+// jsonSchema is the subset of JSON Schema (draft-07) which is used to describe the project configuration
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	ID                   string                 `json:"$id,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	// DeprecationMessage is understood by the YAML extension of VS Code
+	DeprecationMessage string `json:"deprecationMessage,omitempty"`
+}
+
+// ProcessConfigSchema creates a JSON Schema for the project configuration (.pipeline/config.yml) containing
+// the sections general, stages and steps with the parameters of all steps according to their scope
+func ProcessConfigSchema(metadataFiles []string, stepHelperData StepHelperData, schemaFile string) error {
+	steps, err := readStepData(metadataFiles, stepHelperData)
+	if err != nil {
+		return err
+	}
+
+	schema, err := configSchema(steps)
+	if err != nil {
+		return err
+	}
+	return stepHelperData.WriteFile(schemaFile, schema, 0644)
+}
+
+func readStepData(metadataFiles []string, stepHelperData StepHelperData) ([]config.StepData, error) {
+	steps := []config.StepData{}
+	for _, metadataFile := range metadataFiles {
+		file, err := stepHelperData.OpenFile(metadataFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open metadata file %v", metadataFile)
+		}
+		var stepData config.StepData
+		err = stepData.ReadPipelineStepData(file)
+		file.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read metadata file %v", metadataFile)
+		}
+		steps = append(steps, stepData)
+	}
+	return steps, nil
+}
+
+func configSchema(steps []config.StepData) ([]byte, error) {
+	sort.Slice(steps, func(i, j int) bool { return steps[i].Metadata.Name < steps[j].Metadata.Name })
+
+	general := objectSchema("Configuration valid for all steps")
+	stage := objectSchema("Configuration valid for all steps of the stage")
+	stepsSchema := objectSchema("Configuration of individual steps")
+	generalParams := sharedParameters{}
+	stageParams := sharedParameters{}
+
+	for _, step := range steps {
+		stepSchema := objectSchema(step.Metadata.Description)
+		for _, param := range step.Spec.Inputs.Parameters {
+			paramSchema, err := parameterSchema(param)
+			if err != nil {
+				return nil, errors.Wrapf(err, "step %v", step.Metadata.Name)
+			}
+			if contains(param.Scope, "STEPS") {
+				addParameter(stepSchema, param, paramSchema)
+			}
+			if contains(param.Scope, "GENERAL") {
+				generalParams.add(param, paramSchema)
+			}
+			if contains(param.Scope, "STAGES") {
+				stageParams.add(param, paramSchema)
+			}
+		}
+		stepsSchema.Properties[step.Metadata.Name] = stepSchema
+		for _, alias := range step.Metadata.Aliases {
+			if alias.Deprecated {
+				stepsSchema.Properties[alias.Name] = deprecatedSchema(stepSchema, step.Metadata.Name)
+			}
+		}
+	}
+
+	generalParams.addTo(general)
+	stageParams.addTo(stage)
+
+	stages := objectSchema("Configuration of individual stages")
+	stages.AdditionalProperties = stage
+
+	schema := objectSchema("Configuration of project 'Piper' steps, typically stored in .pipeline/config.yml")
+	schema.Schema = "http://json-schema.org/draft-07/schema#"
+	schema.ID = "https://sap.github.io/jenkins-library/schemas/config.json"
+	schema.Title = "Project 'Piper' configuration"
+	schema.Properties["general"] = general
+	schema.Properties["stages"] = stages
+	schema.Properties["steps"] = stepsSchema
+
+	content, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal config schema")
+	}
+	return append(content, '\n'), nil
+}
+
+// objectSchema creates the schema of an object, additional properties are allowed since
+// the configuration also contains parameters of steps which are not described by metadata
+func objectSchema(description string) *jsonSchema {
+	return &jsonSchema{Type: "object", Description: description, Properties: map[string]*jsonSchema{}}
+}
+
+func parameterSchema(param config.StepParameters) (*jsonSchema, error) {
+	schema := &jsonSchema{Description: param.Description, Default: param.Default}
+	switch param.Type {
+	case "string":
+		schema.Type = "string"
+	case "bool":
+		schema.Type = "boolean"
+	case "int":
+		schema.Type = "integer"
+	case "[]string":
+		schema.Type = "array"
+		schema.Items = &jsonSchema{Type: "string"}
+		if param.Default != nil {
+			schema.Default = getStringSliceFromInterface(param.Default)
+		}
+	case "float64":
+		schema.Type = "number"
+	case "[]int":
+		schema.Type = "array"
+		schema.Items = &jsonSchema{Type: "integer"}
+	case "map[string]interface{}":
+		schema.Type = "object"
+	default:
+		return nil, fmt.Errorf("parameter %v: type '%v' not supported in config schema", param.Name, param.Type)
+	}
+
+	if len(param.PossibleValues) > 0 {
+		if schema.Items != nil {
+			schema.Items.Enum = param.PossibleValues
+		} else {
+			schema.Enum = param.PossibleValues
+		}
+	}
+	return schema, nil
+}
+
+// addParameter adds the parameter and its aliases
+func addParameter(parent *jsonSchema, param config.StepParameters, paramSchema *jsonSchema) {
+	parent.Properties[param.Name] = paramSchema
+	for _, alias := range param.Aliases {
+		aliasSchema := paramSchema
+		if alias.Deprecated {
+			aliasSchema = deprecatedSchema(paramSchema, param.Name)
+		}
+		addProperty(parent, alias.Name, aliasSchema)
+	}
+}
+
+// addProperty adds the schema unless the property already exists, names like 'maven/m2Path' are added as nested properties
+func addProperty(parent *jsonSchema, name string, schema *jsonSchema) {
+	path := strings.Split(name, "/")
+	target := parent
+	for _, name := range path[:len(path)-1] {
+		if target.Properties[name] == nil {
+			target.Properties[name] = objectSchema("")
+		}
+		if target.Properties[name].Properties == nil {
+			// a parameter with the same name already exists, nested aliases cannot be described
+			return
+		}
+		target = target.Properties[name]
+	}
+	if target.Properties[path[len(path)-1]] == nil {
+		target.Properties[path[len(path)-1]] = schema
+	}
+}
+
+// sharedParameters collects the parameters and aliases of the general and the stage section,
+// keys declared by several steps are described by the merged schema of all declarations
+type sharedParameters struct {
+	keys    []string
+	schemas map[string]*jsonSchema
+}
+
+func (p *sharedParameters) add(param config.StepParameters, paramSchema *jsonSchema) {
+	p.merge(param.Name, paramSchema)
+	for _, alias := range param.Aliases {
+		if alias.Deprecated {
+			p.merge(alias.Name, deprecatedSchema(paramSchema, param.Name))
+		} else {
+			p.merge(alias.Name, paramSchema)
+		}
+	}
+}
+
+func (p *sharedParameters) merge(key string, schema *jsonSchema) {
+	if p.schemas == nil {
+		p.schemas = map[string]*jsonSchema{}
+	}
+	if existing, ok := p.schemas[key]; ok {
+		p.schemas[key] = mergeSchema(existing, schema)
+		return
+	}
+	p.keys = append(p.keys, key)
+	p.schemas[key] = schema
+}
+
+// addTo adds all collected keys to the parent, plain keys take precedence over nested aliases like 'maven/m2Path'
+func (p *sharedParameters) addTo(parent *jsonSchema) {
+	for _, key := range p.keys {
+		if !strings.Contains(key, "/") {
+			parent.Properties[key] = p.schemas[key]
+		}
+	}
+	for _, key := range p.keys {
+		if strings.Contains(key, "/") {
+			addProperty(parent, key, p.schemas[key])
+		}
+	}
+}
+
+// mergeSchema combines the schemas of a parameter declared by several steps,
+// i.e. possible values are joined and type or default are left out if the steps do not agree on them
+func mergeSchema(schema, other *jsonSchema) *jsonSchema {
+	merged := *schema
+	if merged.Type != other.Type {
+		merged.Type = ""
+		merged.Items = nil
+	} else if merged.Items != nil && other.Items != nil {
+		merged.Items = mergeSchema(merged.Items, other.Items)
+	}
+	if !reflect.DeepEqual(merged.Default, other.Default) {
+		merged.Default = nil
+	}
+	merged.Enum = mergeEnum(merged.Enum, other.Enum)
+	if len(merged.Type) == 0 {
+		merged.Enum = nil
+	}
+	if merged.DeprecationMessage != other.DeprecationMessage {
+		merged.DeprecationMessage = ""
+	}
+	return &merged
+}
+
+// mergeEnum joins the possible values, a parameter without possible values allows any value
+func mergeEnum(enum, other []interface{}) []interface{} {
+	if len(enum) == 0 || len(other) == 0 {
+		return nil
+	}
+	merged := append([]interface{}{}, enum...)
+	for _, value := range other {
+		found := false
+		for _, existing := range merged {
+			if reflect.DeepEqual(existing, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
+
+func deprecatedSchema(schema *jsonSchema, replacement string) *jsonSchema {
+	deprecated := *schema
+	deprecated.DeprecationMessage = fmt.Sprintf("Deprecated, please use '%v' instead", replacement)
+	return &deprecated
+}
@@ -0,0 +1,195 @@
+package helper
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func schemaOpenFileMock(name string) (io.ReadCloser, error) {
+	meta := `metadata:
+  name: secondStep
+  description: Second description
+spec:
+  inputs:
+    params:
+      - name: param0
+        type: string
+        description: param0 description of second step
+        scope:
+        - GENERAL
+      - name: list
+        type: "[]string"
+        description: list description
+        default:
+        - a
+        - b
+        scope:
+        - STAGES
+        - STEPS
+      - name: mode
+        type: string
+        description: mode description
+        possibleValues:
+        - fast
+        - slow
+        scope:
+        - STEPS
+`
+	if name == "test.yaml" {
+		return configOpenFileMock(name)
+	}
+	return ioutil.NopCloser(strings.NewReader(meta)), nil
+}
+
+func TestProcessConfigSchema(t *testing.T) {
+	var written []byte
+	var writtenFile string
+	stepHelperData := StepHelperData{schemaOpenFileMock, func(filename string, data []byte, perm os.FileMode) error {
+		writtenFile = filename
+		written = data
+		return nil
+	}, ""}
+
+	err := ProcessConfigSchema([]string{"test.yaml", "second.yaml"}, stepHelperData, "schemas/config.json")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "schemas/config.json", writtenFile)
+
+	var schema map[string]interface{}
+	if assert.NoError(t, json.Unmarshal(written, &schema)) {
+		assert.Equal(t, "http://json-schema.org/draft-07/schema#", schema["$schema"])
+		properties := schema["properties"].(map[string]interface{})
+
+		t.Run("general", func(t *testing.T) {
+			general := properties["general"].(map[string]interface{})["properties"].(map[string]interface{})
+			assert.Equal(t, map[string]interface{}{"description": "param0 description of second step", "type": "string"}, general["param0"])
+			assert.Nil(t, general["param1"])
+		})
+
+		t.Run("stages", func(t *testing.T) {
+			stage := properties["stages"].(map[string]interface{})["additionalProperties"].(map[string]interface{})["properties"].(map[string]interface{})
+			assert.Equal(t, map[string]interface{}{
+				"description": "list description",
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"default":     []interface{}{"a", "b"},
+			}, stage["list"])
+		})
+
+		t.Run("steps", func(t *testing.T) {
+			steps := properties["steps"].(map[string]interface{})["properties"].(map[string]interface{})
+			second := steps["secondStep"].(map[string]interface{})
+			assert.Equal(t, "Second description", second["description"])
+			secondParams := second["properties"].(map[string]interface{})
+			assert.Contains(t, secondParams, "list")
+			assert.NotContains(t, secondParams, "param0")
+			assert.Equal(t, []interface{}{"fast", "slow"}, secondParams["mode"].(map[string]interface{})["enum"])
+
+			assert.Contains(t, steps, "testStep")
+			alias := steps["testStepAlias"].(map[string]interface{})
+			assert.Equal(t, "Deprecated, please use 'testStep' instead", alias["deprecationMessage"])
+		})
+	}
+}
+
+func TestAddParameter(t *testing.T) {
+	param := config.StepParameters{
+		Name:    "m2Path",
+		Type:    "string",
+		Aliases: []config.Alias{{Name: "maven/m2Path"}, {Name: "localRepository", Deprecated: true}},
+	}
+	paramSchema, err := parameterSchema(param)
+	assert.NoError(t, err)
+
+	parent := objectSchema("")
+	addParameter(parent, param, paramSchema)
+
+	assert.Equal(t, paramSchema, parent.Properties["m2Path"])
+	assert.Equal(t, paramSchema, parent.Properties["maven"].Properties["m2Path"])
+	assert.Equal(t, "Deprecated, please use 'm2Path' instead", parent.Properties["localRepository"].DeprecationMessage)
+	assert.Equal(t, "", paramSchema.DeprecationMessage)
+}
+
+func TestSharedParameters(t *testing.T) {
+	declarations := []config.StepParameters{
+		{Name: "deployTool", Type: "string", Default: "cf_native", PossibleValues: []interface{}{"cf_native", "mtaDeployPlugin"}},
+		{Name: "deployTool", Type: "string", Default: "kubectl", PossibleValues: []interface{}{"kubectl", "helm", "cf_native"}},
+		{Name: "links", Type: "[]string", Aliases: []config.Alias{{Name: "tls/links"}}},
+		{Name: "links", Type: "string"},
+		{Name: "mode", Type: "string", Default: "fast", PossibleValues: []interface{}{"fast"}},
+		{Name: "mode", Type: "string", Default: "fast"},
+		{Name: "tls", Type: "string"},
+	}
+
+	shared := sharedParameters{}
+	for _, param := range declarations {
+		paramSchema, err := parameterSchema(param)
+		assert.NoError(t, err)
+		shared.add(param, paramSchema)
+	}
+	parent := objectSchema("")
+	shared.addTo(parent)
+
+	t.Run("possible values are joined", func(t *testing.T) {
+		assert.Equal(t, "string", parent.Properties["deployTool"].Type)
+		assert.Equal(t, []interface{}{"cf_native", "mtaDeployPlugin", "kubectl", "helm"}, parent.Properties["deployTool"].Enum)
+		assert.Nil(t, parent.Properties["deployTool"].Default)
+	})
+
+	t.Run("type is left out if declarations differ", func(t *testing.T) {
+		assert.Equal(t, "", parent.Properties["links"].Type)
+		assert.Nil(t, parent.Properties["links"].Items)
+	})
+
+	t.Run("any value is allowed if one declaration has no possible values", func(t *testing.T) {
+		assert.Nil(t, parent.Properties["mode"].Enum)
+		assert.Equal(t, "fast", parent.Properties["mode"].Default)
+	})
+
+	t.Run("plain parameters take precedence over nested aliases", func(t *testing.T) {
+		assert.Equal(t, "string", parent.Properties["tls"].Type)
+		assert.Nil(t, parent.Properties["tls"].Properties)
+	})
+}
+
+func TestParameterSchema(t *testing.T) {
+	tt := []struct {
+		paramType    string
+		expectedType string
+	}{
+		{paramType: "string", expectedType: "string"},
+		{paramType: "bool", expectedType: "boolean"},
+		{paramType: "int", expectedType: "integer"},
+		{paramType: "[]string", expectedType: "array"},
+		{paramType: "float64", expectedType: "number"},
+		{paramType: "[]int", expectedType: "array"},
+		{paramType: "map[string]interface{}", expectedType: "object"},
+	}
+
+	for _, test := range tt {
+		schema, err := parameterSchema(config.StepParameters{Name: "param", Type: test.paramType})
+		assert.NoError(t, err)
+		assert.Equal(t, test.expectedType, schema.Type)
+	}
+
+	t.Run("possible values", func(t *testing.T) {
+		schema, err := parameterSchema(config.StepParameters{Name: "param", Type: "string", PossibleValues: []interface{}{"a", "b"}})
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{"a", "b"}, schema.Enum)
+
+		schema, err = parameterSchema(config.StepParameters{Name: "param", Type: "[]string", PossibleValues: []interface{}{"a", "b"}})
+		assert.NoError(t, err)
+		assert.Nil(t, schema.Enum)
+		assert.Equal(t, []interface{}{"a", "b"}, schema.Items.Enum)
+	})
+
+	_, err := parameterSchema(config.StepParameters{Name: "param", Type: "unknown"})
+	assert.EqualError(t, err, "parameter param: type 'unknown' not supported in config schema")
+}
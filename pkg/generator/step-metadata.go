@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 
 	"github.com/SAP/jenkins-library/pkg/generator/helper"
 )
@@ -14,9 +15,15 @@ import (
 func main() {
 	var docTemplatePath string
 	var isGenerateDocu bool
+	var schemaFile string
+	var actionsDir string
+	var azureTasksDir string
 
 	flag.StringVar(&docTemplatePath, "docuDir", "./documentation/docs/steps/", "The directory containing the docu stubs. Default points to \\'documentation/docs/steps.\\'")
 	flag.BoolVar(&isGenerateDocu, "docuGen", false, "Boolean to generate Documentation or Step-MetaData. Default is false")
+	flag.StringVar(&schemaFile, "schemaFile", "./resources/schemas/config.json", "The file the JSON Schema of the project configuration is written to")
+	flag.StringVar(&actionsDir, "actionsDir", "./actions", "The directory the GitHub actions of the steps are written to")
+	flag.StringVar(&azureTasksDir, "azureTasksDir", "./azure", "The directory the Azure DevOps tasks of the steps are written to")
 	flag.Parse()
 
 	fmt.Printf("docuDir: %v, genDocu: %v \n", docTemplatePath, isGenerateDocu)
@@ -30,6 +37,17 @@ func main() {
 	err = helper.ProcessMetaFiles(metadataFiles, stepHelperData, docuHelperData)
 	checkError(err)
 
+	if !isGenerateDocu {
+		err = helper.ProcessConfigSchema(metadataFiles, stepHelperData, schemaFile)
+		checkError(err)
+
+		err = helper.ProcessGitHubActions(metadataFiles, stepHelperData, actionsDir)
+		checkError(err)
+
+		err = helper.ProcessAzureTasks(metadataFiles, stepHelperData, azureTasksDir)
+		checkError(err)
+	}
+
 	cmd := exec.Command("go", "fmt", "./cmd")
 	err = cmd.Run()
 	checkError(err)
@@ -40,6 +58,9 @@ func openMetaFile(name string) (io.ReadCloser, error) {
 }
 
 func fileWriter(filename string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
 	return ioutil.WriteFile(filename, data, perm)
 }
 
@@ -0,0 +1,108 @@
+package gradle
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// ExecuteOptions are used by Execute() to construct the Gradle command line.
+type ExecuteOptions struct {
+	ProjectDir      string   `json:"projectDir,omitempty"`
+	InitScriptFiles []string `json:"initScriptFiles,omitempty"`
+	Tasks           []string `json:"tasks,omitempty"`
+	Flags           []string `json:"flags,omitempty"`
+}
+
+type gradleExecRunner interface {
+	Stdout(out io.Writer)
+	Stderr(err io.Writer)
+	RunExecutable(e string, p ...string) error
+}
+
+const gradleExecutable = "gradle"
+const gradleWrapper = "gradlew"
+
+// Execute constructs a gradle command line from the given options, and uses the provided
+// gradleExecRunner to execute it. The Gradle wrapper of the project is preferred over
+// the gradle executable of the execution environment.
+func Execute(options *ExecuteOptions, command gradleExecRunner, fileUtils piperutils.FileUtils) error {
+	command.Stdout(log.Entry().Writer())
+	command.Stderr(log.Entry().Writer())
+
+	executable, err := getExecutable(options.ProjectDir, fileUtils)
+	if err != nil {
+		return err
+	}
+
+	parameters, err := getParametersFromOptions(options, &http.Client{})
+	if err != nil {
+		return fmt.Errorf("failed to construct parameters from options: %w", err)
+	}
+
+	err = command.RunExecutable(executable, parameters...)
+	if err != nil {
+		commandLine := append([]string{executable}, parameters...)
+		return fmt.Errorf("failed to run executable, command: '%s', error: %w", commandLine, err)
+	}
+	return nil
+}
+
+func getExecutable(projectDir string, fileUtils piperutils.FileUtils) (string, error) {
+	wrapper := filepath.Join(projectDir, gradleWrapper)
+	exists, err := fileUtils.FileExists(wrapper)
+	if err != nil {
+		return "", fmt.Errorf("failed to check for Gradle wrapper '%s': %w", wrapper, err)
+	}
+	if !exists {
+		return gradleExecutable, nil
+	}
+	log.Entry().Infof("Using Gradle wrapper '%s'", wrapper)
+	if filepath.IsAbs(wrapper) {
+		return wrapper, nil
+	}
+	// a relative wrapper has to be addressed relative to the working directory, otherwise it is looked up in the PATH
+	return "." + string(filepath.Separator) + wrapper, nil
+}
+
+func getParametersFromOptions(options *ExecuteOptions, client http.Downloader) ([]string, error) {
+	var parameters []string
+
+	for i, initScriptFile := range options.InitScriptFiles {
+		initScriptFileName, err := downloadInitScriptIfURL(initScriptFile, fmt.Sprintf("initScript%d.gradle", i), client)
+		if err != nil {
+			return nil, err
+		}
+		parameters = append(parameters, "--init-script", initScriptFileName)
+	}
+
+	if options.ProjectDir != "" && options.ProjectDir != "." {
+		parameters = append(parameters, "--project-dir", options.ProjectDir)
+	}
+
+	if options.Flags != nil {
+		parameters = append(parameters, options.Flags...)
+	}
+
+	parameters = append(parameters, "--console=plain")
+
+	parameters = append(parameters, options.Tasks...)
+	return parameters, nil
+}
+
+func downloadInitScriptIfURL(initScriptOption, initScriptFile string, client http.Downloader) (string, error) {
+	if !strings.HasPrefix(initScriptOption, "http:") && !strings.HasPrefix(initScriptOption, "https:") {
+		return initScriptOption, nil
+	}
+	err := client.DownloadFile(initScriptOption, initScriptFile, nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to download Gradle init script from URL '%s' to file '%s': %w",
+			initScriptOption, initScriptFile, err)
+	}
+	return initScriptFile, nil
+}
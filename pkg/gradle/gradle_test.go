@@ -0,0 +1,143 @@
+package gradle
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockDownloader struct {
+	shouldFail     bool
+	requestedUrls  []string
+	requestedFiles []string
+}
+
+func (m *mockDownloader) DownloadFile(url, filename string, header http.Header, cookies []*http.Cookie) error {
+	m.requestedUrls = append(m.requestedUrls, url)
+	m.requestedFiles = append(m.requestedFiles, filename)
+	if m.shouldFail {
+		return errors.New("something happened")
+	}
+	return nil
+}
+
+func (m *mockDownloader) SetOptions(options piperHttp.ClientOptions) {
+	return
+}
+
+type mockFileUtils struct {
+	mock.FilesMock
+	written map[string][]byte
+}
+
+func (f *mockFileUtils) FileWrite(path string, content []byte, perm os.FileMode) error {
+	if f.written == nil {
+		f.written = map[string][]byte{}
+	}
+	f.written[path] = content
+	return nil
+}
+
+func TestExecute(t *testing.T) {
+	t.Run("should use gradle executable", func(t *testing.T) {
+		execMockRunner := mock.ExecMockRunner{}
+		opts := ExecuteOptions{Tasks: []string{"build"}}
+
+		err := Execute(&opts, &execMockRunner, &mockFileUtils{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{{Exec: "gradle", Params: []string{"--console=plain", "build"}}}, execMockRunner.Calls)
+	})
+	t.Run("should use gradle wrapper of the project", func(t *testing.T) {
+		execMockRunner := mock.ExecMockRunner{}
+		fileUtils := mockFileUtils{FilesMock: mock.FilesMock{Files: []string{filepath.Join("service", "gradlew")}}}
+		opts := ExecuteOptions{ProjectDir: "service", Tasks: []string{"clean", "build"}}
+
+		err := Execute(&opts, &execMockRunner, &fileUtils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{{Exec: "./service/gradlew", Params: []string{"--project-dir", "service", "--console=plain", "clean", "build"}}}, execMockRunner.Calls)
+	})
+	t.Run("should use gradle wrapper in the working directory", func(t *testing.T) {
+		execMockRunner := mock.ExecMockRunner{}
+		fileUtils := mockFileUtils{FilesMock: mock.FilesMock{Files: []string{"gradlew"}}}
+		opts := ExecuteOptions{ProjectDir: ".", Tasks: []string{"build"}}
+
+		err := Execute(&opts, &execMockRunner, &fileUtils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{{Exec: "./gradlew", Params: []string{"--console=plain", "build"}}}, execMockRunner.Calls)
+	})
+	t.Run("should use gradle wrapper of an absolute project directory", func(t *testing.T) {
+		execMockRunner := mock.ExecMockRunner{}
+		fileUtils := mockFileUtils{FilesMock: mock.FilesMock{Files: []string{"/workspace/service/gradlew"}}}
+		opts := ExecuteOptions{ProjectDir: "/workspace/service", Tasks: []string{"build"}}
+
+		err := Execute(&opts, &execMockRunner, &fileUtils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{{Exec: "/workspace/service/gradlew", Params: []string{"--project-dir", "/workspace/service", "--console=plain", "build"}}}, execMockRunner.Calls)
+	})
+	t.Run("should return error if executing gradle failed", func(t *testing.T) {
+		execMockRunner := mock.ExecMockRunner{ShouldFailOnCommand: map[string]error{"gradle --console=plain build": errors.New("error case")}}
+		opts := ExecuteOptions{Tasks: []string{"build"}}
+
+		err := Execute(&opts, &execMockRunner, &mockFileUtils{})
+
+		assert.EqualError(t, err, "failed to run executable, command: '[gradle --console=plain build]', error: error case")
+	})
+}
+
+func TestGetParameters(t *testing.T) {
+	t.Run("should resolve init scripts", func(t *testing.T) {
+		client := mockDownloader{}
+		opts := ExecuteOptions{
+			InitScriptFiles: []string{"init.gradle", "https://example.org/init.gradle"},
+			Flags:           []string{"--no-daemon"},
+			Tasks:           []string{"build"},
+		}
+
+		parameters, err := getParametersFromOptions(&opts, &client)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"--init-script", "init.gradle", "--init-script", "initScript1.gradle", "--no-daemon", "--console=plain", "build"}, parameters)
+		assert.Equal(t, []string{"https://example.org/init.gradle"}, client.requestedUrls)
+		assert.Equal(t, []string{"initScript1.gradle"}, client.requestedFiles)
+	})
+	t.Run("should return error if download of init script failed", func(t *testing.T) {
+		client := mockDownloader{shouldFail: true}
+		opts := ExecuteOptions{InitScriptFiles: []string{"https://example.org/init.gradle"}}
+
+		_, err := getParametersFromOptions(&opts, &client)
+
+		assert.EqualError(t, err, "failed to download Gradle init script from URL 'https://example.org/init.gradle' to file 'initScript0.gradle': something happened")
+	})
+}
+
+func TestRepositoryInitScript(t *testing.T) {
+	t.Run("should write init script", func(t *testing.T) {
+		fileUtils := mockFileUtils{}
+
+		err := WriteRepositoryInitScript("repository.gradle", &fileUtils)
+
+		assert.NoError(t, err)
+		content := string(fileUtils.written["repository.gradle"])
+		assert.Contains(t, content, "System.getenv('PIPER_GRADLE_REPOSITORY_URL')")
+		assert.Contains(t, content, "piperRepository(settings.pluginManagement.repositories)")
+		assert.NotContains(t, content, "%!", "format verbs must be resolved")
+	})
+	t.Run("should provide environment", func(t *testing.T) {
+		assert.Equal(t, []string{"PIPER_GRADLE_REPOSITORY_URL=https://repo.example.org"}, RepositoryEnvironment("https://repo.example.org", "", ""))
+		assert.Equal(t, []string{
+			"PIPER_GRADLE_REPOSITORY_URL=https://repo.example.org",
+			"PIPER_GRADLE_REPOSITORY_USERNAME=user",
+			"PIPER_GRADLE_REPOSITORY_PASSWORD=secret",
+		}, RepositoryEnvironment("https://repo.example.org", "user", "secret"))
+	})
+}
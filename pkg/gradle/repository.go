@@ -0,0 +1,63 @@
+package gradle
+
+import (
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// Environment variables which provide the repository to the init script written by WriteRepositoryInitScript.
+// The credentials are passed via the environment so that they are not persisted in the workspace.
+const (
+	RepositoryURLEnv      = "PIPER_GRADLE_REPOSITORY_URL"
+	RepositoryUsernameEnv = "PIPER_GRADLE_REPOSITORY_USERNAME"
+	RepositoryPasswordEnv = "PIPER_GRADLE_REPOSITORY_PASSWORD"
+)
+
+// repositoryInitScript adds the repository to the plugin management, the build scripts and the dependency resolution of all projects
+const repositoryInitScript = `// Generated by project "Piper", adds the configured repository to all projects of the build.
+def piperRepositoryUrl = System.getenv('%[1]s')
+def piperRepositoryUsername = System.getenv('%[2]s')
+def piperRepositoryPassword = System.getenv('%[3]s')
+
+def piperRepository = { handler ->
+    handler.maven {
+        name = 'piper'
+        url = piperRepositoryUrl
+        if (piperRepositoryUsername) {
+            credentials {
+                username = piperRepositoryUsername
+                password = piperRepositoryPassword
+            }
+        }
+    }
+}
+
+settingsEvaluated { settings ->
+    piperRepository(settings.pluginManagement.repositories)
+}
+
+allprojects {
+    piperRepository(buildscript.repositories)
+    piperRepository(repositories)
+}
+`
+
+// WriteRepositoryInitScript writes the Gradle init script which adds the repository provided via
+// RepositoryURLEnv, RepositoryUsernameEnv and RepositoryPasswordEnv to all projects.
+func WriteRepositoryInitScript(path string, fileUtils piperutils.FileUtils) error {
+	content := fmt.Sprintf(repositoryInitScript, RepositoryURLEnv, RepositoryUsernameEnv, RepositoryPasswordEnv)
+	if err := fileUtils.FileWrite(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write Gradle init script '%s': %w", path, err)
+	}
+	return nil
+}
+
+// RepositoryEnvironment returns the environment variables for the init script written by WriteRepositoryInitScript
+func RepositoryEnvironment(url, username, password string) []string {
+	env := []string{RepositoryURLEnv + "=" + url}
+	if len(username) > 0 {
+		env = append(env, RepositoryUsernameEnv+"="+username, RepositoryPasswordEnv+"="+password)
+	}
+	return env
+}
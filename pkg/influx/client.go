@@ -0,0 +1,106 @@
+package influx
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/pkg/errors"
+)
+
+const (
+	// APIVersion1 uses the write endpoint of InfluxDB 1.x with database and retention policy
+	APIVersion1 = "v1"
+	// APIVersion2 uses the write endpoint of InfluxDB 2.x with organization and bucket
+	APIVersion2 = "v2"
+)
+
+// Client writes data in line protocol to an InfluxDB
+type Client struct {
+	sender          piperhttp.Sender
+	serverURL       string
+	apiVersion      string
+	database        string
+	retentionPolicy string
+	organization    string
+	bucket          string
+}
+
+// ClientOptions defines the target of the data written by the Client
+type ClientOptions struct {
+	ServerURL  string
+	APIVersion string
+	// Database and RetentionPolicy are used for API version v1
+	Database        string
+	RetentionPolicy string
+	// Organization and Bucket are used for API version v2
+	Organization string
+	Bucket       string
+}
+
+// NewClient creates a client, the authentication is expected to be configured on the sender
+func NewClient(sender piperhttp.Sender, options ClientOptions) *Client {
+	return &Client{
+		sender:          sender,
+		serverURL:       strings.TrimSuffix(options.ServerURL, "/"),
+		apiVersion:      options.APIVersion,
+		database:        options.Database,
+		retentionPolicy: options.RetentionPolicy,
+		organization:    options.Organization,
+		bucket:          options.Bucket,
+	}
+}
+
+// WriteURL returns the url of the write endpoint depending on the API version
+func (c *Client) WriteURL() (string, error) {
+	query := url.Values{}
+	query.Set("precision", "ns")
+
+	switch c.apiVersion {
+	case APIVersion1, "":
+		if len(c.database) == 0 {
+			return "", fmt.Errorf("database is required for InfluxDB API version %v", APIVersion1)
+		}
+		query.Set("db", c.database)
+		if len(c.retentionPolicy) > 0 {
+			query.Set("rp", c.retentionPolicy)
+		}
+		return fmt.Sprintf("%v/write?%v", c.serverURL, query.Encode()), nil
+	case APIVersion2:
+		if len(c.organization) == 0 || len(c.bucket) == 0 {
+			return "", fmt.Errorf("organization and bucket are required for InfluxDB API version %v", APIVersion2)
+		}
+		query.Set("org", c.organization)
+		query.Set("bucket", c.bucket)
+		return fmt.Sprintf("%v/api/v2/write?%v", c.serverURL, query.Encode()), nil
+	default:
+		return "", fmt.Errorf("InfluxDB API version '%v' not supported", c.apiVersion)
+	}
+}
+
+// Write sends the lines to the write endpoint of the InfluxDB
+func (c *Client) Write(lines string) error {
+	writeURL, err := c.WriteURL()
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	response, err := c.sender.SendRequest(http.MethodPost, writeURL, strings.NewReader(lines), header, nil)
+	if response != nil && response.Body != nil {
+		defer response.Body.Close()
+	}
+	if err != nil {
+		if response != nil && response.Body != nil {
+			if body, readErr := ioutil.ReadAll(response.Body); readErr == nil && len(body) > 0 {
+				return errors.Wrapf(err, "failed to write data to InfluxDB: %v", strings.TrimSpace(string(body)))
+			}
+		}
+		return errors.Wrap(err, "failed to write data to InfluxDB")
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package influx
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteURL(t *testing.T) {
+	tt := []struct {
+		options     ClientOptions
+		expected    string
+		expectedErr string
+	}{
+		{options: ClientOptions{ServerURL: "http://influx:8086/", Database: "jenkins"}, expected: "http://influx:8086/write?db=jenkins&precision=ns"},
+		{options: ClientOptions{ServerURL: "http://influx:8086", APIVersion: "v1", Database: "jenkins", RetentionPolicy: "weekly"}, expected: "http://influx:8086/write?db=jenkins&precision=ns&rp=weekly"},
+		{options: ClientOptions{ServerURL: "http://influx:8086", APIVersion: "v2", Organization: "my org", Bucket: "piper"}, expected: "http://influx:8086/api/v2/write?bucket=piper&org=my+org&precision=ns"},
+		{options: ClientOptions{APIVersion: "v1"}, expectedErr: "database is required for InfluxDB API version v1"},
+		{options: ClientOptions{APIVersion: "v2", Bucket: "piper"}, expectedErr: "organization and bucket are required for InfluxDB API version v2"},
+		{options: ClientOptions{APIVersion: "v3"}, expectedErr: "InfluxDB API version 'v3' not supported"},
+	}
+
+	for _, test := range tt {
+		writeURL, err := NewClient(&piperhttp.Client{}, test.options).WriteURL()
+		if len(test.expectedErr) > 0 {
+			assert.EqualError(t, err, test.expectedErr)
+		} else {
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, writeURL)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		var passedPath, passedBody, passedAuth string
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			passedPath = req.URL.Path
+			passedAuth = req.Header.Get("Authorization")
+			body, _ := ioutil.ReadAll(req.Body)
+			passedBody = string(body)
+			rw.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{Token: "Token myToken"})
+		client := NewClient(sender, ClientOptions{ServerURL: server.URL, APIVersion: APIVersion2, Organization: "org", Bucket: "bucket"})
+
+		err := client.Write("step_data count=1i 1\n")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "/api/v2/write", passedPath)
+		assert.Equal(t, "Token myToken", passedAuth)
+		assert.Equal(t, "step_data count=1i 1\n", passedBody)
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(`{"error":"unable to parse 'invalid'"}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(&piperhttp.Client{}, ClientOptions{ServerURL: server.URL, Database: "jenkins"})
+
+		err := client.Write("invalid")
+
+		assert.Contains(t, err.Error(), `failed to write data to InfluxDB: {"error":"unable to parse 'invalid'"}`)
+	})
+}
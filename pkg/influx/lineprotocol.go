@@ -0,0 +1,118 @@
+package influx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/pkg/errors"
+)
+
+// Measurement contains the tags and fields of an Influx measurement
+type Measurement struct {
+	Name   string
+	Tags   map[string]string
+	Fields map[string]string
+}
+
+// ReadMeasurements reads the measurements which steps persisted as influx resource below path.
+// The values are expected in the layout '<measurement>/fields/<name>' and '<measurement>/tags/<name>'.
+func ReadMeasurements(path, resourceName string) ([]Measurement, error) {
+	values, err := piperenv.ExportResource(path, resourceName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read influx data")
+	}
+
+	measurements := map[string]*Measurement{}
+	for key, value := range values {
+		parts := strings.Split(key, "/")
+		if len(parts) != 3 {
+			continue
+		}
+		measurement, ok := measurements[parts[0]]
+		if !ok {
+			measurement = &Measurement{Name: parts[0], Tags: map[string]string{}, Fields: map[string]string{}}
+			measurements[parts[0]] = measurement
+		}
+
+		switch parts[1] {
+		case config.InfluxField + "s":
+			measurement.Fields[parts[2]] = fmt.Sprint(value)
+		case config.InfluxTag + "s":
+			measurement.Tags[parts[2]] = fmt.Sprint(value)
+		}
+	}
+
+	result := []Measurement{}
+	for _, measurement := range measurements {
+		result = append(result, *measurement)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// LineProtocol renders the measurements in the InfluxDB line protocol.
+// Measurements without fields are skipped since the line protocol requires at least one field.
+func LineProtocol(measurements []Measurement, timestamp time.Time) string {
+	var lines []string
+	for _, measurement := range measurements {
+		if len(measurement.Fields) == 0 {
+			continue
+		}
+
+		line := escape(measurement.Name, ", ")
+		for _, name := range sortedKeys(measurement.Tags) {
+			if len(measurement.Tags[name]) == 0 {
+				continue
+			}
+			line += fmt.Sprintf(",%v=%v", escape(name, ",= "), escape(measurement.Tags[name], ",= "))
+		}
+
+		fields := []string{}
+		for _, name := range sortedKeys(measurement.Fields) {
+			fields = append(fields, fmt.Sprintf("%v=%v", escape(name, ",= "), fieldValue(measurement.Fields[name])))
+		}
+		line += fmt.Sprintf(" %v %v", strings.Join(fields, ","), timestamp.UnixNano())
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// fieldValue types the field value: integers, floats and booleans are written as such, everything else as string
+func fieldValue(value string) string {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return value + "i"
+	}
+	// hexadecimal notation, NaN and Inf are accepted by ParseFloat but not by InfluxDB
+	if f, err := strconv.ParseFloat(value, 64); err == nil && !strings.ContainsAny(value, "xXnN") {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	if value == "true" || value == "false" {
+		return value
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func escape(value, characters string) string {
+	for _, c := range characters {
+		value = strings.ReplaceAll(value, string(c), `\`+string(c))
+	}
+	return value
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,66 @@
+package influx
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadMeasurements(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal("Failed to create temporary directory")
+	}
+
+	// clean up tmp dir
+	defer os.RemoveAll(dir)
+
+	piperenv.SetResourceParameter(dir, "influx", "checkmarx_data/fields/high_issues", "3")
+	piperenv.SetResourceParameter(dir, "influx", "checkmarx_data/tags/project", "myProject")
+	piperenv.SetResourceParameter(dir, "influx", "protecode_data/fields/vulnerabilities", "0")
+	piperenv.SetResourceParameter(dir, "influx", "unexpected", "ignored")
+
+	measurements, err := ReadMeasurements(dir, "influx")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Measurement{
+		{Name: "checkmarx_data", Tags: map[string]string{"project": "myProject"}, Fields: map[string]string{"high_issues": "3"}},
+		{Name: "protecode_data", Tags: map[string]string{}, Fields: map[string]string{"vulnerabilities": "0"}},
+	}, measurements)
+}
+
+func TestLineProtocol(t *testing.T) {
+	timestamp := time.Unix(1586000000, 0)
+
+	t.Run("typed fields", func(t *testing.T) {
+		measurements := []Measurement{
+			{
+				Name:   "step_data",
+				Tags:   map[string]string{"stage": "Central Build", "empty": ""},
+				Fields: map[string]string{"count": "3", "ratio": "0.5", "success": "true", "message": `say "hi"`, "hex": "0x10", "notANumber": "NaN"},
+			},
+		}
+
+		assert.Equal(t, `step_data,stage=Central\ Build count=3i,hex="0x10",message="say \"hi\"",notANumber="NaN",ratio=0.5,success=true 1586000000000000000`+"\n", LineProtocol(measurements, timestamp))
+	})
+
+	t.Run("escaping", func(t *testing.T) {
+		measurements := []Measurement{
+			{Name: "my data,1", Tags: map[string]string{"a=b": "c,d"}, Fields: map[string]string{"my field": "1.0"}},
+		}
+
+		assert.Equal(t, `my\ data\,1,a\=b=c\,d my\ field=1 1586000000000000000`+"\n", LineProtocol(measurements, timestamp))
+	})
+
+	t.Run("measurement without fields", func(t *testing.T) {
+		measurements := []Measurement{
+			{Name: "tags_only", Tags: map[string]string{"tag": "value"}, Fields: map[string]string{}},
+		}
+
+		assert.Equal(t, "", LineProtocol(measurements, timestamp))
+	})
+}
@@ -0,0 +1,116 @@
+package log
+
+import (
+	"fmt"
+)
+
+// ErrorCategory defines the category of a step failure
+type ErrorCategory int
+
+const (
+	// ErrorUndefined is used for failures which have not been categorized
+	ErrorUndefined ErrorCategory = iota
+	// ErrorConfiguration is used for failures caused by invalid or missing step configuration
+	ErrorConfiguration
+	// ErrorInfrastructure is used for failures of systems the step interacts with, like servers or tools
+	ErrorInfrastructure
+	// ErrorCompliance is used for failures caused by not meeting a defined threshold, e.g. of a security scan
+	ErrorCompliance
+	// ErrorTest is used for failures caused by failing tests
+	ErrorTest
+)
+
+var errorCategoryNames = map[ErrorCategory]string{
+	ErrorUndefined:      "undefined",
+	ErrorConfiguration:  "configuration",
+	ErrorInfrastructure: "infrastructure",
+	ErrorCompliance:     "compliance",
+	ErrorTest:           "test",
+}
+
+// exit codes of the categories, the undefined category keeps the generic exit code 1
+var errorCategoryExitCodes = map[ErrorCategory]int{
+	ErrorUndefined:      1,
+	ErrorConfiguration:  2,
+	ErrorInfrastructure: 3,
+	ErrorCompliance:     4,
+	ErrorTest:           5,
+}
+
+func (e ErrorCategory) String() string {
+	if name, ok := errorCategoryNames[e]; ok {
+		return name
+	}
+	return errorCategoryNames[ErrorUndefined]
+}
+
+// ExitCode returns the process exit code used for failures of this category
+func (e ErrorCategory) ExitCode() int {
+	if code, ok := errorCategoryExitCodes[e]; ok {
+		return code
+	}
+	return errorCategoryExitCodes[ErrorUndefined]
+}
+
+// CategorizedError is an error which carries the category of a step failure
+type CategorizedError struct {
+	Category ErrorCategory
+	err      error
+}
+
+func (e *CategorizedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *CategorizedError) Unwrap() error {
+	return e.err
+}
+
+// Cause returns the wrapped error, it allows errors.Cause of github.com/pkg/errors to look through the categorization
+func (e *CategorizedError) Cause() error {
+	return e.err
+}
+
+// NewError creates a categorized error with the given message
+func NewError(category ErrorCategory, format string, args ...interface{}) error {
+	return &CategorizedError{Category: category, err: fmt.Errorf(format, args...)}
+}
+
+// WrapError assigns a category to an existing error
+func WrapError(category ErrorCategory, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CategorizedError{Category: category, err: err}
+}
+
+// ErrorCategoryOf returns the category of the outermost categorized error within the chain of wrapped errors
+func ErrorCategoryOf(err error) ErrorCategory {
+	for err != nil {
+		if categorized, ok := err.(*CategorizedError); ok {
+			return categorized.Category
+		}
+		switch wrapped := err.(type) {
+		case interface{ Unwrap() error }:
+			err = wrapped.Unwrap()
+		case interface{ Cause() error }:
+			err = wrapped.Cause()
+		default:
+			return ErrorUndefined
+		}
+	}
+	return ErrorUndefined
+}
+
+var errorCategory = ErrorUndefined
+
+// SetErrorCategory sets the category used for a fatal log entry which does not contain a categorized error
+func SetErrorCategory(category ErrorCategory) {
+	errorCategory = category
+}
+
+// GetErrorCategory returns the category of the step failure
+func GetErrorCategory() ErrorCategory {
+	return errorCategory
+}
@@ -0,0 +1,52 @@
+package log
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCategory(t *testing.T) {
+	tt := []struct {
+		category ErrorCategory
+		name     string
+		exitCode int
+	}{
+		{category: ErrorUndefined, name: "undefined", exitCode: 1},
+		{category: ErrorConfiguration, name: "configuration", exitCode: 2},
+		{category: ErrorInfrastructure, name: "infrastructure", exitCode: 3},
+		{category: ErrorCompliance, name: "compliance", exitCode: 4},
+		{category: ErrorTest, name: "test", exitCode: 5},
+		{category: ErrorCategory(99), name: "undefined", exitCode: 1},
+	}
+
+	for _, test := range tt {
+		assert.Equal(t, test.name, test.category.String())
+		assert.Equal(t, test.exitCode, test.category.ExitCode())
+	}
+}
+
+func TestErrorCategoryOf(t *testing.T) {
+	t.Run("categorized error", func(t *testing.T) {
+		err := NewError(ErrorConfiguration, "parameter %v missing", "test")
+		assert.EqualError(t, err, "parameter test missing")
+		assert.Equal(t, ErrorConfiguration, ErrorCategoryOf(err))
+	})
+
+	t.Run("wrapped categorized error", func(t *testing.T) {
+		err := errors.Wrap(WrapError(ErrorTest, fmt.Errorf("tests failed")), "step failed")
+		assert.EqualError(t, err, "step failed: tests failed")
+		assert.Equal(t, ErrorTest, ErrorCategoryOf(err))
+
+		err = fmt.Errorf("step failed: %w", WrapError(ErrorCompliance, fmt.Errorf("threshold exceeded")))
+		assert.Equal(t, ErrorCompliance, ErrorCategoryOf(err))
+	})
+
+	t.Run("uncategorized error", func(t *testing.T) {
+		assert.Equal(t, ErrorUndefined, ErrorCategoryOf(errors.Wrap(fmt.Errorf("failed"), "step failed")))
+		assert.Equal(t, ErrorUndefined, ErrorCategoryOf(nil))
+		assert.Nil(t, WrapError(ErrorTest, nil))
+	})
+}
@@ -0,0 +1,81 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrorDetails contains the details of a step failure which are written to '<stepName>_errorDetails.json'
+type ErrorDetails struct {
+	StepName string `json:"stepName"`
+	Message  string `json:"message"`
+	Error    string `json:"error,omitempty"`
+	Category string `json:"category"`
+	ExitCode int    `json:"exitCode"`
+	Time     string `json:"time"`
+}
+
+// FatalHook is a logrus hook which determines the error category of fatal log entries and persists the error details
+type FatalHook struct {
+	StepName string
+	Path     string
+}
+
+// Levels returns the log levels the hook is registered for
+func (f *FatalHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.FatalLevel}
+}
+
+// Fire persists the error details of a fatal log entry
+func (f *FatalHook) Fire(entry *logrus.Entry) error {
+	details := ErrorDetails{
+		StepName: f.StepName,
+		Message:  entry.Message,
+		Time:     entry.Time.Format(time.RFC3339),
+	}
+
+	if err, ok := entry.Data[logrus.ErrorKey].(error); ok {
+		details.Error = err.Error()
+		if category := ErrorCategoryOf(err); category != ErrorUndefined {
+			SetErrorCategory(category)
+		}
+	}
+	details.Category = GetErrorCategory().String()
+	details.ExitCode = GetErrorCategory().ExitCode()
+
+	content, err := json.Marshal(&details)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.Path, 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(f.Path, fmt.Sprintf("%v_errorDetails.json", f.StepName)), content, 0644)
+}
+
+var fatalHook *FatalHook
+
+// RegisterFatalHook registers the FatalHook for a step and makes fatal log entries exit with the exit code of the error category.
+// The hook is only added once, subsequent calls update the step name and the path of the registered hook.
+func RegisterFatalHook(stepName, path string) {
+	if fatalHook == nil {
+		fatalHook = &FatalHook{}
+		logrus.AddHook(fatalHook)
+	}
+	fatalHook.StepName = stepName
+	fatalHook.Path = path
+	logrus.StandardLogger().ExitFunc = exit
+}
+
+func exit(code int) {
+	if code == 1 {
+		code = GetErrorCategory().ExitCode()
+	}
+	os.Exit(code)
+}
@@ -0,0 +1,75 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFatalHook(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal("Failed to create temporary directory")
+	}
+	defer os.RemoveAll(dir)
+	defer SetErrorCategory(ErrorUndefined)
+
+	hook := FatalHook{StepName: "testStep", Path: dir}
+
+	t.Run("entry with categorized error", func(t *testing.T) {
+		SetErrorCategory(ErrorUndefined)
+		entry := logrus.WithError(NewError(ErrorInfrastructure, "server not reachable"))
+		entry.Message = "step execution failed"
+		entry.Time = time.Now()
+
+		err := hook.Fire(entry)
+
+		assert.NoError(t, err)
+		assert.Equal(t, ErrorInfrastructure, GetErrorCategory())
+		var details ErrorDetails
+		content, err := ioutil.ReadFile(filepath.Join(dir, "testStep_errorDetails.json"))
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(content, &details))
+		assert.Equal(t, "testStep", details.StepName)
+		assert.Equal(t, "step execution failed", details.Message)
+		assert.Equal(t, "server not reachable", details.Error)
+		assert.Equal(t, "infrastructure", details.Category)
+		assert.Equal(t, 3, details.ExitCode)
+	})
+
+	t.Run("entry with previously set category", func(t *testing.T) {
+		SetErrorCategory(ErrorCompliance)
+		entry := logrus.WithError(fmt.Errorf("threshold exceeded"))
+		entry.Message = "scan not compliant"
+
+		err := hook.Fire(entry)
+
+		assert.NoError(t, err)
+		var details ErrorDetails
+		content, _ := ioutil.ReadFile(filepath.Join(dir, "testStep_errorDetails.json"))
+		json.Unmarshal(content, &details)
+		assert.Equal(t, "compliance", details.Category)
+		assert.Equal(t, 4, details.ExitCode)
+	})
+
+	t.Run("levels", func(t *testing.T) {
+		assert.Equal(t, []logrus.Level{logrus.FatalLevel}, hook.Levels())
+	})
+}
+
+func TestRegisterFatalHook(t *testing.T) {
+	hooks := len(logrus.StandardLogger().Hooks[logrus.FatalLevel])
+
+	RegisterFatalHook("firstStep", ".")
+	RegisterFatalHook("secondStep", "reports")
+
+	assert.Len(t, logrus.StandardLogger().Hooks[logrus.FatalLevel], hooks+1)
+	assert.Equal(t, &FatalHook{StepName: "secondStep", Path: "reports"}, fatalHook)
+}
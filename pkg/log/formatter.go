@@ -0,0 +1,205 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// FormatText is the default logrus text format
+	FormatText = "text"
+	// FormatJSON writes one JSON document per log entry
+	FormatJSON = "json"
+	// FormatGitHubActions writes warnings and errors as GitHub Actions workflow commands
+	FormatGitHubActions = "github"
+	// FormatAzureDevOps writes warnings and errors as Azure DevOps logging commands
+	FormatAzureDevOps = "azure"
+)
+
+// fields which are part of every log entry and not repeated in the CI specific formats
+var commonFields = []string{"library", "stepName", logrus.ErrorKey}
+
+// fields denoting the source location of a warning or an error, e.g. log.Entry().WithField("file", "pom.xml"),
+// which are passed as properties of the CI specific commands instead of being part of the message
+var sourceLocationFields = []string{"file", "line", "col"}
+
+var format = FormatText
+
+// SetFormatter sets the format of the log output.
+// If no format is provided it is detected from the environment, i.e. GitHub Actions and Azure DevOps are recognized.
+func SetFormatter(logFormat string) {
+	if len(logFormat) == 0 {
+		logFormat = detectFormat()
+	}
+
+	switch logFormat {
+	case FormatJSON:
+		logrus.SetFormatter(&logrus.JSONFormatter{TimestampFormat: time.RFC3339Nano})
+	case FormatGitHubActions:
+		logrus.SetFormatter(&gitHubActionsFormatter{})
+	case FormatAzureDevOps:
+		logrus.SetFormatter(&azureDevOpsFormatter{})
+	default:
+		logFormat = FormatText
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	}
+	format = logFormat
+}
+
+func detectFormat() string {
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		return FormatGitHubActions
+	}
+	if strings.EqualFold(os.Getenv("TF_BUILD"), "true") {
+		return FormatAzureDevOps
+	}
+	return FormatText
+}
+
+// StartGroup starts a collapsible section of the log output, e.g. for a phase of the step execution.
+// Groups are only rendered for GitHub Actions and Azure DevOps and cannot be nested, the step execution is already one group.
+func StartGroup(name string) {
+	switch format {
+	case FormatGitHubActions:
+		writeCommand(fmt.Sprintf("::group::%v", escapeData(name)))
+	case FormatAzureDevOps:
+		writeCommand(fmt.Sprintf("##[group]%v", escapeAzureDevOps(name)))
+	}
+}
+
+// EndGroup ends the collapsible section started with StartGroup
+func EndGroup() {
+	switch format {
+	case FormatGitHubActions:
+		writeCommand("::endgroup::")
+	case FormatAzureDevOps:
+		writeCommand("##[endgroup]")
+	}
+}
+
+func writeCommand(command string) {
+	fmt.Fprintln(logrus.StandardLogger().Out, command)
+}
+
+// gitHubActionsFormatter renders log entries as GitHub Actions workflow commands,
+// see https://docs.github.com/en/actions/reference/workflow-commands-for-github-actions
+type gitHubActionsFormatter struct{}
+
+func (f *gitHubActionsFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	message := formatMessage(entry)
+
+	command := ""
+	switch entry.Level {
+	case logrus.DebugLevel, logrus.TraceLevel:
+		command = "debug"
+	case logrus.WarnLevel:
+		command = "warning"
+	case logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel:
+		command = "error"
+	}
+	if len(command) == 0 {
+		return []byte(message + "\n"), nil
+	}
+
+	properties := []string{}
+	if command != "debug" {
+		for _, field := range sourceLocationFields {
+			if value, ok := entry.Data[field]; ok {
+				properties = append(properties, fmt.Sprintf("%v=%v", field, escapeProperty(fmt.Sprint(value))))
+			}
+		}
+	}
+	if len(properties) > 0 {
+		command = fmt.Sprintf("%v %v", command, strings.Join(properties, ","))
+	}
+	return []byte(fmt.Sprintf("::%v::%v\n", command, escapeData(message))), nil
+}
+
+// azureDevOpsFormatter renders log entries as Azure DevOps logging commands,
+// see https://docs.microsoft.com/en-us/azure/devops/pipelines/scripts/logging-commands
+type azureDevOpsFormatter struct{}
+
+func (f *azureDevOpsFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	message := formatMessage(entry)
+
+	issueType := ""
+	switch entry.Level {
+	case logrus.DebugLevel, logrus.TraceLevel:
+		return []byte(fmt.Sprintf("##[debug]%v\n", escapeAzureDevOps(message))), nil
+	case logrus.WarnLevel:
+		issueType = "warning"
+	case logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel:
+		issueType = "error"
+	default:
+		return []byte(message + "\n"), nil
+	}
+
+	properties := fmt.Sprintf("type=%v;", issueType)
+	for _, p := range []struct{ name, field string }{{"sourcepath", "file"}, {"linenumber", "line"}, {"columnnumber", "col"}} {
+		if value, ok := entry.Data[p.field]; ok {
+			properties += fmt.Sprintf("%v=%v;", p.name, escapeAzureDevOpsProperty(fmt.Sprint(value)))
+		}
+	}
+	return []byte(fmt.Sprintf("##vso[task.logissue %v]%v\n", properties, escapeAzureDevOps(message))), nil
+}
+
+// formatMessage appends the error and all step specific fields to the message of the entry
+func formatMessage(entry *logrus.Entry) string {
+	var b bytes.Buffer
+	b.WriteString(entry.Message)
+
+	keys := []string{}
+	for key := range entry.Data {
+		if !contains(commonFields, key) && !contains(sourceLocationFields, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %v=%v", key, entry.Data[key])
+	}
+
+	if err, ok := entry.Data[logrus.ErrorKey]; ok {
+		fmt.Fprintf(&b, " error=%v", err)
+	}
+	return b.String()
+}
+
+func escapeData(value string) string {
+	value = strings.ReplaceAll(value, "%", "%25")
+	value = strings.ReplaceAll(value, "\r", "%0D")
+	return strings.ReplaceAll(value, "\n", "%0A")
+}
+
+func escapeProperty(value string) string {
+	value = escapeData(value)
+	value = strings.ReplaceAll(value, ":", "%3A")
+	return strings.ReplaceAll(value, ",", "%2C")
+}
+
+func escapeAzureDevOps(value string) string {
+	value = strings.ReplaceAll(value, "%", "%AZP25")
+	value = strings.ReplaceAll(value, "\r", "%0D")
+	return strings.ReplaceAll(value, "\n", "%0A")
+}
+
+func escapeAzureDevOpsProperty(value string) string {
+	value = escapeAzureDevOps(value)
+	value = strings.ReplaceAll(value, ";", "%3B")
+	return strings.ReplaceAll(value, "]", "%5D")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFormatter(t *testing.T) {
+	defer SetFormatter(FormatText)
+
+	t.Run("explicit format", func(t *testing.T) {
+		SetFormatter(FormatJSON)
+		assert.IsType(t, &logrus.JSONFormatter{}, logrus.StandardLogger().Formatter)
+		assert.Equal(t, FormatJSON, format)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		SetFormatter("unknown")
+		assert.IsType(t, &logrus.TextFormatter{}, logrus.StandardLogger().Formatter)
+		assert.Equal(t, FormatText, format)
+	})
+
+	t.Run("GitHub Actions detected", func(t *testing.T) {
+		os.Setenv("GITHUB_ACTIONS", "true")
+		defer os.Unsetenv("GITHUB_ACTIONS")
+		SetFormatter("")
+		assert.IsType(t, &gitHubActionsFormatter{}, logrus.StandardLogger().Formatter)
+	})
+
+	t.Run("Azure DevOps detected", func(t *testing.T) {
+		os.Setenv("TF_BUILD", "True")
+		defer os.Unsetenv("TF_BUILD")
+		SetFormatter("")
+		assert.IsType(t, &azureDevOpsFormatter{}, logrus.StandardLogger().Formatter)
+	})
+}
+
+func TestGitHubActionsFormatter(t *testing.T) {
+	formatter := gitHubActionsFormatter{}
+	tt := []struct {
+		level    logrus.Level
+		message  string
+		data     logrus.Fields
+		expected string
+	}{
+		{level: logrus.InfoLevel, message: "info", data: logrus.Fields{"stepName": "test"}, expected: "info\n"},
+		{level: logrus.DebugLevel, message: "debug", data: logrus.Fields{}, expected: "::debug::debug\n"},
+		{level: logrus.WarnLevel, message: "deprecated", data: logrus.Fields{"file": "pom.xml"}, expected: "::warning file=pom.xml::deprecated\n"},
+		{level: logrus.ErrorLevel, message: "invalid", data: logrus.Fields{"file": "src/a:b,c.yaml", "line": 12, "col": 3}, expected: "::error file=src/a%3Ab%2Cc.yaml,line=12,col=3::invalid\n"},
+		{level: logrus.ErrorLevel, message: "failed\n100%", data: logrus.Fields{"error": fmt.Errorf("timeout"), "url": "http://test"}, expected: "::error::failed%0A100%25 url=http://test error=timeout\n"},
+	}
+
+	for _, test := range tt {
+		result, err := formatter.Format(&logrus.Entry{Level: test.level, Message: test.message, Data: test.data})
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, string(result))
+	}
+}
+
+func TestAzureDevOpsFormatter(t *testing.T) {
+	formatter := azureDevOpsFormatter{}
+	tt := []struct {
+		level    logrus.Level
+		message  string
+		data     logrus.Fields
+		expected string
+	}{
+		{level: logrus.InfoLevel, message: "info", data: logrus.Fields{"stepName": "test"}, expected: "info\n"},
+		{level: logrus.DebugLevel, message: "debug", data: logrus.Fields{}, expected: "##[debug]debug\n"},
+		{level: logrus.WarnLevel, message: "deprecated", data: logrus.Fields{"file": "pom.xml"}, expected: "##vso[task.logissue type=warning;sourcepath=pom.xml;]deprecated\n"},
+		{level: logrus.ErrorLevel, message: "invalid", data: logrus.Fields{"file": "src/a;b].yaml", "line": 12, "col": 3}, expected: "##vso[task.logissue type=error;sourcepath=src/a%3Bb%5D.yaml;linenumber=12;columnnumber=3;]invalid\n"},
+		{level: logrus.FatalLevel, message: "failed; [100%]\n", data: logrus.Fields{}, expected: "##vso[task.logissue type=error;]failed; [100%AZP25]%0A\n"},
+	}
+
+	for _, test := range tt {
+		result, err := formatter.Format(&logrus.Entry{Level: test.level, Message: test.message, Data: test.data})
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, string(result))
+	}
+}
+
+func TestGroups(t *testing.T) {
+	out := logrus.StandardLogger().Out
+	defer logrus.SetOutput(out)
+	defer SetFormatter(FormatText)
+
+	tt := []struct {
+		format   string
+		expected string
+	}{
+		{format: FormatText, expected: ""},
+		{format: FormatGitHubActions, expected: "::group::Build\n::endgroup::\n"},
+		{format: FormatAzureDevOps, expected: "##[group]Build\n##[endgroup]\n"},
+	}
+
+	for _, test := range tt {
+		var buffer bytes.Buffer
+		logrus.SetOutput(&buffer)
+		SetFormatter(test.format)
+		StartGroup("Build")
+		EndGroup()
+		assert.Equal(t, test.expected, buffer.String(), test.format)
+	}
+}
@@ -4,7 +4,11 @@ package mock
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"sort"
+
+	"github.com/bmatcuk/doublestar"
 )
 
 // FilesMock ...
@@ -44,3 +48,74 @@ func (f FilesMock) FileWrite(path string, content []byte, perm os.FileMode) erro
 func (f FilesMock) MkdirAll(path string, perm os.FileMode) error {
 	return errors.New("Not implemented")
 }
+
+// FileSystemMock keeps the content of files in memory, it can be used to mock the file utilities of steps
+type FileSystemMock struct {
+	Files map[string][]byte
+}
+
+// NewFileSystemMock ...
+func NewFileSystemMock() FileSystemMock {
+	return FileSystemMock{Files: map[string][]byte{}}
+}
+
+// FileExists ...
+func (f *FileSystemMock) FileExists(path string) (bool, error) {
+	_, ok := f.Files[path]
+	return ok, nil
+}
+
+// Copy ...
+func (f *FileSystemMock) Copy(src, dst string) (int64, error) {
+	content, ok := f.Files[src]
+	if !ok {
+		return 0, fmt.Errorf("could not read '%s'", src)
+	}
+	f.Files[dst] = content
+	return int64(len(content)), nil
+}
+
+// FileRead ...
+func (f *FileSystemMock) FileRead(path string) ([]byte, error) {
+	content, ok := f.Files[path]
+	if !ok {
+		return nil, fmt.Errorf("could not read '%s'", path)
+	}
+	return content, nil
+}
+
+// FileWrite ...
+func (f *FileSystemMock) FileWrite(path string, content []byte, perm os.FileMode) error {
+	f.Files[path] = content
+	return nil
+}
+
+// FileRemove ...
+func (f *FileSystemMock) FileRemove(path string) error {
+	if _, ok := f.Files[path]; !ok {
+		return fmt.Errorf("could not remove '%s'", path)
+	}
+	delete(f.Files, path)
+	return nil
+}
+
+// MkdirAll ...
+func (f *FileSystemMock) MkdirAll(path string, perm os.FileMode) error {
+	return nil
+}
+
+// Glob returns the sorted paths of the files matching the doublestar pattern
+func (f *FileSystemMock) Glob(pattern string) ([]string, error) {
+	matches := []string{}
+	for path := range f.Files {
+		matched, err := doublestar.Match(pattern, path)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			matches = append(matches, path)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
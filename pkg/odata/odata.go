@@ -0,0 +1,253 @@
+package odata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const csrfTokenHeader = "X-Csrf-Token"
+
+// Client is a client for SAP OData v2 services.
+// It takes care of the CSRF token handshake and keeps the session cookies required for modifying requests.
+type Client struct {
+	sender    piperhttp.Sender
+	csrfToken string
+	cookies   []*http.Cookie
+}
+
+// Error contains the details of an OData error response
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    []string
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("OData request failed with status code %v", e.StatusCode)
+	if len(e.Code) > 0 {
+		msg = fmt.Sprintf("%v: %v", msg, e.Code)
+	}
+	if len(e.Message) > 0 {
+		msg = fmt.Sprintf("%v - %v", msg, e.Message)
+	}
+	if len(e.Details) > 0 {
+		msg = fmt.Sprintf("%v (%v)", msg, strings.Join(e.Details, "; "))
+	}
+	return msg
+}
+
+// NewClient creates an OData client which sends its requests via the given sender
+func NewClient(sender piperhttp.Sender) *Client {
+	return &Client{sender: sender}
+}
+
+// CSRFToken returns the CSRF token of the current session
+func (c *Client) CSRFToken() string {
+	return c.csrfToken
+}
+
+// FetchCSRFToken retrieves a new CSRF token and the session cookies from the service
+func (c *Client) FetchCSRFToken(url string) error {
+	header := c.header()
+	header.Set(csrfTokenHeader, "fetch")
+
+	response, err := c.sender.SendRequest(http.MethodHead, url, nil, header, c.cookies)
+	if err != nil {
+		return errors.Wrapf(handleError(response, err), "fetching CSRF token from %v failed", url)
+	}
+	defer closeBody(response)
+
+	c.csrfToken = response.Header.Get(csrfTokenHeader)
+	c.updateCookies(response)
+	if len(c.csrfToken) == 0 {
+		return fmt.Errorf("no CSRF token returned by %v", url)
+	}
+	log.Entry().WithField("url", url).Debug("Fetched CSRF token")
+	return nil
+}
+
+// Get reads the entity or entity set available at url and unmarshals the payload into result
+func (c *Client) Get(url string, result interface{}) error {
+	return c.Send(http.MethodGet, url, nil, result)
+}
+
+// Post creates an entity at url and unmarshals the returned payload into result
+func (c *Client) Post(url string, payload, result interface{}) error {
+	return c.Send(http.MethodPost, url, payload, result)
+}
+
+// Put replaces the entity available at url
+func (c *Client) Put(url string, payload interface{}) error {
+	return c.Send(http.MethodPut, url, payload, nil)
+}
+
+// Delete deletes the entity available at url
+func (c *Client) Delete(url string) error {
+	return c.Send(http.MethodDelete, url, nil, nil)
+}
+
+// Send sends a request with the JSON representation of payload as body.
+// For modifying requests a CSRF token is fetched if none is available yet and the token is refreshed once in case the service rejects it.
+// The returned payload is unwrapped from the OData v2 'd' envelope and unmarshalled into result, if result is not nil.
+func (c *Client) Send(method, url string, payload, result interface{}) error {
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return errors.Wrap(err, "failed to marshal request payload")
+		}
+	}
+
+	modifying := method != http.MethodGet && method != http.MethodHead
+	if modifying && len(c.csrfToken) == 0 {
+		if err := c.FetchCSRFToken(url); err != nil {
+			return err
+		}
+	}
+
+	response, err := c.send(method, url, body)
+	if modifying && csrfTokenRequired(response) {
+		log.Entry().WithField("url", url).Debug("CSRF token rejected, fetching a new one")
+		closeBody(response)
+		if err := c.FetchCSRFToken(url); err != nil {
+			return err
+		}
+		response, err = c.send(method, url, body)
+	}
+	if err != nil {
+		return errors.Wrapf(handleError(response, err), "%v request to %v failed", method, url)
+	}
+	defer closeBody(response)
+	c.updateCookies(response)
+
+	if result == nil {
+		return nil
+	}
+	content, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response body")
+	}
+	return Unmarshal(content, result)
+}
+
+// Unmarshal unwraps the payload of an OData v2 JSON response from its 'd' envelope and unmarshals it into v.
+// Entity sets are unwrapped from their 'results' property.
+func Unmarshal(data []byte, v interface{}) error {
+	var envelope struct {
+		D json.RawMessage `json:"d"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return errors.Wrap(err, "failed to parse OData response")
+	}
+	if len(envelope.D) == 0 {
+		return errors.New("OData response does not contain a 'd' property")
+	}
+
+	payload := envelope.D
+	var set map[string]json.RawMessage
+	if err := json.Unmarshal(envelope.D, &set); err == nil {
+		if results, ok := set["results"]; ok && bytes.HasPrefix(bytes.TrimSpace(results), []byte("[")) {
+			payload = results
+		}
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return errors.Wrap(err, "failed to unmarshal OData payload")
+	}
+	return nil
+}
+
+func (c *Client) send(method, url string, body []byte) (*http.Response, error) {
+	header := c.header()
+	header.Set(csrfTokenHeader, c.csrfToken)
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	return c.sender.SendRequest(method, url, reader, header, c.cookies)
+}
+
+func (c *Client) header() http.Header {
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+	header.Set("Content-Type", "application/json")
+	return header
+}
+
+func (c *Client) updateCookies(response *http.Response) {
+	for _, cookie := range response.Cookies() {
+		replaced := false
+		for i, existing := range c.cookies {
+			if existing.Name == cookie.Name {
+				c.cookies[i] = cookie
+				replaced = true
+			}
+		}
+		if !replaced {
+			c.cookies = append(c.cookies, cookie)
+		}
+	}
+}
+
+func csrfTokenRequired(response *http.Response) bool {
+	return response != nil && response.StatusCode == http.StatusForbidden && strings.EqualFold(response.Header.Get(csrfTokenHeader), "required")
+}
+
+// handleError extracts the OData error message from the response body, if available
+func handleError(response *http.Response, err error) error {
+	if response == nil || response.Body == nil {
+		return err
+	}
+	defer closeBody(response)
+
+	content, readErr := ioutil.ReadAll(response.Body)
+	if readErr != nil || len(content) == 0 {
+		return err
+	}
+
+	var errorResponse struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message struct {
+				Value string `json:"value"`
+			} `json:"message"`
+			InnerError struct {
+				ErrorDetails []struct {
+					Code    string `json:"code"`
+					Message string `json:"message"`
+				} `json:"errordetails"`
+			} `json:"innererror"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(content, &errorResponse) != nil || (len(errorResponse.Error.Code) == 0 && len(errorResponse.Error.Message.Value) == 0) {
+		return err
+	}
+
+	odataErr := &Error{
+		StatusCode: response.StatusCode,
+		Code:       errorResponse.Error.Code,
+		Message:    errorResponse.Error.Message.Value,
+	}
+	for _, detail := range errorResponse.Error.InnerError.ErrorDetails {
+		if len(detail.Message) > 0 && detail.Message != odataErr.Message {
+			odataErr.Details = append(odataErr.Details, detail.Message)
+		}
+	}
+	return odataErr
+}
+
+func closeBody(response *http.Response) {
+	if response != nil && response.Body != nil {
+		response.Body.Close()
+	}
+}
@@ -0,0 +1,179 @@
+package odata
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testEntity struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+func TestFetchCSRFToken(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		var passedMethod, passedToken string
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			passedMethod = req.Method
+			passedToken = req.Header.Get("X-Csrf-Token")
+			http.SetCookie(rw, &http.Cookie{Name: "session", Value: "abc"})
+			rw.Header().Set("X-Csrf-Token", "myToken")
+		}))
+		defer server.Close()
+
+		client := NewClient(&piperhttp.Client{})
+		err := client.FetchCSRFToken(server.URL)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.MethodHead, passedMethod)
+		assert.Equal(t, "fetch", passedToken)
+		assert.Equal(t, "myToken", client.CSRFToken())
+		if assert.Equal(t, 1, len(client.cookies)) {
+			assert.Equal(t, "session", client.cookies[0].Name)
+		}
+	})
+
+	t.Run("no token returned", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+		defer server.Close()
+
+		client := NewClient(&piperhttp.Client{})
+		err := client.FetchCSRFToken(server.URL)
+
+		assert.EqualError(t, err, "no CSRF token returned by "+server.URL)
+	})
+}
+
+func TestSend(t *testing.T) {
+	t.Run("post with token and cookies", func(t *testing.T) {
+		var passedToken, passedBody string
+		var passedCookies []*http.Cookie
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.Method == http.MethodHead {
+				http.SetCookie(rw, &http.Cookie{Name: "session", Value: "abc"})
+				rw.Header().Set("X-Csrf-Token", "myToken")
+				return
+			}
+			passedToken = req.Header.Get("X-Csrf-Token")
+			passedCookies = req.Cookies()
+			body, _ := ioutil.ReadAll(req.Body)
+			passedBody = string(body)
+			rw.Write([]byte(`{"d": {"name": "test", "status": "R"}}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(&piperhttp.Client{})
+		var entity testEntity
+		err := client.Post(server.URL, map[string]string{"name": "test"}, &entity)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "myToken", passedToken)
+		assert.Equal(t, `{"name":"test"}`, passedBody)
+		if assert.Equal(t, 1, len(passedCookies)) {
+			assert.Equal(t, "abc", passedCookies[0].Value)
+		}
+		assert.Equal(t, testEntity{Name: "test", Status: "R"}, entity)
+	})
+
+	t.Run("token refresh on 403", func(t *testing.T) {
+		fetchCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.Method == http.MethodHead {
+				fetchCount++
+				rw.Header().Set("X-Csrf-Token", "validToken")
+				return
+			}
+			if req.Header.Get("X-Csrf-Token") != "validToken" {
+				rw.Header().Set("X-Csrf-Token", "Required")
+				rw.WriteHeader(http.StatusForbidden)
+				return
+			}
+			rw.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		client := NewClient(&piperhttp.Client{})
+		client.csrfToken = "expiredToken"
+		err := client.Delete(server.URL)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, fetchCount)
+		assert.Equal(t, "validToken", client.CSRFToken())
+	})
+
+	t.Run("get does not fetch token", func(t *testing.T) {
+		fetchCount := 0
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.Method == http.MethodHead {
+				fetchCount++
+			}
+			rw.Write([]byte(`{"d": {"results": [{"name": "first"}, {"name": "second"}]}}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(&piperhttp.Client{})
+		var entities []testEntity
+		err := client.Get(server.URL, &entities)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, fetchCount)
+		assert.Equal(t, []testEntity{{Name: "first"}, {Name: "second"}}, entities)
+	})
+
+	t.Run("error message extraction", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(`{"error": {"code": "GIT/001", "message": {"lang": "en", "value": "Repository not found"}, "innererror": {"errordetails": [{"code": "GIT/002", "message": "Check the repository name"}]}}}`))
+		}))
+		defer server.Close()
+
+		client := NewClient(&piperhttp.Client{})
+		var entity testEntity
+		err := client.Get(server.URL, &entity)
+
+		if assert.Error(t, err) {
+			odataErr, ok := errors.Cause(err).(*Error)
+			if assert.True(t, ok, "expected OData error") {
+				assert.Equal(t, http.StatusBadRequest, odataErr.StatusCode)
+				assert.Equal(t, "GIT/001", odataErr.Code)
+				assert.Equal(t, "Repository not found", odataErr.Message)
+				assert.Equal(t, []string{"Check the repository name"}, odataErr.Details)
+			}
+			assert.Contains(t, err.Error(), "OData request failed with status code 400: GIT/001 - Repository not found (Check the repository name)")
+		}
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("single entity", func(t *testing.T) {
+		var entity testEntity
+		err := Unmarshal([]byte(`{"d": {"name": "test", "status": "S"}}`), &entity)
+		assert.NoError(t, err)
+		assert.Equal(t, testEntity{Name: "test", Status: "S"}, entity)
+	})
+
+	t.Run("entity with results property", func(t *testing.T) {
+		var entity map[string]interface{}
+		err := Unmarshal([]byte(`{"d": {"results": "done"}}`), &entity)
+		assert.NoError(t, err)
+		assert.Equal(t, "done", entity["results"])
+	})
+
+	t.Run("missing envelope", func(t *testing.T) {
+		var entity testEntity
+		err := Unmarshal([]byte(`{"name": "test"}`), &entity)
+		assert.EqualError(t, err, "OData response does not contain a 'd' property")
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		var entity testEntity
+		err := Unmarshal([]byte(`not json`), &entity)
+		assert.Contains(t, err.Error(), "failed to parse OData response")
+	})
+}
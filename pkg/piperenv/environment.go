@@ -1,21 +1,42 @@
 package piperenv
 
 import (
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
 )
 
 // This file contains functions used to read/write pipeline environment data from/to disk.
 // The content of a written file is the value. For the custom parameters this could for example also be a JSON representation of a more complex value.
+//
+// Next to the value file a hidden directory '.<name>.history' is maintained which contains
+// the previous versions of the value, the type of each version and the lock file used to serialize concurrent writers.
+
+// ValueType defines the type of a value stored in the environment
+type ValueType string
+
+const (
+	// TypeString marks a plain string value
+	TypeString ValueType = "string"
+	// TypeJSON marks a JSON object or array
+	TypeJSON ValueType = "json"
+)
+
+// maxVersions is the number of versions which are kept per value
+var maxVersions = 10
 
 // SetResourceParameter sets a resource parameter in the environment stored in the file system
 func SetResourceParameter(path, resourceName, paramName, value string) error {
 	paramPath := filepath.Join(path, resourceName, paramName)
-	return writeToDisk(paramPath, []byte(value))
+	return writeToDisk(paramPath, []byte(value), TypeString)
 }
 
 // GetResourceParameter reads a resource parameter from the environment stored in the file system
@@ -24,10 +45,28 @@ func GetResourceParameter(path, resourceName, paramName string) string {
 	return readFromDisk(paramPath)
 }
 
+// SetResourceParameterJSON stores the JSON representation of a value as resource parameter
+func SetResourceParameterJSON(path, resourceName, paramName string, value interface{}) error {
+	paramPath := filepath.Join(path, resourceName, paramName)
+	return writeJSONToDisk(paramPath, value)
+}
+
+// GetResourceParameterJSON reads a resource parameter containing JSON into value
+func GetResourceParameterJSON(path, resourceName, paramName string, value interface{}) error {
+	paramPath := filepath.Join(path, resourceName, paramName)
+	return readJSONFromDisk(paramPath, value)
+}
+
+// GetResourceParameterType returns the type of a resource parameter, values written without type information are treated as strings
+func GetResourceParameterType(path, resourceName, paramName string) ValueType {
+	paramPath := filepath.Join(path, resourceName, paramName)
+	return readTypeFromDisk(paramPath)
+}
+
 // SetParameter sets any parameter in the pipeline environment or another environment stored in the file system
 func SetParameter(path, name, value string) error {
 	paramPath := filepath.Join(path, name)
-	return writeToDisk(paramPath, []byte(value))
+	return writeToDisk(paramPath, []byte(value), TypeString)
 }
 
 // GetParameter reads any parameter from the pipeline environment or another environment stored in the file system
@@ -36,28 +75,177 @@ func GetParameter(path, name string) string {
 	return readFromDisk(paramPath)
 }
 
-func writeToDisk(filename string, data []byte) error {
+// SetParameterJSON stores the JSON representation of any parameter in the pipeline environment or another environment stored in the file system
+func SetParameterJSON(path, name string, value interface{}) error {
+	paramPath := filepath.Join(path, name)
+	return writeJSONToDisk(paramPath, value)
+}
+
+// GetParameterJSON reads any parameter containing JSON into value
+func GetParameterJSON(path, name string, value interface{}) error {
+	paramPath := filepath.Join(path, name)
+	return readJSONFromDisk(paramPath, value)
+}
+
+// GetParameterType returns the type of any parameter, values written without type information are treated as strings
+func GetParameterType(path, name string) ValueType {
+	paramPath := filepath.Join(path, name)
+	return readTypeFromDisk(paramPath)
+}
+
+func writeJSONToDisk(filename string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal value of %v", filename)
+	}
+	if !isJSONObjectOrArray(data) {
+		return fmt.Errorf("value of %v is neither a JSON object nor a JSON array", filename)
+	}
+	return writeToDisk(filename, data, TypeJSON)
+}
+
+func readJSONFromDisk(filename string, value interface{}) error {
+	data := readFromDisk(filename)
+	if len(data) == 0 {
+		return fmt.Errorf("no value available for %v", filename)
+	}
+	if err := json.Unmarshal([]byte(data), value); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal value of %v", filename)
+	}
+	return nil
+}
+
+func isJSONObjectOrArray(data []byte) bool {
+	trimmed := strings.TrimSpace(string(data))
+	return strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")
+}
+
+func historyDir(filename string) string {
+	return filepath.Join(filepath.Dir(filename), fmt.Sprintf(".%v.history", filepath.Base(filename)))
+}
+
+func writeToDisk(filename string, data []byte, valueType ValueType) error {
+	// empty values do not overwrite existing values
+	if len(data) == 0 {
+		return nil
+	}
+
+	history := historyDir(filename)
+	if _, err := os.Stat(history); os.IsNotExist(err) {
+		log.Entry().Debugf("Creating directory: %v", history)
+	}
+	if err := os.MkdirAll(history, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory %v", history)
+	}
+
+	unlock, err := lock(filepath.Join(history, "lock"))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	versions, err := listVersions(history)
+	if err != nil {
+		return err
+	}
+	next := 1
+	if len(versions) > 0 {
+		next = versions[len(versions)-1].number + 1
+	}
+
+	log.Entry().Debugf("Writing file to disk: %v", filename)
+	versionFile := filepath.Join(history, fmt.Sprintf("%010d.%v", next, valueType))
+	if err := writeAtomically(history, versionFile, data); err != nil {
+		return err
+	}
+	if err := writeAtomically(history, filename, data); err != nil {
+		return err
+	}
+
+	// remove versions exceeding the history limit, the list does not contain the version just written
+	for i := 0; i < len(versions)+1-maxVersions; i++ {
+		os.Remove(filepath.Join(history, versions[i].name))
+	}
+	return nil
+}
 
-	if _, err := os.Stat(filepath.Dir(filename)); os.IsNotExist(err) {
-		log.Entry().Debugf("Creating directory: %v", filepath.Dir(filename))
-		os.MkdirAll(filepath.Dir(filename), 0700)
+// writeAtomically writes data to a temporary file in tempDir and renames it to filename afterwards,
+// this way readers never see partially written values
+func writeAtomically(tempDir, filename string, data []byte) error {
+	tmp, err := ioutil.TempFile(tempDir, "tmp")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temporary file for %v", filename)
 	}
+	defer os.Remove(tmp.Name())
 
-	//ToDo: make sure to not overwrite file but rather add another file? Create error if already existing?
-	if len(data) > 0 {
-		log.Entry().Debugf("Writing file to disk: %v", filename)
-		return ioutil.WriteFile(filename, data, 0700)
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrapf(err, "failed to write %v", filename)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write %v", filename)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return errors.Wrapf(err, "failed to set permissions of %v", filename)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return errors.Wrapf(err, "failed to write %v", filename)
 	}
 	return nil
 }
 
 func readFromDisk(filename string) string {
-	//ToDo: if multiple files exist, read from latest file
 	log.Entry().Debugf("Reading file from disk: %v", filename)
 	v, err := ioutil.ReadFile(filename)
-	val := string(v)
 	if err != nil {
-		val = ""
+		return ""
+	}
+	return strings.TrimSpace(string(v))
+}
+
+func readTypeFromDisk(filename string) ValueType {
+	if latest, ok := latestVersion(filename); ok {
+		return latest.valueType
+	}
+	return TypeString
+}
+
+type version struct {
+	name      string
+	number    int
+	valueType ValueType
+}
+
+func latestVersion(filename string) (version, bool) {
+	versions, err := listVersions(historyDir(filename))
+	if err != nil || len(versions) == 0 {
+		return version{}, false
+	}
+	return versions[len(versions)-1], true
+}
+
+// listVersions returns the versions stored in the history directory ordered from oldest to latest
+func listVersions(history string) ([]version, error) {
+	files, err := ioutil.ReadDir(history)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []version{}, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read directory %v", history)
+	}
+
+	versions := []version{}
+	for _, file := range files {
+		parts := strings.SplitN(file.Name(), ".", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		number, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version{name: file.Name(), number: number, valueType: ValueType(parts[1])})
 	}
-	return strings.TrimSpace(val)
+	sort.Slice(versions, func(i, j int) bool { return versions[i].number < versions[j].number })
+	return versions, nil
 }
@@ -1,8 +1,11 @@
 package piperenv
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -49,3 +52,120 @@ func TestReadFromDisk(t *testing.T) {
 
 	assert.Equal(t, "", GetParameter(dir, "testParamNotExistingYet"))
 }
+
+func TestVersions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal("Failed to create temporary directory")
+	}
+
+	// clean up tmp dir
+	defer os.RemoveAll(dir)
+
+	t.Run("latest value wins", func(t *testing.T) {
+		assert.NoError(t, SetParameter(dir, "version", "1.0.0"))
+		assert.NoError(t, SetParameter(dir, "version", "1.0.1"))
+		assert.NoError(t, SetParameter(dir, "version", ""))
+
+		assert.Equal(t, "1.0.1", GetParameter(dir, "version"))
+		versions, err := listVersions(filepath.Join(dir, ".version.history"))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(versions))
+	})
+
+	t.Run("removed value stays removed", func(t *testing.T) {
+		assert.NoError(t, SetParameter(dir, "removed", "first"))
+		assert.NoError(t, SetParameter(dir, "removed", "second"))
+		assert.NoError(t, os.Remove(filepath.Join(dir, "removed")))
+
+		assert.Equal(t, "", GetParameter(dir, "removed"))
+	})
+
+	t.Run("history is limited", func(t *testing.T) {
+		for i := 0; i < maxVersions+5; i++ {
+			assert.NoError(t, SetParameter(dir, "limited", fmt.Sprint(i)))
+		}
+
+		versions, err := listVersions(filepath.Join(dir, ".limited.history"))
+		assert.NoError(t, err)
+		assert.Equal(t, maxVersions, len(versions))
+		assert.Equal(t, maxVersions+5, versions[len(versions)-1].number)
+		assert.Equal(t, fmt.Sprint(maxVersions+4), GetParameter(dir, "limited"))
+	})
+
+	t.Run("concurrent writers", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				assert.NoError(t, SetParameter(dir, "parallel", fmt.Sprintf("value%v", i)))
+			}(i)
+		}
+		wg.Wait()
+
+		latest, ok := latestVersion(filepath.Join(dir, "parallel"))
+		if assert.True(t, ok) {
+			assert.Equal(t, 20, latest.number)
+			content, err := ioutil.ReadFile(filepath.Join(dir, ".parallel.history", latest.name))
+			assert.NoError(t, err)
+			assert.Equal(t, string(content), GetParameter(dir, "parallel"))
+		}
+		_, err = os.Stat(filepath.Join(dir, ".parallel.history", "lock"))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestJSONParameter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal("Failed to create temporary directory")
+	}
+
+	// clean up tmp dir
+	defer os.RemoveAll(dir)
+
+	t.Run("object", func(t *testing.T) {
+		err := SetResourceParameterJSON(dir, "custom", "object", map[string]interface{}{"key": "value"})
+		assert.NoError(t, err)
+
+		var value map[string]interface{}
+		assert.NoError(t, GetResourceParameterJSON(dir, "custom", "object", &value))
+		assert.Equal(t, map[string]interface{}{"key": "value"}, value)
+		assert.Equal(t, TypeJSON, GetResourceParameterType(dir, "custom", "object"))
+		assert.Equal(t, `{"key":"value"}`, GetResourceParameter(dir, "custom", "object"))
+	})
+
+	t.Run("array", func(t *testing.T) {
+		assert.NoError(t, SetParameterJSON(dir, "array", []string{"a", "b"}))
+
+		var value []string
+		assert.NoError(t, GetParameterJSON(dir, "array", &value))
+		assert.Equal(t, []string{"a", "b"}, value)
+		assert.Equal(t, TypeJSON, GetParameterType(dir, "array"))
+	})
+
+	t.Run("type changes with the latest version", func(t *testing.T) {
+		assert.NoError(t, SetParameterJSON(dir, "changing", []string{"a"}))
+		assert.NoError(t, SetParameter(dir, "changing", "plain"))
+
+		assert.Equal(t, TypeString, GetParameterType(dir, "changing"))
+	})
+
+	t.Run("no object or array", func(t *testing.T) {
+		err := SetParameterJSON(dir, "number", 1)
+		assert.EqualError(t, err, fmt.Sprintf("value of %v is neither a JSON object nor a JSON array", filepath.Join(dir, "number")))
+	})
+
+	t.Run("value missing", func(t *testing.T) {
+		var value []string
+		err := GetParameterJSON(dir, "missing", &value)
+		assert.EqualError(t, err, fmt.Sprintf("no value available for %v", filepath.Join(dir, "missing")))
+	})
+
+	t.Run("untyped value", func(t *testing.T) {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "untyped"), []byte("value"), 0644))
+		assert.Equal(t, TypeString, GetParameterType(dir, "untyped"))
+		assert.Equal(t, "value", GetParameter(dir, "untyped"))
+	})
+}
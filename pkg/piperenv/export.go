@@ -0,0 +1,102 @@
+package piperenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ExportResource reads all values of a resource, e.g. the commonPipelineEnvironment, from the file system.
+// The keys of the result are the slash separated paths of the values relative to the resource,
+// values of type JSON are contained as json.RawMessage, all other values as string.
+func ExportResource(path, resourceName string) (map[string]interface{}, error) {
+	if !IsRelativePath(resourceName) {
+		return nil, fmt.Errorf("invalid resource name '%v'", resourceName)
+	}
+	values := map[string]interface{}{}
+	root := filepath.Join(path, resourceName)
+
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return values, nil
+	}
+
+	err := filepath.Walk(root, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// the history directories and other hidden files are not part of the environment
+		if strings.HasPrefix(info.Name(), ".") && filename != root {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		key, err := filepath.Rel(root, filename)
+		if err != nil {
+			return err
+		}
+		value := readFromDisk(filename)
+		if readTypeFromDisk(filename) == TypeJSON && json.Valid([]byte(value)) {
+			values[filepath.ToSlash(key)] = json.RawMessage(value)
+		} else {
+			values[filepath.ToSlash(key)] = value
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read resource %v", resourceName)
+	}
+	return values, nil
+}
+
+// ImportResource writes values of a resource to the file system, it is the counterpart of ExportResource.
+// Objects and arrays are stored as JSON values, all other values as string.
+func ImportResource(path, resourceName string, values map[string]interface{}) error {
+	if !IsRelativePath(resourceName) {
+		return fmt.Errorf("invalid resource name '%v'", resourceName)
+	}
+	for key, value := range values {
+		if !IsRelativePath(key) {
+			return fmt.Errorf("invalid key '%v' for resource %v", key, resourceName)
+		}
+		paramName := filepath.FromSlash(key)
+
+		var err error
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			err = SetResourceParameter(path, resourceName, paramName, v)
+		case map[string]interface{}, []interface{}, json.RawMessage:
+			err = SetResourceParameterJSON(path, resourceName, paramName, v)
+		default:
+			err = SetResourceParameter(path, resourceName, paramName, fmt.Sprint(v))
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to write '%v' of resource %v", key, resourceName)
+		}
+	}
+	return nil
+}
+
+// IsRelativePath returns true for non-empty paths which stay within their parent directory.
+// Paths with empty segments or the segments "." and ".." are rejected, thus each path denotes exactly one value.
+func IsRelativePath(name string) bool {
+	if len(name) == 0 || filepath.IsAbs(name) {
+		return false
+	}
+	for _, segment := range strings.Split(filepath.ToSlash(name), "/") {
+		if len(segment) == 0 || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package piperenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportImportResource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal("Failed to create temporary directory")
+	}
+
+	// clean up tmp dir
+	defer os.RemoveAll(dir)
+
+	t.Run("export", func(t *testing.T) {
+		assert.NoError(t, SetResourceParameter(dir, "commonPipelineEnvironment", "artifactVersion", "1.0.0"))
+		assert.NoError(t, SetResourceParameter(dir, "commonPipelineEnvironment", "artifactVersion", "1.0.1"))
+		assert.NoError(t, SetResourceParameter(dir, "commonPipelineEnvironment", "git/commitId", "abc"))
+		assert.NoError(t, SetResourceParameterJSON(dir, "commonPipelineEnvironment", "custom/list", []string{"a", "b"}))
+
+		values, err := ExportResource(dir, "commonPipelineEnvironment")
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"artifactVersion": "1.0.1",
+			"git/commitId":    "abc",
+			"custom/list":     json.RawMessage(`["a","b"]`),
+		}, values)
+	})
+
+	t.Run("export of missing resource", func(t *testing.T) {
+		values, err := ExportResource(dir, "influx")
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{}, values)
+	})
+
+	t.Run("export of invalid resource name", func(t *testing.T) {
+		for _, resourceName := range []string{"", "..", "../outside", "/absolute", "."} {
+			_, err := ExportResource(dir, resourceName)
+
+			assert.EqualError(t, err, fmt.Sprintf("invalid resource name '%v'", resourceName))
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		err := ImportResource(dir, "influx", map[string]interface{}{
+			"step_data/fields/build": "true",
+			"step_data/fields/count": float64(3),
+			"custom/object":          map[string]interface{}{"key": "value"},
+			"ignored":                nil,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "true", GetResourceParameter(dir, "influx", "step_data/fields/build"))
+		assert.Equal(t, "3", GetResourceParameter(dir, "influx", "step_data/fields/count"))
+		assert.Equal(t, TypeJSON, GetResourceParameterType(dir, "influx", "custom/object"))
+		assert.Equal(t, `{"key":"value"}`, GetResourceParameter(dir, "influx", "custom/object"))
+		assert.Equal(t, "", GetResourceParameter(dir, "influx", "ignored"))
+	})
+
+	t.Run("import of invalid key", func(t *testing.T) {
+		err := ImportResource(dir, "influx", map[string]interface{}{"../outside": "value"})
+
+		assert.EqualError(t, err, "invalid key '../outside' for resource influx")
+	})
+
+	t.Run("import of invalid resource name", func(t *testing.T) {
+		for _, resourceName := range []string{"", "../../outside", "/absolute"} {
+			err := ImportResource(dir, resourceName, map[string]interface{}{"key": "value"})
+
+			assert.EqualError(t, err, fmt.Sprintf("invalid resource name '%v'", resourceName))
+		}
+		_, err := os.Stat(filepath.Join(dir, "..", "..", "outside"))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestIsRelativePath(t *testing.T) {
+	for _, name := range []string{"commonPipelineEnvironment", "git/commitId", "custom/..value"} {
+		assert.True(t, IsRelativePath(name), "path '%v' is expected to be valid", name)
+	}
+	for _, name := range []string{"", "/absolute", "..", "../outside", "git/../../outside", ".", "./git", "git/.", "git//commitId", "git/"} {
+		assert.False(t, IsRelativePath(name), "path '%v' is expected to be invalid", name)
+	}
+}
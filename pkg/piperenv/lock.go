@@ -0,0 +1,39 @@
+package piperenv
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+var (
+	lockTimeout       = 30 * time.Second
+	lockRetryInterval = 10 * time.Millisecond
+	// locks older than this are considered to be left over by a terminated process
+	staleLockAge = 60 * time.Second
+)
+
+// lock creates the lock file exclusively and returns a function which releases the lock again.
+// A lock file is used instead of OS specific file locking to work the same way on all platforms.
+func lock(filename string) (func(), error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		file, err := os.OpenFile(filename, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			file.Close()
+			return func() { os.Remove(filename) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file %v: %v", filename, err)
+		}
+
+		if info, err := os.Stat(filename); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(filename)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timeout while waiting for lock file %v", filename)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
@@ -0,0 +1,53 @@
+package piperenv
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal("Failed to create temporary directory")
+	}
+
+	// clean up tmp dir
+	defer os.RemoveAll(dir)
+
+	defer func(timeout time.Duration) { lockTimeout = timeout }(lockTimeout)
+	lockTimeout = 50 * time.Millisecond
+	lockFile := filepath.Join(dir, "lock")
+
+	t.Run("lock and unlock", func(t *testing.T) {
+		unlock, err := lock(lockFile)
+		assert.NoError(t, err)
+		assert.FileExists(t, lockFile)
+		unlock()
+		_, err = os.Stat(lockFile)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		unlock, err := lock(lockFile)
+		assert.NoError(t, err)
+		defer unlock()
+
+		_, err = lock(lockFile)
+		assert.EqualError(t, err, "timeout while waiting for lock file "+lockFile)
+	})
+
+	t.Run("stale lock", func(t *testing.T) {
+		assert.NoError(t, ioutil.WriteFile(lockFile, []byte{}, 0644))
+		old := time.Now().Add(-2 * staleLockAge)
+		assert.NoError(t, os.Chtimes(lockFile, old, old))
+
+		unlock, err := lock(lockFile)
+		assert.NoError(t, err)
+		unlock()
+	})
+}
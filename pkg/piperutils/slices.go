@@ -14,6 +14,16 @@ func ContainsInt(s []int, e int) bool {
 	return false
 }
 
+//ContainsString check wether the element is part of the slice
+func ContainsString(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
+
 //Prefix adds a prefix to each element of the slice
 func Prefix(in []string, prefix string) (out []string) {
 	for _, element := range in {
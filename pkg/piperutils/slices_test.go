@@ -17,6 +17,16 @@ func TestContainsInt(t *testing.T) {
 	assert.Equal(t, false, ContainsInt(intList, 13), "False expected but returned true")
 }
 
+func TestContainsString(t *testing.T) {
+	var stringList []string
+	assert.False(t, ContainsString(stringList, "foo"))
+
+	stringList = append(stringList, "foo", "bar", "--no-confirm")
+	assert.True(t, ContainsString(stringList, "foo"))
+	assert.True(t, ContainsString(stringList, "--no-confirm"))
+	assert.False(t, ContainsString(stringList, "baz"))
+}
+
 func TestPrefix(t *testing.T) {
 	// init
 	s := []string{"tree", "pie", "applejuice"}
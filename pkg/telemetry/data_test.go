@@ -65,3 +65,12 @@ func TestDataToPayload(t *testing.T) {
 		assert.NotContains(t, result, "t€štÄçtïøñ")
 	})
 }
+
+func TestDataToEvent(t *testing.T) {
+	// init
+	testData := Data{BaseData: BaseData{StepName: "testStep"}, CustomData: CustomData{ErrorCode: "0", Custom2Label: "label", Custom2: "value"}}
+	// test
+	result := testData.toEvent()
+	// assert
+	assert.Equal(t, map[string]string{"stepName": "testStep", "errorCode": "0", "label": "value"}, result)
+}
@@ -0,0 +1,219 @@
+package telemetry
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// otlpSink sends the data as metrics and as trace span to an OpenTelemetry collector using OTLP/HTTP with JSON encoding
+type otlpSink struct {
+	client   piperhttp.Sender
+	endpoint string
+}
+
+// span status codes as defined by OTLP
+const (
+	otlpStatusOk    = 1
+	otlpStatusError = 2
+)
+
+// span kind 'internal' as defined by OTLP
+const otlpSpanKindInternal = 1
+
+const otlpScopeName = "piper"
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpDataPoint struct {
+	Attributes   []otlpKeyValue `json:"attributes"`
+	TimeUnixNano string         `json:"timeUnixNano"`
+	AsDouble     float64        `json:"asDouble"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpDataPoint `json:"dataPoints"`
+}
+
+type otlpMetric struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Unit        string    `json:"unit,omitempty"`
+	Gauge       otlpGauge `json:"gauge"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpMetricsRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpStatus struct {
+	Code int `json:"code"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTracesRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+func (o *otlpSink) Send(data *Data) error {
+	end := time.Now()
+	duration, _ := strconv.ParseInt(data.Duration, 10, 64)
+	start := end.Add(-time.Duration(duration) * time.Millisecond)
+
+	event := data.toEvent()
+	attributes := toOtlpAttributes(event)
+	resource := otlpResource{Attributes: []otlpKeyValue{
+		{Key: "service.name", Value: otlpAnyValue{StringValue: otlpScopeName}},
+		{Key: "service.namespace", Value: otlpAnyValue{StringValue: data.URL}},
+	}}
+
+	if err := o.sendMetrics(resource, attributes, end, float64(duration), data.ErrorCode); err != nil {
+		return err
+	}
+	return o.sendTrace(resource, attributes, start, end, data.StepName, data.ErrorCode)
+}
+
+func (o *otlpSink) sendMetrics(resource otlpResource, attributes []otlpKeyValue, timestamp time.Time, duration float64, errorCode string) error {
+	failed := 0.0
+	if errorCode != "0" {
+		failed = 1.0
+	}
+	timeUnixNano := strconv.FormatInt(timestamp.UnixNano(), 10)
+
+	metrics := []otlpMetric{
+		{
+			Name:        "piper.step.duration",
+			Description: "Duration of the step execution",
+			Unit:        "ms",
+			Gauge:       otlpGauge{DataPoints: []otlpDataPoint{{Attributes: attributes, TimeUnixNano: timeUnixNano, AsDouble: duration}}},
+		},
+		{
+			Name:        "piper.step.failed",
+			Description: "Indicates whether the step execution failed (1) or succeeded (0)",
+			Gauge:       otlpGauge{DataPoints: []otlpDataPoint{{Attributes: attributes, TimeUnixNano: timeUnixNano, AsDouble: failed}}},
+		},
+	}
+	request := otlpMetricsRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource:     resource,
+		ScopeMetrics: []otlpScopeMetrics{{Scope: otlpScope{Name: otlpScopeName}, Metrics: metrics}},
+	}}}
+	return o.post("/v1/metrics", request)
+}
+
+func (o *otlpSink) sendTrace(resource otlpResource, attributes []otlpKeyValue, start, end time.Time, stepName, errorCode string) error {
+	status := otlpStatusOk
+	if errorCode != "0" {
+		status = otlpStatusError
+	}
+
+	span := otlpSpan{
+		TraceID:           randomHex(16),
+		SpanID:            randomHex(8),
+		Name:              stepName,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(end.UnixNano(), 10),
+		Attributes:        attributes,
+		Status:            otlpStatus{Code: status},
+	}
+	request := otlpTracesRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   resource,
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: otlpScopeName}, Spans: []otlpSpan{span}}},
+	}}}
+	return o.post("/v1/traces", request)
+}
+
+func (o *otlpSink) post(path string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal OTLP payload")
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+
+	url := o.endpoint + path
+	log.Entry().WithField("request", url).Debug("Sending telemetry data")
+	response, err := o.client.SendRequest(http.MethodPost, url, bytes.NewReader(body), header, nil)
+	if response != nil && response.Body != nil {
+		response.Body.Close()
+	}
+	if err != nil {
+		return errors.Wrapf(err, "sending OTLP data to %v failed", url)
+	}
+	return nil
+}
+
+func toOtlpAttributes(event map[string]string) []otlpKeyValue {
+	keys := []string{}
+	for key := range event {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attributes := []otlpKeyValue{}
+	for _, key := range keys {
+		attributes = append(attributes, otlpKeyValue{Key: fmt.Sprintf("piper.%v", key), Value: otlpAnyValue{StringValue: event[key]}})
+	}
+	return attributes
+}
+
+func randomHex(length int) string {
+	b := make([]byte, length)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,59 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/stretchr/testify/assert"
+)
+
+type otlpClientMock struct {
+	urls   []string
+	bodies [][]byte
+}
+
+func (c *otlpClientMock) SetOptions(opts piperhttp.ClientOptions) {}
+
+func (c *otlpClientMock) SendRequest(method, url string, body io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	content, _ := ioutil.ReadAll(body)
+	c.urls = append(c.urls, url)
+	c.bodies = append(c.bodies, content)
+	return &http.Response{StatusCode: 200, Body: ioutil.NopCloser(bytes.NewReader([]byte("")))}, nil
+}
+
+func TestOtlpSink(t *testing.T) {
+	// init
+	clientMock := otlpClientMock{}
+	sink := otlpSink{client: &clientMock, endpoint: "http://collector:4318"}
+	// test
+	err := sink.Send(&Data{BaseData: BaseData{StepName: "testStep"}, CustomData: CustomData{Duration: "1500", ErrorCode: "1"}})
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http://collector:4318/v1/metrics", "http://collector:4318/v1/traces"}, clientMock.urls)
+
+	var metrics otlpMetricsRequest
+	if assert.NoError(t, json.Unmarshal(clientMock.bodies[0], &metrics)) {
+		result := metrics.ResourceMetrics[0].ScopeMetrics[0].Metrics
+		if assert.Equal(t, 2, len(result)) {
+			assert.Equal(t, "piper.step.duration", result[0].Name)
+			assert.Equal(t, 1500.0, result[0].Gauge.DataPoints[0].AsDouble)
+			assert.Contains(t, result[0].Gauge.DataPoints[0].Attributes, otlpKeyValue{Key: "piper.stepName", Value: otlpAnyValue{StringValue: "testStep"}})
+			assert.Equal(t, "piper.step.failed", result[1].Name)
+			assert.Equal(t, 1.0, result[1].Gauge.DataPoints[0].AsDouble)
+		}
+	}
+
+	var traces otlpTracesRequest
+	if assert.NoError(t, json.Unmarshal(clientMock.bodies[1], &traces)) {
+		span := traces.ResourceSpans[0].ScopeSpans[0].Spans[0]
+		assert.Equal(t, "testStep", span.Name)
+		assert.Equal(t, 32, len(span.TraceID))
+		assert.Equal(t, 16, len(span.SpanID))
+		assert.Equal(t, otlpStatusError, span.Status.Code)
+	}
+}
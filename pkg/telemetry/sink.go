@@ -0,0 +1,126 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// Sink defines a destination for telemetry data
+type Sink interface {
+	Send(data *Data) error
+}
+
+// SinkConfig defines which telemetry sinks are active and how they are configured
+type SinkConfig struct {
+	// Sinks contains the names of the active sinks, possible values are 'swa', 'file' and 'otlp'
+	Sinks []string
+	// FilePath is the file the 'file' sink appends its JSON lines to
+	FilePath string
+	// OtlpEndpoint is the base url of the OTLP/HTTP collector used by the 'otlp' sink
+	OtlpEndpoint string
+}
+
+const (
+	// SinkSWA sends telemetry data to the SAP Web Analytics tracker
+	SinkSWA = "swa"
+	// SinkFile appends telemetry data as JSON lines to a local file
+	SinkFile = "file"
+	// SinkOTLP sends telemetry data as metrics and traces to an OTLP/HTTP collector
+	SinkOTLP = "otlp"
+)
+
+var sinkConfig = SinkConfig{Sinks: []string{SinkSWA}}
+var sinks = []Sink{&swaSink{}}
+
+// Configure defines the sinks which are created by Initialize. Without configured sinks only SWA is used.
+func Configure(config SinkConfig) {
+	if config.Sinks == nil {
+		config.Sinks = []string{SinkSWA}
+	}
+	if len(config.OtlpEndpoint) == 0 {
+		config.OtlpEndpoint = os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+	sinkConfig = config
+}
+
+func createSinks(config SinkConfig) []Sink {
+	result := []Sink{}
+	for _, name := range config.Sinks {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case SinkSWA:
+			result = append(result, &swaSink{})
+		case SinkFile:
+			if len(config.FilePath) == 0 {
+				log.Entry().Warning("Telemetry sink 'file' requires parameter telemetryFilePath, sink ignored")
+				continue
+			}
+			result = append(result, &fileSink{path: config.FilePath})
+		case SinkOTLP:
+			if len(config.OtlpEndpoint) == 0 {
+				log.Entry().Warning("Telemetry sink 'otlp' requires parameter telemetryOtlpEndpoint, sink ignored")
+				continue
+			}
+			otlpClient := &piperhttp.Client{}
+			otlpClient.SetOptions(piperhttp.ClientOptions{MaxRequestDuration: 5 * time.Second})
+			result = append(result, &otlpSink{client: otlpClient, endpoint: strings.TrimSuffix(config.OtlpEndpoint, "/")})
+		default:
+			log.Entry().Warningf("Telemetry sink '%v' not known, sink ignored", name)
+		}
+	}
+	return result
+}
+
+// swaSink sends the data to the SAP Web Analytics tracker
+type swaSink struct{}
+
+// SWA baseURL
+const baseURL = "https://webanalytics.cfapps.eu10.hana.ondemand.com"
+
+// SWA endpoint
+const endpoint = "/tracker/log"
+
+func (s *swaSink) Send(data *Data) error {
+	request, _ := url.Parse(baseURL)
+	request.Path = endpoint
+	request.RawQuery = data.toPayloadString()
+	log.Entry().WithField("request", request.String()).Debug("Sending telemetry data")
+	_, err := client.SendRequest(http.MethodGet, request.String(), nil, nil, nil)
+	return err
+}
+
+// fileSink appends the data as JSON line to a local file
+type fileSink struct {
+	path string
+}
+
+func (f *fileSink) Send(data *Data) error {
+	event := data.toEvent()
+	event["timestamp"] = time.Now().UTC().Format(time.RFC3339)
+	line, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal telemetry event")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory for telemetry file %v", f.path)
+	}
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open telemetry file %v", f.path)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return errors.Wrapf(err, "failed to write telemetry file %v", f.path)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigure(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		// test
+		Configure(SinkConfig{})
+		// assert
+		assert.Equal(t, []string{SinkSWA}, sinkConfig.Sinks)
+	})
+
+	t.Run("otlp endpoint from environment", func(t *testing.T) {
+		// init
+		os.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+		defer os.Unsetenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+		// test
+		Configure(SinkConfig{Sinks: []string{SinkOTLP}})
+		// assert
+		assert.Equal(t, "http://collector:4318", sinkConfig.OtlpEndpoint)
+	})
+	// cleanup
+	sinkConfig = SinkConfig{Sinks: []string{SinkSWA}}
+}
+
+func TestCreateSinks(t *testing.T) {
+	t.Run("all sinks", func(t *testing.T) {
+		// test
+		result := createSinks(SinkConfig{Sinks: []string{"swa", "FILE", "otlp"}, FilePath: "telemetry.jsonl", OtlpEndpoint: "http://collector:4318/"})
+		// assert
+		if assert.Equal(t, 3, len(result)) {
+			assert.IsType(t, &swaSink{}, result[0])
+			assert.Equal(t, &fileSink{path: "telemetry.jsonl"}, result[1])
+			assert.Equal(t, "http://collector:4318", result[2].(*otlpSink).endpoint)
+		}
+	})
+
+	t.Run("incomplete and unknown sinks", func(t *testing.T) {
+		// test
+		result := createSinks(SinkConfig{Sinks: []string{"file", "otlp", "unknown"}})
+		// assert
+		assert.Equal(t, 0, len(result))
+	})
+}
+
+func TestFileSink(t *testing.T) {
+	// init
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal("Failed to create temporary directory")
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "telemetry", "events.jsonl")
+	sink := fileSink{path: path}
+	// test
+	err1 := sink.Send(&Data{BaseData: BaseData{StepName: "step1"}, CustomData: CustomData{Duration: "10", ErrorCode: "0"}})
+	err2 := sink.Send(&Data{BaseData: BaseData{StepName: "step2"}, CustomData: CustomData{ErrorCode: "1", Custom1Label: "buildTool", Custom1: "maven"}})
+	// assert
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	content, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if assert.Equal(t, 2, len(lines)) {
+		var event map[string]string
+		assert.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+		assert.Equal(t, "step1", event["stepName"])
+		assert.Equal(t, "10", event["duration"])
+		assert.Contains(t, event, "timestamp")
+
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &event))
+		assert.Equal(t, "step2", event["stepName"])
+		assert.Equal(t, "maven", event["buildTool"])
+	}
+}
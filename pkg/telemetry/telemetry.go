@@ -6,9 +6,6 @@ import (
 	"os"
 	"time"
 
-	"net/http"
-	"net/url"
-
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 )
@@ -61,8 +58,8 @@ func Initialize(telemetryDisabled bool, stepName string) {
 		PipelineURLHash: getPipelineURLHash(), // http://server:port/jenkins/job/foo/
 		BuildURLHash:    getBuildURLHash(),    // http://server:port/jenkins/job/foo/15/
 	}
-	//ToDo: register Logrus Hook
 
+	sinks = createSinks(sinkConfig)
 }
 
 func getPipelineURLHash() string {
@@ -80,13 +77,7 @@ func toSha1OrNA(input string) string {
 	return fmt.Sprintf("%x", sha1.Sum([]byte(input)))
 }
 
-// SWA baseURL
-const baseURL = "https://webanalytics.cfapps.eu10.hana.ondemand.com"
-
-// SWA endpoint
-const endpoint = "/tracker/log"
-
-// Send ...
+// Send sends the telemetry data to all configured sinks
 func Send(customData *CustomData) {
 	data := Data{
 		BaseData:     baseData,
@@ -99,9 +90,9 @@ func Send(customData *CustomData) {
 		return
 	}
 
-	request, _ := url.Parse(baseURL)
-	request.Path = endpoint
-	request.RawQuery = data.toPayloadString()
-	log.Entry().WithField("request", request.String()).Debug("Sending telemetry data")
-	client.SendRequest(http.MethodGet, request.String(), nil, nil, nil)
+	for _, sink := range sinks {
+		if err := sink.Send(&data); err != nil {
+			log.Entry().WithError(err).Debug("Sending telemetry data failed")
+		}
+	}
 }
@@ -83,6 +83,33 @@ func TestSend(t *testing.T) {
 		assert.Contains(t, mock.urlsCalled, "action_name=testAction")
 	})
 }
+
+type sinkMock struct {
+	data []Data
+}
+
+func (s *sinkMock) Send(data *Data) error {
+	s.data = append(s.data, *data)
+	return nil
+}
+
+func TestSendToSinks(t *testing.T) {
+	// init
+	sink1 := sinkMock{}
+	sink2 := sinkMock{}
+	sinks = []Sink{&sink1, &sink2}
+	disabled = false
+	baseData = BaseData{StepName: "testStep"}
+	// test
+	Send(&CustomData{ErrorCode: "0"})
+	// assert
+	assert.Equal(t, 1, len(sink1.data))
+	assert.Equal(t, 1, len(sink2.data))
+	assert.Equal(t, "testStep", sink2.data[0].StepName)
+	// cleanup
+	sinks = []Sink{&swaSink{}}
+}
+
 func TestEnvVars(t *testing.T) {
 	t.Run("without values", func(t *testing.T) {
 		// init
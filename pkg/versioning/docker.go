@@ -0,0 +1,98 @@
+package versioning
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// dockerVersionLabelPattern matches the version label of a Dockerfile
+var dockerVersionLabelPattern = regexp.MustCompile(`(?mi)^LABEL[ \t]+version=.*$`)
+
+// Docker reads the version of a Dockerfile from the tag of the base image or from an environment variable
+// and writes the version as label of the image
+type Docker struct {
+	path          string
+	versionSource string
+	utils         Utils
+}
+
+// GetVersion returns the version according to the version source
+func (d *Docker) GetVersion() (string, error) {
+	content, err := d.utils.FileRead(d.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %v: %w", d.path, err)
+	}
+
+	var version string
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		instruction := strings.ToUpper(fields[0])
+		if (len(d.versionSource) == 0 || d.versionSource == "FROM") && instruction == "FROM" {
+			// the version of the last stage is used
+			version = dockerImageTag(fields[1])
+		}
+		if len(d.versionSource) > 0 && d.versionSource != "FROM" && instruction == "ENV" {
+			if value, ok := dockerEnvValue(fields[1:], d.versionSource); ok {
+				version = value
+			}
+		}
+	}
+
+	if len(version) == 0 {
+		return "", fmt.Errorf("no version found in %v for version source '%v'", d.path, d.versionSource)
+	}
+	return version, nil
+}
+
+// SetVersion sets the version label of the image, an existing version label is replaced
+func (d *Docker) SetVersion(version string) error {
+	content, err := d.utils.FileRead(d.path)
+	if err != nil {
+		return fmt.Errorf("failed to read %v: %w", d.path, err)
+	}
+	label := fmt.Sprintf("LABEL version=%q", version)
+	if dockerVersionLabelPattern.Match(content) {
+		content = dockerVersionLabelPattern.ReplaceAllLiteral(content, []byte(label))
+	} else {
+		if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+			content = append(content, '\n')
+		}
+		content = append(content, []byte(label+"\n")...)
+	}
+	if err := d.utils.FileWrite(d.path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %v: %w", d.path, err)
+	}
+	return nil
+}
+
+// dockerImageTag returns the tag of an image reference like registry:5000/image:tag@sha256:digest
+func dockerImageTag(image string) string {
+	image = strings.SplitN(image, "@", 2)[0]
+	lastSlash := strings.LastIndex(image, "/")
+	if i := strings.LastIndex(image, ":"); i > lastSlash {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// dockerEnvValue returns the value of the variable from the arguments of an ENV instruction,
+// which are either "name value" or "name=value ..."
+func dockerEnvValue(arguments []string, name string) (string, bool) {
+	if !strings.Contains(arguments[0], "=") {
+		if arguments[0] == name && len(arguments) > 1 {
+			return strings.Trim(strings.Join(arguments[1:], " "), `"`), true
+		}
+		return "", false
+	}
+	for _, argument := range arguments {
+		parts := strings.SplitN(argument, "=", 2)
+		if len(parts) == 2 && parts[0] == name {
+			return strings.Trim(parts[1], `"`), true
+		}
+	}
+	return "", false
+}
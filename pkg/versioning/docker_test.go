@@ -0,0 +1,75 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDockerfile = `FROM golang:1.13 AS build
+RUN go build
+
+FROM my.registry.io:5000/base/image:2.4.1
+ENV APP_VERSION 1.2.3
+ENV OTHER=a VERSION="3.2.1"
+LABEL version="0.0.1"
+`
+
+func TestDocker(t *testing.T) {
+	t.Run("version of base image", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.files["Dockerfile"] = []byte(testDockerfile)
+		artifact := Docker{path: "Dockerfile", versionSource: "FROM", utils: utils}
+
+		version, err := artifact.GetVersion()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2.4.1", version)
+	})
+
+	t.Run("version of environment variable", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.files["Dockerfile"] = []byte(testDockerfile)
+
+		version, err := (&Docker{path: "Dockerfile", versionSource: "APP_VERSION", utils: utils}).GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+
+		version, err = (&Docker{path: "Dockerfile", versionSource: "VERSION", utils: utils}).GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "3.2.1", version)
+	})
+
+	t.Run("missing version", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.files["Dockerfile"] = []byte("FROM scratch\n")
+		artifact := Docker{path: "Dockerfile", versionSource: "FROM", utils: utils}
+
+		_, err := artifact.GetVersion()
+
+		assert.EqualError(t, err, "no version found in Dockerfile for version source 'FROM'")
+	})
+
+	t.Run("replace version label", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.files["Dockerfile"] = []byte(testDockerfile)
+		artifact := Docker{path: "Dockerfile", versionSource: "FROM", utils: utils}
+
+		err := artifact.SetVersion("2.4.1-20200101120000")
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(utils.files["Dockerfile"]), "\nLABEL version=\"2.4.1-20200101120000\"\n")
+		assert.NotContains(t, string(utils.files["Dockerfile"]), "0.0.1")
+	})
+
+	t.Run("add version label", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.files["Dockerfile"] = []byte("FROM alpine:3.12")
+		artifact := Docker{path: "Dockerfile", versionSource: "FROM", utils: utils}
+
+		err := artifact.SetVersion("3.12-20200101120000")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "FROM alpine:3.12\nLABEL version=\"3.12-20200101120000\"\n", string(utils.files["Dockerfile"]))
+	})
+}
@@ -0,0 +1,40 @@
+package versioning
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/maven"
+)
+
+// Maven reads and writes the version of a pom.xml via Maven
+type Maven struct {
+	pomPath string
+	options *Options
+	utils   Utils
+}
+
+// GetVersion returns the project version as evaluated by Maven
+func (m *Maven) GetVersion() (string, error) {
+	version, err := maven.Evaluate(m.pomPath, "project.version", m.utils)
+	if err != nil {
+		return "", fmt.Errorf("failed to read version of %v: %w", m.pomPath, err)
+	}
+	return strings.TrimSpace(version), nil
+}
+
+// SetVersion updates the version of the project and its modules via the versions-maven-plugin
+func (m *Maven) SetVersion(version string) error {
+	options := maven.ExecuteOptions{
+		PomPath:             m.pomPath,
+		ProjectSettingsFile: m.options.ProjectSettingsFile,
+		GlobalSettingsFile:  m.options.GlobalSettingsFile,
+		M2Path:              m.options.M2Path,
+		Goals:               []string{"org.codehaus.mojo:versions-maven-plugin:2.7:set"},
+		Defines:             []string{"-DnewVersion=" + version, "-DgenerateBackupPoms=false"},
+	}
+	if _, err := maven.Execute(&options, m.utils); err != nil {
+		return fmt.Errorf("failed to set version of %v: %w", m.pomPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaven(t *testing.T) {
+	t.Run("get version", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.StdoutReturn = map[string]string{"help-plugin": "1.2.3-SNAPSHOT"}
+		artifact := Maven{pomPath: "pom.xml", options: &Options{}, utils: utils}
+
+		version, err := artifact.GetVersion()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3-SNAPSHOT", version)
+		assert.Contains(t, utils.Calls[0].Params, "-Dexpression=project.version")
+	})
+
+	t.Run("set version", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		artifact := Maven{pomPath: "pom.xml", options: &Options{M2Path: ".m2"}, utils: utils}
+
+		err := artifact.SetVersion("1.2.3-20200101120000")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []mock.ExecCall{{Exec: "mvn", Params: []string{
+			"-Dmaven.repo.local=.m2",
+			"--file", "pom.xml",
+			"-DnewVersion=1.2.3-20200101120000", "-DgenerateBackupPoms=false",
+			"--batch-mode",
+			"org.codehaus.mojo:versions-maven-plugin:2.7:set",
+		}}}, utils.Calls)
+	})
+}
@@ -0,0 +1,51 @@
+package versioning
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/ghodss/yaml"
+)
+
+// mtaVersionPattern matches the top level version of an mta.yaml
+var mtaVersionPattern = regexp.MustCompile(`(?m)^(version:[ \t]*["']?)([^"'\s#]*)(["']?)`)
+
+// Mta reads and writes the version of an mta.yaml
+type Mta struct {
+	path  string
+	utils Utils
+}
+
+// GetVersion returns the version of the multi-target application
+func (m *Mta) GetVersion() (string, error) {
+	content, err := m.utils.FileRead(m.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %v: %w", m.path, err)
+	}
+	var descriptor struct {
+		Version string `json:"version"`
+	}
+	if err := yaml.Unmarshal(content, &descriptor); err != nil {
+		return "", fmt.Errorf("failed to parse %v: %w", m.path, err)
+	}
+	if len(descriptor.Version) == 0 {
+		return "", fmt.Errorf("no version defined in %v", m.path)
+	}
+	return descriptor.Version, nil
+}
+
+// SetVersion replaces the version of the multi-target application, the formatting of the file is retained
+func (m *Mta) SetVersion(version string) error {
+	content, err := m.utils.FileRead(m.path)
+	if err != nil {
+		return fmt.Errorf("failed to read %v: %w", m.path, err)
+	}
+	if !mtaVersionPattern.Match(content) {
+		return fmt.Errorf("no version defined in %v", m.path)
+	}
+	content = mtaVersionPattern.ReplaceAll(content, []byte("${1}"+version+"${3}"))
+	if err := m.utils.FileWrite(m.path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %v: %w", m.path, err)
+	}
+	return nil
+}
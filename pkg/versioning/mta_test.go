@@ -0,0 +1,52 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testMtaYaml = `_schema-version: "3.1"
+ID: my-mta
+version: '1.2.3' # managed by the pipeline
+modules:
+  - name: app
+    type: nodejs
+    parameters:
+      version: 4.5.6
+`
+
+func TestMta(t *testing.T) {
+	t.Run("get version", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.files["mta.yaml"] = []byte(testMtaYaml)
+		artifact := Mta{path: "mta.yaml", utils: utils}
+
+		version, err := artifact.GetVersion()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("set version", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.files["mta.yaml"] = []byte(testMtaYaml)
+		artifact := Mta{path: "mta.yaml", utils: utils}
+
+		err := artifact.SetVersion("1.2.3-20200101120000")
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(utils.files["mta.yaml"]), "version: '1.2.3-20200101120000' # managed by the pipeline\n")
+		assert.Contains(t, string(utils.files["mta.yaml"]), "      version: 4.5.6\n")
+	})
+
+	t.Run("missing version", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.files["mta.yaml"] = []byte("ID: my-mta\n")
+		artifact := Mta{path: "mta.yaml", utils: utils}
+
+		err := artifact.SetVersion("1.2.3")
+
+		assert.EqualError(t, err, "no version defined in mta.yaml")
+	})
+}
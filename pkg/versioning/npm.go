@@ -0,0 +1,63 @@
+package versioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// npmVersionPattern matches version attributes within a package.json
+var npmVersionPattern = regexp.MustCompile(`("version"\s*:\s*")([^"]*)(")`)
+
+// Npm reads and writes the version of a package.json
+type Npm struct {
+	path  string
+	utils Utils
+}
+
+// GetVersion returns the version of the package
+func (n *Npm) GetVersion() (string, error) {
+	content, err := n.utils.FileRead(n.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %v: %w", n.path, err)
+	}
+	var descriptor struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(content, &descriptor); err != nil {
+		return "", fmt.Errorf("failed to parse %v: %w", n.path, err)
+	}
+	if len(descriptor.Version) == 0 {
+		return "", fmt.Errorf("no version defined in %v", n.path)
+	}
+	return descriptor.Version, nil
+}
+
+// SetVersion replaces the version of the package, the formatting of the file is retained
+func (n *Npm) SetVersion(version string) error {
+	current, err := n.GetVersion()
+	if err != nil {
+		return err
+	}
+	content, err := n.utils.FileRead(n.path)
+	if err != nil {
+		return fmt.Errorf("failed to read %v: %w", n.path, err)
+	}
+
+	// nested objects like dependencies may contain version attributes as well,
+	// the first one with the version of the package is assumed to be the one of the package
+	replaced := false
+	content = npmVersionPattern.ReplaceAllFunc(content, func(match []byte) []byte {
+		parts := npmVersionPattern.FindSubmatch(match)
+		if replaced || string(parts[2]) != current {
+			return match
+		}
+		replaced = true
+		return []byte(string(parts[1]) + version + string(parts[3]))
+	})
+
+	if err := n.utils.FileWrite(n.path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %v: %w", n.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPackageJSON = `{
+  "name": "my-app",
+  "dependencies": {
+    "lib": {"version": "2.0.0"}
+  },
+  "version": "1.2.3",
+  "devDependencies": {
+    "other": {"version": "1.2.3"}
+  }
+}
+`
+
+func TestNpm(t *testing.T) {
+	t.Run("get version", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.files["package.json"] = []byte(testPackageJSON)
+		artifact := Npm{path: "package.json", utils: utils}
+
+		version, err := artifact.GetVersion()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("set version", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.files["package.json"] = []byte(testPackageJSON)
+		artifact := Npm{path: "package.json", utils: utils}
+
+		err := artifact.SetVersion("1.2.3-20200101120000")
+
+		assert.NoError(t, err)
+		assert.Equal(t, `{
+  "name": "my-app",
+  "dependencies": {
+    "lib": {"version": "2.0.0"}
+  },
+  "version": "1.2.3-20200101120000",
+  "devDependencies": {
+    "other": {"version": "1.2.3"}
+  }
+}
+`, string(utils.files["package.json"]))
+	})
+
+	t.Run("missing version", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.files["package.json"] = []byte(`{"name": "my-app"}`)
+		artifact := Npm{path: "package.json", utils: utils}
+
+		_, err := artifact.GetVersion()
+
+		assert.EqualError(t, err, "no version defined in package.json")
+	})
+}
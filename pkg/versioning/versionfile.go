@@ -0,0 +1,33 @@
+package versioning
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VersionFile reads and writes a file which only contains the version, like the VERSION file of Go projects
+type VersionFile struct {
+	path  string
+	utils Utils
+}
+
+// GetVersion returns the content of the file
+func (v *VersionFile) GetVersion() (string, error) {
+	content, err := v.utils.FileRead(v.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %v: %w", v.path, err)
+	}
+	version := strings.TrimSpace(string(content))
+	if len(version) == 0 {
+		return "", fmt.Errorf("no version defined in %v", v.path)
+	}
+	return version, nil
+}
+
+// SetVersion replaces the content of the file
+func (v *VersionFile) SetVersion(version string) error {
+	if err := v.utils.FileWrite(v.path, []byte(version+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write %v: %w", v.path, err)
+	}
+	return nil
+}
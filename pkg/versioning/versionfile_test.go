@@ -0,0 +1,40 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionFile(t *testing.T) {
+	t.Run("get version", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.files["VERSION"] = []byte("1.2.3\n")
+		artifact := VersionFile{path: "VERSION", utils: utils}
+
+		version, err := artifact.GetVersion()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("set version", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		artifact := VersionFile{path: "VERSION", utils: utils}
+
+		err := artifact.SetVersion("1.2.3-20200101120000")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3-20200101120000\n", string(utils.files["VERSION"]))
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.files["VERSION"] = []byte("\n")
+		artifact := VersionFile{path: "VERSION", utils: utils}
+
+		_, err := artifact.GetVersion()
+
+		assert.EqualError(t, err, "no version defined in VERSION")
+	})
+}
@@ -0,0 +1,102 @@
+package versioning
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// Artifact provides access to the version of a build descriptor
+type Artifact interface {
+	GetVersion() (string, error)
+	SetVersion(version string) error
+}
+
+// Options contains build tool specific options for the versioning
+type Options struct {
+	// DockerVersionSource defines whether the version of a Dockerfile is taken from the tag of the base image (FROM)
+	// or from the environment variable with the given name
+	DockerVersionSource string
+	ProjectSettingsFile string
+	GlobalSettingsFile  string
+	M2Path              string
+}
+
+// Utils provides the file access and the command execution needed for reading and writing versions
+type Utils interface {
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+	Stdout(out io.Writer)
+	Stderr(err io.Writer)
+	RunExecutable(e string, p ...string) error
+}
+
+// DefaultDescriptorFile returns the default build descriptor of the build tool
+func DefaultDescriptorFile(buildTool string) string {
+	switch buildTool {
+	case "docker":
+		return "Dockerfile"
+	case "golang":
+		return "VERSION"
+	case "maven":
+		return "pom.xml"
+	case "mta":
+		return "mta.yaml"
+	case "npm":
+		return "package.json"
+	}
+	return ""
+}
+
+// GetArtifact returns the versioning of the build descriptor of the build tool.
+// The default descriptor of the build tool is used if descriptorFile is empty.
+func GetArtifact(buildTool, descriptorFile string, options *Options, utils Utils) (Artifact, error) {
+	if len(descriptorFile) == 0 {
+		descriptorFile = DefaultDescriptorFile(buildTool)
+	}
+	switch buildTool {
+	case "docker":
+		return &Docker{path: descriptorFile, versionSource: options.DockerVersionSource, utils: utils}, nil
+	case "golang":
+		return &VersionFile{path: descriptorFile, utils: utils}, nil
+	case "maven":
+		return &Maven{pomPath: descriptorFile, options: options, utils: utils}, nil
+	case "mta":
+		return &Mta{path: descriptorFile, utils: utils}, nil
+	case "npm":
+		return &Npm{path: descriptorFile, utils: utils}, nil
+	}
+	return nil, fmt.Errorf("build tool '%v' not supported", buildTool)
+}
+
+// VersionData is available within the template of the automatic version
+type VersionData struct {
+	// Version is the version of the descriptor without pre-release and build metadata, e.g. 1.2.3 for 1.2.3-SNAPSHOT
+	Version   string
+	Timestamp string
+	CommitID  string
+}
+
+// BaseVersion returns the version without pre-release and build metadata
+func BaseVersion(version string) string {
+	if i := strings.IndexAny(version, "-+"); i > 0 {
+		return version[:i]
+	}
+	return version
+}
+
+// AutomaticVersion renders the template of the automatic version, e.g. {{.Version}}-{{.Timestamp}}{{with .CommitID}}+{{.}}{{end}}
+func AutomaticVersion(versioningTemplate string, data VersionData) (string, error) {
+	tmpl, err := template.New("version").Parse(versioningTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse versioning template: %w", err)
+	}
+	var version bytes.Buffer
+	if err := tmpl.Execute(&version, data); err != nil {
+		return "", fmt.Errorf("failed to render versioning template: %w", err)
+	}
+	return version.String(), nil
+}
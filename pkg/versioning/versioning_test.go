@@ -0,0 +1,89 @@
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type versioningMockUtils struct {
+	mock.ExecMockRunner
+	files map[string][]byte
+}
+
+func newVersioningMockUtils() *versioningMockUtils {
+	return &versioningMockUtils{files: map[string][]byte{}}
+}
+
+func (m *versioningMockUtils) FileRead(path string) ([]byte, error) {
+	content, ok := m.files[path]
+	if !ok {
+		return nil, fmt.Errorf("could not read '%s'", path)
+	}
+	return content, nil
+}
+
+func (m *versioningMockUtils) FileWrite(path string, content []byte, _ os.FileMode) error {
+	m.files[path] = content
+	return nil
+}
+
+func TestGetArtifact(t *testing.T) {
+	utils := newVersioningMockUtils()
+	options := &Options{DockerVersionSource: "FROM"}
+
+	tt := []struct {
+		buildTool      string
+		descriptorFile string
+		expected       Artifact
+	}{
+		{buildTool: "docker", expected: &Docker{path: "Dockerfile", versionSource: "FROM", utils: utils}},
+		{buildTool: "golang", expected: &VersionFile{path: "VERSION", utils: utils}},
+		{buildTool: "maven", descriptorFile: "app/pom.xml", expected: &Maven{pomPath: "app/pom.xml", options: options, utils: utils}},
+		{buildTool: "mta", expected: &Mta{path: "mta.yaml", utils: utils}},
+		{buildTool: "npm", expected: &Npm{path: "package.json", utils: utils}},
+	}
+	for _, test := range tt {
+		t.Run(test.buildTool, func(t *testing.T) {
+			artifact, err := GetArtifact(test.buildTool, test.descriptorFile, options, utils)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, artifact)
+		})
+	}
+
+	t.Run("not supported", func(t *testing.T) {
+		_, err := GetArtifact("sbt", "", options, utils)
+		assert.EqualError(t, err, "build tool 'sbt' not supported")
+	})
+}
+
+func TestBaseVersion(t *testing.T) {
+	assert.Equal(t, "1.2.3", BaseVersion("1.2.3"))
+	assert.Equal(t, "1.2.3", BaseVersion("1.2.3-SNAPSHOT"))
+	assert.Equal(t, "1.2.3", BaseVersion("1.2.3-20200101120000+abc"))
+	assert.Equal(t, "1.2.3", BaseVersion("1.2.3+abc"))
+}
+
+func TestAutomaticVersion(t *testing.T) {
+	versioningTemplate := "{{.Version}}-{{.Timestamp}}{{with .CommitID}}+{{.}}{{end}}"
+
+	t.Run("with commit", func(t *testing.T) {
+		version, err := AutomaticVersion(versioningTemplate, VersionData{Version: "1.2.3", Timestamp: "20200101120000", CommitID: "abc"})
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3-20200101120000+abc", version)
+	})
+
+	t.Run("without commit", func(t *testing.T) {
+		version, err := AutomaticVersion(versioningTemplate, VersionData{Version: "1.2.3", Timestamp: "20200101120000"})
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3-20200101120000", version)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := AutomaticVersion("{{.Version", VersionData{})
+		assert.Contains(t, err.Error(), "failed to parse versioning template")
+	})
+}
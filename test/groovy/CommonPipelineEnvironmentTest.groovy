@@ -79,7 +79,19 @@ class CommonPipelineEnvironmentTest extends BasePiperTest {
             '.pipeline/commonPipelineEnvironment/custom/custom1',
         ])
 
-        nullScript.metaClass.findFiles {
+        nullScript.metaClass.findFiles { Map m ->
+            if (m.glob.startsWith('.pipeline/commonPipelineEnvironment/container/')) {
+                return [
+                    [
+                        'getName': {'image'},
+                        'getPath': {'.pipeline/commonPipelineEnvironment/container/image'},
+                    ],
+                    [
+                        'getName': {'.image.history'},
+                        'getPath': {'.pipeline/commonPipelineEnvironment/container/.image.history'},
+                    ],
+                ]
+            }
             [
                 [
                     'getName': {'custom1'},
@@ -90,12 +102,15 @@ class CommonPipelineEnvironmentTest extends BasePiperTest {
 
         readFileRule.files.putAll([
             '.pipeline/commonPipelineEnvironment/artifactVersion': '1.0.0',
+            '.pipeline/commonPipelineEnvironment/container/image': 'my.registry.io/image:1.0',
             '.pipeline/commonPipelineEnvironment/custom': 'customVal1',
         ])
 
         nullScript.commonPipelineEnvironment.readFromDisk(nullScript)
 
         assertThat(nullScript.commonPipelineEnvironment.artifactVersion, is('1.0.0'))
+        assertThat(nullScript.commonPipelineEnvironment.getContainerProperty('image'), is('my.registry.io/image:1.0'))
+        assertThat(nullScript.commonPipelineEnvironment.containerProperties.keySet(), is(['image'] as Set))
         assertThat(nullScript.commonPipelineEnvironment.valueMap['custom1'], is('customVal1'))
     }
 
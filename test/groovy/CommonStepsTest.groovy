@@ -64,6 +64,7 @@ public class CommonStepsTest extends BasePiperTest{
 
         List steps = getSteps().stream()
             .filter {! whitelistScriptReference.contains(it)}
+            .filter {! isGeneratedStep(it)}
             .forEach {checkReference(it)}
     }
 
@@ -167,12 +168,19 @@ public class CommonStepsTest extends BasePiperTest{
             stepsWithoutParametersKeySet, is(empty()))
     }
 
+    /*
+     * Steps generated from the step metadata implement the golang pattern without fields.
+     */
+    private static boolean isGeneratedStep(step) {
+        new File("vars/${step}.groovy").text.startsWith("// Code generated by piper's step-generator. DO NOT EDIT.")
+    }
+
     private fieldCheck(fieldName, whitelist) {
 
         def stepsWithoutGeneralConfigKeySet = []
 
         for(def step in getSteps()) {
-            if(whitelist.contains(step)) continue
+            if(whitelist.contains(step) || isGeneratedStep(step)) continue
 
             def fields = loadScript("${step}.groovy").getClass().getDeclaredFields() as Set
             Field generalConfigKeyField = fields.find{ it.getName() == fieldName}
@@ -206,7 +206,16 @@ class commonPipelineEnvironment implements Serializable {
             }
         })
 
+        // hidden entries contain the history of the values maintained by the piper binary
+        def containerValues = script.findFiles(glob: '.pipeline/commonPipelineEnvironment/container/*')
+            .findAll({f -> !f.getName().startsWith('.')})
+
+        containerValues.each({f ->
+            containerProperties[f.getName()] = script.readFile(f.getPath())
+        })
+
         def customValues = script.findFiles(glob: '.pipeline/commonPipelineEnvironment/custom/*')
+            .findAll({f -> !f.getName().startsWith('.')})
 
         customValues.each({f ->
             def fileName = f.getName()
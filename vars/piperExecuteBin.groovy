@@ -23,7 +23,8 @@ void call(Map parameters = [:], stepName, metadataFile, List credentialInfo, fai
         utils.unstash('pipelineConfigAndTests')
         script.commonPipelineEnvironment.writeToDisk(script)
 
-        writeFile(file: ".pipeline/tmp/${metadataFile}", text: libraryResource(metadataFile))
+        String metadata = libraryResource(metadataFile)
+        writeFile(file: ".pipeline/tmp/${metadataFile}", text: metadata)
 
         withEnv([
             "PIPER_parametersJSON=${groovy.json.JsonOutput.toJson(stepParameters)}",
@@ -39,10 +40,18 @@ void call(Map parameters = [:], stepName, metadataFile, List credentialInfo, fai
                 }
                 jenkinsUtils.handleStepResults(stepName, failOnMissingReports, failOnMissingLinks)
             }
+            // make values written by the step available to subsequent Groovy steps
+            if (hasPipelineEnvironmentOutput(readYaml(text: metadata))) {
+                script.commonPipelineEnvironment.readFromDisk(script)
+            }
         }
     }
 }
 
+boolean hasPipelineEnvironmentOutput(Map metadata) {
+    return metadata?.spec?.outputs?.resources?.any { resource -> resource.type == 'piperEnvironment' } ?: false
+}
+
 void dockerWrapper(script, config, body) {
     if (config.dockerImage) {
         dockerExecute(